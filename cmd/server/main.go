@@ -12,10 +12,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
 	"agent-hub/internal/config"
 	contentHandler "agent-hub/internal/content/handler"
 	contentRepo "agent-hub/internal/content/repository"
@@ -25,20 +28,30 @@ import (
 	interactionService "agent-hub/internal/interaction/service"
 	"agent-hub/internal/model"
 	"agent-hub/internal/middleware"
+	moderationHandler "agent-hub/internal/moderation/handler"
+	moderationRepo "agent-hub/internal/moderation/repository"
+	moderationService "agent-hub/internal/moderation/service"
 	notificationHandler "agent-hub/internal/notification/handler"
 	notificationRepo "agent-hub/internal/notification/repository"
 	notificationService "agent-hub/internal/notification/service"
+	pointsHandler "agent-hub/internal/points/handler"
 	pointsRepo "agent-hub/internal/points/repository"
 	pointsService "agent-hub/internal/points/service"
+	"agent-hub/internal/quality"
 	rankingHandler "agent-hub/internal/ranking/handler"
 	rankingRepo "agent-hub/internal/ranking/repository"
 	rankingService "agent-hub/internal/ranking/service"
+	recommendHandler "agent-hub/internal/recommend/handler"
+	recommendRepo "agent-hub/internal/recommend/repository"
+	recommendService "agent-hub/internal/recommend/service"
 	searchHandler "agent-hub/internal/search/handler"
 	searchRepo "agent-hub/internal/search/repository"
 	searchService "agent-hub/internal/search/service"
 	userHandler "agent-hub/internal/user/handler"
 	userRepo "agent-hub/internal/user/repository"
 	userService "agent-hub/internal/user/service"
+	"agent-hub/pkg/ratelimit"
+	"agent-hub/pkg/sensitive"
 )
 
 func main() {
@@ -74,62 +87,151 @@ func main() {
 	// 确保存在默认社区（发帖需要 community_id）
 	seedDefaultCommunity(db)
 
-	// Redis 可在后续注入到需要缓存的 service
-	_ = cfg.Redis
+	// Redis（未配置或不可达时为 nil，相关功能回退到进程内实现）
+	rdb := openRedis(cfg.Redis)
+	if rdb != nil {
+		defer rdb.Close()
+	}
 
 	// Repositories
 	userRepository := userRepo.NewUserRepository(db)
 	agentRepository := userRepo.NewAgentRepository(db)
+	tagRepository := userRepo.NewTagRepository(db)
 	userPointsRepo := userRepo.NewPointsRepository(db)
 	postRepository := contentRepo.NewPostRepository(db)
 	commentRepository := contentRepo.NewCommentRepository(db)
 	communityRepository := contentRepo.NewCommunityRepository(db)
 	voteRepository := interactionRepo.NewVoteRepository(db)
 	followRepository := interactionRepo.NewFollowRepository(db)
+	moderatorRepository := contentRepo.NewModeratorRepository(db)
+	subscriptionRepository := interactionRepo.NewSubscriptionRepository(db)
 	pointsRepository := pointsRepo.NewPointsRepository(db)
 	rankingRepository := rankingRepo.NewRankingRepository(db)
 	notificationRepository := notificationRepo.NewNotificationRepository(db)
 
+	// Cache（cache-aside，Redis 不可用时直接回源 MySQL）
+	appCache := cache.New(rdb, cfg.Redis.CacheTTL)
+	agentCache := cache.NewAgentCache(appCache, agentRepository)
+	postCache := cache.NewPostCache(appCache, postRepository)
+	leaderboardCache := cache.NewLeaderboardCache(appCache, rankingRepository)
+
+	// Notification Service（通知模块）：用户模块锁定账号时也需要发通知，先于用户模块创建
+	notificationSvc := notificationService.NewNotificationService(notificationRepository)
+
+	// Points Service（积分模块）：用户模块发放完善资料奖励时也需要记积分，先于用户模块创建
+	// 积分规则：内置缺省规则叠加积分规则表，按 points.rules_reload_interval 定时重新加载
+	pointsSvc := pointsService.NewPointsService(pointsRepository, pointsRepo.NewRuleRepository(db))
+	if err := pointsSvc.Reload(context.Background()); err != nil {
+		log.Printf("load points rules: %v", err)
+	}
+
 	// User Service（用户模块）
-	userSvc := userService.NewUserService(userRepository, agentRepository, userPointsRepo)
 	jwtSecret := []byte(cfg.JWT.Secret)
 	if len(jwtSecret) == 0 {
 		jwtSecret = []byte("dev-secret-change-in-production")
 	}
-	authHandler := userHandler.NewAuthHandler(userSvc, jwtSecret, cfg.JWT.ExpireHours)
-	agentHandler := userHandler.NewAgentHandler(userSvc, jwtSecret, cfg.JWT.ExpireHours)
+	// 已吊销的 access token / 会话，Redis 可用时多实例共享
+	denylist := cache.NewDenylist(appCache)
+	sessionSvc := userService.NewSessionService(userRepo.NewRefreshTokenRepository(db), userRepository, agentRepository, denylist, jwtSecret, cfg.JWT)
+	// 敏感词过滤：词表按 sensitive.reload_interval 定时重新加载，命中送审词的内容以系统身份进入审核队列
+	sensitiveFilter := sensitive.NewFilter()
+	if err := sensitiveFilter.ReloadDir(cfg.Sensitive.Dir); err != nil {
+		log.Printf("load sensitive words: %v", err)
+	}
+	reportRepository := moderationRepo.NewReportRepository(db)
+	flagger := moderationService.NewSystemFlagger(reportRepository)
+	// 登录失败按邮箱与 IP 计数，退避后锁定账号并通知所有者；Redis 可用时多实例共享计数
+	loginGuard := userService.NewLoginGuard(cache.NewLoginAttempts(appCache), agentRepository, notificationSvc, cfg.Login)
+	// 创始 Agent 窗口：前 founding.max_agents 个 / founding.until 之前创建的 Agent
+	foundingWindow, err := userService.ParseFoundingWindow(cfg.Founding)
+	if err != nil {
+		log.Printf("founding: %v", err)
+	}
+	userSvc := userService.NewUserService(userRepository, agentRepository, tagRepository, userPointsRepo, pointsSvc, foundingWindow, agentCache, leaderboardCache, sessionSvc, sensitiveFilter, flagger, loginGuard)
+	authHandler := userHandler.NewAuthHandler(userSvc, sessionSvc)
+	adminHandler := userHandler.NewAdminHandler(userSvc)
+	if email := cfg.RBAC.OwnerEmail; email != "" {
+		if found, err := userSvc.EnsureOwner(context.Background(), email); err != nil {
+			log.Printf("ensure owner: %v", err)
+		} else if !found {
+			log.Printf("rbac.owner_email %s not registered yet, restart after registering to grant owner", email)
+		}
+	}
 
-	// Points Service（积分模块）
-	pointsSvc := pointsService.NewPointsService(pointsRepository)
+	// Points Handler（积分明细与规则管理）
+	pointsHdl := pointsHandler.NewPointsHandler(pointsSvc)
+	pointsRuleHdl := pointsHandler.NewRuleHandler(pointsSvc)
 
-	// Notification Service（通知模块）
-	notificationSvc := notificationService.NewNotificationService(notificationRepository)
+	// Activity Service（每日活跃）：每天首次认证请求发放每日登录积分，记录连续活跃天数
+	streakBonuses, err := userService.ParseStreakBonuses(cfg.Activity.StreakBonuses)
+	if err != nil {
+		log.Printf("activity.streak_bonuses: %v", err)
+	}
+	activitySvc := userService.NewActivityService(agentRepository, pointsSvc, agentCache, leaderboardCache, streakBonuses)
+
+	// Ranking Service（排名模块）：Top/热搜榜单由定时任务预计算
+	var randomPool rankingRepo.RandomPool = rankingRepo.NewMemoryRandomPool()
+	if rdb != nil {
+		randomPool = rankingRepo.NewRedisRandomPool(rdb)
+	}
+	rankingSvc := rankingService.NewRankingService(rankingRepository, postRepository, rankingRepo.NewMemoryScoreStore(), randomPool, leaderboardCache, cfg.Ranking)
+	leaderboardHandler := rankingHandler.NewLeaderboardHandler(rankingSvc)
+	hotHandler := rankingHandler.NewHotHandler(rankingSvc)
 
 	// Content Service（内容模块）
-	contentSvc := contentService.NewContentService(postRepository, commentRepository, communityRepository, pointsSvc, notificationSvc)
+	checker := authz.NewChecker(moderatorRepository)
+	// 封禁检查：认证中间件拒绝完全封禁的 Agent，内容与互动服务按封禁范围拦截写操作
+	suspensionSvc := moderationService.NewSuspensionService(moderationRepo.NewSuspensionRepository(db), agentRepository)
+	// 质量检测：低质、重复内容不加分，近似重复的内容以系统身份送审
+	qualityPipeline := quality.NewDefaultPipeline(contentService.NewRecentContent(postRepository, commentRepository))
+	contentSvc := contentService.NewContentService(postRepository, commentRepository, communityRepository, pointsSvc, notificationSvc, rankingSvc, postCache, agentCache, leaderboardCache, moderatorRepository, checker, suspensionSvc, qualityPipeline, flagger, sensitiveFilter)
 	postHandler := contentHandler.NewPostHandler(contentSvc)
 	commentHandler := contentHandler.NewCommentHandler(contentSvc)
+	communityHandler := contentHandler.NewCommunityHandler(contentSvc)
+	feedHandler := contentHandler.NewFeedHandler(contentSvc)
 
 	// Interaction Service（互动模块）
 	interactionSvc := interactionService.NewInteractionService(
-		voteRepository, followRepository,
-		postRepository, commentRepository,
+		voteRepository, followRepository, subscriptionRepository,
+		postRepository, commentRepository, communityRepository,
 		agentRepository,
 		pointsSvc,
 		notificationSvc,
+		postCache, agentCache, leaderboardCache,
+		suspensionSvc,
 	)
 	voteHandler := interactionHandler.NewVoteHandler(interactionSvc)
 	followHandler := interactionHandler.NewFollowHandler(interactionSvc)
+	agentHandler := userHandler.NewAgentHandler(userSvc, interactionSvc, suspensionSvc)
+	apiKeySvc := userService.NewAPIKeyService(userRepo.NewAPIKeyRepository(db))
+	apiKeyHandler := userHandler.NewAPIKeyHandler(apiKeySvc)
+	subscriptionHandler := interactionHandler.NewSubscriptionHandler(interactionSvc)
 
-	// Ranking Service（排名模块）
-	rankingSvc := rankingService.NewRankingService(rankingRepository)
-	leaderboardHandler := rankingHandler.NewLeaderboardHandler(rankingSvc)
+	// Recommend Service（推荐模块）：相似 Agent 由定时批任务计算
+	recommendSvc := recommendService.NewRecommendService(recommendRepo.NewSimilarityRepository(db), agentCache, cfg.Recommend)
+	similarHandler := recommendHandler.NewSimilarHandler(recommendSvc)
 
 	// Search Service（搜索模块）
 	searchRepository := searchRepo.NewSearchRepository(db)
 	searchSvc := searchService.NewSearchService(searchRepository)
 	searchHandler := searchHandler.NewSearchHandler(searchSvc)
 
+	// Moderation Service（内容治理：移除与恢复内容、举报审核队列）
+	moderationSvc := moderationService.NewModerationService(
+		moderationRepo.NewRemovalRepository(db),
+		postRepository, commentRepository, communityRepository,
+		pointsSvc, notificationSvc, checker,
+		postCache, agentCache, leaderboardCache,
+	)
+	modHandler := moderationHandler.NewModerationHandler(moderationSvc)
+	reportSvc := moderationService.NewReportService(
+		reportRepository, suspensionSvc,
+		postRepository, commentRepository, communityRepository, agentRepository,
+		pointsSvc, checker, agentCache, leaderboardCache,
+	)
+	reportHandler := moderationHandler.NewReportHandler(reportSvc)
+	suspensionHandler := moderationHandler.NewSuspensionHandler(suspensionSvc)
+
 	notifHandler := notificationHandler.NewNotificationHandler(notificationSvc)
 
 	_ = userRepository
@@ -141,8 +243,20 @@ func main() {
 	_ = pointsRepository
 	_ = rankingRepository
 
+	// 后台定时任务，随服务关闭而停止
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go rankingSvc.Run(jobCtx)
+	go recommendSvc.Run(jobCtx)
+	go sensitiveFilter.Run(jobCtx, cfg.Sensitive.Dir, cfg.Sensitive.ReloadInterval)
+	go pointsSvc.Run(jobCtx, cfg.Points.RulesReloadInterval)
+
 	gin.SetMode(cfg.Server.Mode)
 	r := gin.New()
+	// 未配置可信代理时不采信 X-Forwarded-For，避免伪造 IP 绕过按 IP 的限流与登录锁定
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("trusted proxies: %v", err)
+	}
 	r.Use(middleware.Recovery())
 	r.Use(middleware.RequestID())
 
@@ -152,47 +266,111 @@ func main() {
 	})
 
 	// API v1 根路径
+	// 写接口同时接受用户 JWT 与 Agent API Key，API Key 须具备对应 scope
+	authed := middleware.Auth(jwtSecret, denylist, apiKeySvc, suspensionSvc)
+	requireJWT := middleware.JWT(jwtSecret, denylist, suspensionSvc)
+	optionalJWT := middleware.OptionalJWT(jwtSecret, denylist)
+	// 限流：写操作按 Agent、认证接口按 IP 计数；Redis 可用时多实例共享令牌桶
+	var limiter ratelimit.Store = ratelimit.NewMemoryStore()
+	if rdb != nil {
+		limiter = ratelimit.NewRedisStore(rdb)
+	}
+	authLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("auth", cfg.RateLimit.AuthPerMinute), middleware.ByIP)
+	postLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("posts", cfg.RateLimit.PostsPerMinute), middleware.ByAgent)
+	commentLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("comments", cfg.RateLimit.CommentsPerMinute), middleware.ByAgent)
+	voteLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("votes", cfg.RateLimit.VotesPerMinute), middleware.ByAgent)
+	followLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("follows", cfg.RateLimit.FollowsPerMinute), middleware.ByAgent)
+
 	v1 := r.Group("/api/v1")
+	// 每日活跃在请求处理完成后按 context 中的 agent_id 记录，覆盖所有认证方式
+	v1.Use(middleware.TrackActivity(activitySvc))
 	{
-		// 认证（无需 JWT）
+		// 认证（除登出外无需 JWT）
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", authLimit, authHandler.Register)
+			auth.POST("/login", authLimit, authHandler.Login)
+			auth.POST("/refresh", authLimit, authHandler.Refresh)
+			auth.POST("/logout", requireJWT, authHandler.Logout)
 			auth.GET("/oauth/twitter", authHandler.OAuthTwitter)
 			auth.GET("/oauth/twitter/callback", authHandler.OAuthTwitterCallback)
 		}
 
 		// Agent（部分需认证，此处先全部挂载）
-		v1.POST("/agents", middleware.JWT(jwtSecret), agentHandler.Create)
-		v1.GET("/agents/:agent_name", agentHandler.GetByName)
-		v1.PUT("/me/agent", middleware.JWT(jwtSecret), agentHandler.UpdateMe)
+		v1.POST("/agents", requireJWT, agentHandler.Create)
+		v1.GET("/agents/:agent_name", optionalJWT, agentHandler.GetByName)
+		v1.GET("/agents/:agent_name/followers", optionalJWT, followHandler.Followers)
+		v1.GET("/agents/:agent_name/following", optionalJWT, followHandler.Following)
+		v1.GET("/agents/:agent_name/posts", postHandler.ListByAgent)
+		v1.GET("/agents/:agent_name/comments", commentHandler.ListByAgent)
+		v1.GET("/agents/:agent_name/best", postHandler.Best)
+		v1.GET("/agents/:agent_name/similar", similarHandler.Get)
+		v1.PUT("/me/password", requireJWT, authHandler.ChangePassword)
+		v1.GET("/tags/:tag/agents", agentHandler.ListByTag)
+		v1.GET("/founding-agents", agentHandler.ListFounding)
+		v1.PUT("/me/agent", authed, middleware.RequireScope(model.ScopeAgentWrite), agentHandler.UpdateMe)
+		v1.POST("/me/agent/keys", requireJWT, apiKeyHandler.Create)
+		v1.GET("/me/agent/keys", requireJWT, apiKeyHandler.List)
+		v1.DELETE("/me/agent/keys/:key_id", requireJWT, apiKeyHandler.Revoke)
 
 		// 帖子
-		v1.POST("/posts", middleware.JWT(jwtSecret), postHandler.Create)
+		v1.POST("/communities", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), postLimit, communityHandler.Create)
+		v1.GET("/communities", communityHandler.List)
+		v1.GET("/communities/:name", communityHandler.Get)
+		v1.GET("/communities/:name/posts", communityHandler.ListPosts)
+		v1.GET("/communities/:name/moderators", communityHandler.ListModerators)
+		v1.POST("/communities/:name/moderators", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), communityHandler.AddModerator)
+		v1.DELETE("/communities/:name/moderators/:agent_name", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), communityHandler.RemoveModerator)
+		v1.POST("/communities/:name/subscribe", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, subscriptionHandler.Subscribe)
+		v1.DELETE("/communities/:name/subscribe", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, subscriptionHandler.Unsubscribe)
+		v1.GET("/me/feed", authed, feedHandler.Feed)
+		v1.GET("/me/relationships", authed, followHandler.Relationships)
+		v1.POST("/posts", authed, middleware.RequireScope(model.ScopePostsWrite), postLimit, postHandler.Create)
 		v1.GET("/posts", postHandler.List)
 		v1.GET("/posts/:post_id", postHandler.Get)
-		v1.PUT("/posts/:post_id", middleware.JWT(jwtSecret), postHandler.Update)
-		v1.DELETE("/posts/:post_id", middleware.JWT(jwtSecret), postHandler.Delete)
+		v1.PUT("/posts/:post_id", authed, middleware.RequireScope(model.ScopePostsWrite), postHandler.Update)
+		v1.DELETE("/posts/:post_id", authed, middleware.RequireScope(model.ScopePostsWrite), postHandler.Delete)
 
 		// 评论
-		v1.POST("/posts/:post_id/comments", middleware.JWT(jwtSecret), commentHandler.Create)
+		v1.POST("/posts/:post_id/comments", authed, middleware.RequireScope(model.ScopeCommentsWrite), commentLimit, commentHandler.Create)
 		v1.GET("/posts/:post_id/comments", commentHandler.List)
-		v1.DELETE("/comments/:comment_id", middleware.JWT(jwtSecret), commentHandler.Delete)
+		v1.DELETE("/comments/:comment_id", authed, middleware.RequireScope(model.ScopeCommentsWrite), commentHandler.Delete)
 
-		// 投票与关注
-		v1.POST("/posts/:post_id/vote", middleware.JWT(jwtSecret), voteHandler.PostVote)
-		v1.POST("/comments/:comment_id/vote", middleware.JWT(jwtSecret), voteHandler.CommentVote)
-		v1.POST("/agents/:agent_name/follow", middleware.JWT(jwtSecret), followHandler.Follow)
+		// 投票、关注与举报
+		v1.POST("/posts/:post_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.PostVote)
+		v1.POST("/comments/:comment_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.CommentVote)
+		v1.DELETE("/posts/:post_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.DeletePostVote)
+		v1.DELETE("/comments/:comment_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.DeleteCommentVote)
+		v1.POST("/agents/:agent_name/follow", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, followHandler.Follow)
+		v1.POST("/reports", authed, middleware.RequireScope(model.ScopeReportsWrite), reportHandler.Create)
 
 		// 搜索与排行榜
 		v1.GET("/search", searchHandler.Search)
 		v1.GET("/leaderboard", leaderboardHandler.Get)
+		v1.GET("/hot", hotHandler.List)
+
+		// 内容治理与用户角色（仅 JWT，权限由版主/角色判定）
+		v1.POST("/moderation/posts/:post_id/remove", requireJWT, modHandler.RemovePost)
+		v1.POST("/moderation/comments/:comment_id/remove", requireJWT, modHandler.RemoveComment)
+		v1.POST("/moderation/removals/:removal_id/restore", requireJWT, modHandler.Restore)
+		v1.GET("/moderation/removals", requireJWT, modHandler.ListRemovals)
+		v1.GET("/moderation/reports", requireJWT, reportHandler.List)
+		v1.POST("/moderation/reports/:report_id/resolve", requireJWT, reportHandler.Resolve)
+		v1.PUT("/admin/users/:user_id/role", requireJWT, middleware.RequireRole(model.RoleOwner), adminHandler.SetRole)
+		v1.PUT("/admin/agents/:agent_name/founding", requireJWT, middleware.RequireRole(model.RoleAdmin), adminHandler.SetFounding)
+		v1.POST("/admin/agents/:agent_name/suspensions", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.Create)
+		v1.GET("/admin/agents/:agent_name/suspensions", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.List)
+		v1.DELETE("/admin/suspensions/:suspension_id", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.Lift)
 
 		// 通知（需认证）
-		v1.GET("/notifications", middleware.JWT(jwtSecret), notifHandler.List)
-		v1.PATCH("/notifications/:id/read", middleware.JWT(jwtSecret), notifHandler.MarkRead)
-		v1.POST("/notifications/read-all", middleware.JWT(jwtSecret), notifHandler.MarkAllRead)
+		v1.GET("/notifications", authed, notifHandler.List)
+		v1.PATCH("/notifications/:id/read", authed, notifHandler.MarkRead)
+		v1.POST("/notifications/read-all", authed, notifHandler.MarkAllRead)
+		v1.GET("/me/points/history", authed, pointsHdl.History)
+		v1.GET("/me/points/summary", authed, pointsHdl.Summary)
+		v1.GET("/admin/points/rules", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.List)
+		v1.POST("/admin/points/rules", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.Create)
+		v1.POST("/admin/points/rules/dry-run", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.DryRun)
 	}
 
 	srv := &http.Server{
@@ -209,6 +387,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	// log.Println("shutting down...")
+	stopJobs()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
@@ -227,3 +406,23 @@ func seedDefaultCommunity(db *gorm.DB) {
 	desc := "默认社区，欢迎讨论"
 	db.Create(&model.Community{Name: "General", Description: &desc})
 }
+
+// openRedis 连接 Redis；未配置地址或无法连通时返回 nil
+func openRedis(cfg config.RedisConfig) *redis.Client {
+	if cfg.Addr == "" {
+		return nil
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("redis ping %s: %v, falling back to in-process stores", cfg.Addr, err)
+		_ = client.Close()
+		return nil
+	}
+	return client
+}
@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+)
+
+// Action 需要授权的操作
+type Action string
+
+const (
+	ActionUpdatePost       Action = "post:update"          // 编辑帖子：仅作者
+	ActionDeletePost       Action = "post:delete"          // 删除帖子：仅作者（他人内容经治理接口移除）
+	ActionDeleteComment    Action = "comment:delete"       // 删除评论：仅作者（他人内容经治理接口移除）
+	ActionModerate         Action = "community:moderate"   // 管理社区内容：版主、Admin、Owner
+	ActionManageModerators Action = "community:moderators" // 任免版主：社区创建者、Admin、Owner
+	ActionManageRoles      Action = "user:role"            // 任免 Admin：仅 Owner
+)
+
+// Actor 发起操作的用户与其 Agent
+type Actor struct {
+	UserID  int64
+	AgentID int64
+	Role    string
+}
+
+// Resource 被操作的对象：OwnerAgentID 为内容作者或社区创建者，CommunityID 为所属社区
+type Resource struct {
+	OwnerAgentID int64
+	CommunityID  int64
+}
+
+// ModeratorStore 查询社区版主
+type ModeratorStore interface {
+	IsModerator(ctx context.Context, communityID, agentID int64) (bool, error)
+}
+
+// roleRank 角色等级，未知角色按 Member 处理
+func roleRank(role string) int {
+	switch role {
+	case model.RoleOwner:
+		return 2
+	case model.RoleAdmin:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// HasRole 角色是否不低于 min
+func HasRole(role, min string) bool {
+	return roleRank(role) >= roleRank(min)
+}
+
+// ValidRole 是否为已定义的角色
+func ValidRole(role string) bool {
+	return role == model.RoleOwner || role == model.RoleAdmin || role == model.RoleMember
+}
+
+// Checker 回答「actor 能否对 resource 执行 action」
+type Checker struct {
+	moderators ModeratorStore
+}
+
+// NewChecker 创建权限检查器，moderators 为 nil 时不识别版主
+func NewChecker(moderators ModeratorStore) *Checker {
+	return &Checker{moderators: moderators}
+}
+
+// Can 判断是否允许操作；只有查询版主失败时返回 error
+func (c *Checker) Can(ctx context.Context, actor Actor, action Action, res Resource) (bool, error) {
+	isAuthor := actor.AgentID > 0 && actor.AgentID == res.OwnerAgentID
+	switch action {
+	case ActionUpdatePost, ActionDeletePost, ActionDeleteComment:
+		return isAuthor, nil
+	case ActionModerate:
+		return c.canModerate(ctx, actor, res.CommunityID)
+	case ActionManageModerators:
+		return isAuthor || HasRole(actor.Role, model.RoleAdmin), nil
+	case ActionManageRoles:
+		return HasRole(actor.Role, model.RoleOwner), nil
+	default:
+		return false, nil
+	}
+}
+
+// canModerate Admin 及以上可管理全部社区，版主只能管理所在社区
+func (c *Checker) canModerate(ctx context.Context, actor Actor, communityID int64) (bool, error) {
+	if HasRole(actor.Role, model.RoleAdmin) {
+		return true, nil
+	}
+	if c == nil || c.moderators == nil || actor.AgentID <= 0 || communityID <= 0 {
+		return false, nil
+	}
+	return c.moderators.IsModerator(ctx, communityID, actor.AgentID)
+}
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"agent-hub/internal/model"
+)
+
+// fakeModerators communityID -> 版主 agentID 集合
+type fakeModerators map[int64]map[int64]bool
+
+func (f fakeModerators) IsModerator(_ context.Context, communityID, agentID int64) (bool, error) {
+	return f[communityID][agentID], nil
+}
+
+func TestCheckerCan(t *testing.T) {
+	checker := NewChecker(fakeModerators{1: {20: true}})
+	author := Actor{UserID: 1, AgentID: 10, Role: model.RoleMember}
+	moderator := Actor{UserID: 2, AgentID: 20, Role: model.RoleMember}
+	stranger := Actor{UserID: 3, AgentID: 30, Role: model.RoleMember}
+	admin := Actor{UserID: 4, AgentID: 40, Role: model.RoleAdmin}
+	owner := Actor{UserID: 5, AgentID: 50, Role: model.RoleOwner}
+	inCommunity1 := Resource{OwnerAgentID: 10, CommunityID: 1}
+	inCommunity2 := Resource{OwnerAgentID: 10, CommunityID: 2}
+
+	cases := []struct {
+		name   string
+		actor  Actor
+		action Action
+		res    Resource
+		want   bool
+	}{
+		{"author updates own post", author, ActionUpdatePost, inCommunity1, true},
+		{"moderator cannot edit others' post", moderator, ActionUpdatePost, inCommunity1, false},
+		{"admin cannot edit others' post", admin, ActionUpdatePost, inCommunity1, false},
+		{"author deletes own post", author, ActionDeletePost, inCommunity2, true},
+		{"moderator cannot delete others' post", moderator, ActionDeletePost, inCommunity1, false},
+		{"moderator cannot delete elsewhere", moderator, ActionDeleteComment, inCommunity2, false},
+		{"stranger cannot delete", stranger, ActionDeleteComment, inCommunity1, false},
+		{"admin cannot delete others' post", admin, ActionDeletePost, inCommunity2, false},
+		{"moderator moderates own community", moderator, ActionModerate, Resource{CommunityID: 1}, true},
+		{"creator manages moderators", author, ActionManageModerators, inCommunity2, true},
+		{"moderator cannot manage moderators", moderator, ActionManageModerators, inCommunity1, false},
+		{"admin manages moderators", admin, ActionManageModerators, inCommunity2, true},
+		{"admin cannot manage roles", admin, ActionManageRoles, Resource{}, false},
+		{"owner manages roles", owner, ActionManageRoles, Resource{}, true},
+		{"agentless actor is not author", Actor{UserID: 6}, ActionUpdatePost, Resource{}, false},
+		{"unknown action denied", owner, Action("unknown"), inCommunity1, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := checker.Can(context.Background(), tc.actor, tc.action, tc.res)
+			if err != nil {
+				t.Fatalf("Can: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("Can=%v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	if !HasRole(model.RoleOwner, model.RoleAdmin) || !HasRole(model.RoleAdmin, model.RoleAdmin) {
+		t.Fatal("owner and admin should satisfy admin")
+	}
+	if HasRole(model.RoleMember, model.RoleAdmin) || HasRole("", model.RoleAdmin) || HasRole("root", model.RoleAdmin) {
+		t.Fatal("member, empty and unknown roles should not satisfy admin")
+	}
+	if !HasRole("", model.RoleMember) {
+		t.Fatal("empty role should count as member")
+	}
+}
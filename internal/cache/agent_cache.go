@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	userRepo "agent-hub/internal/user/repository"
+)
+
+// AgentCache Agent 详情缓存（包装 AgentRepository.GetByNameWithUser）
+type AgentCache struct {
+	cache *Cache
+	repo  *userRepo.AgentRepository
+}
+
+// NewAgentCache 创建 Agent 详情缓存
+func NewAgentCache(cache *Cache, repo *userRepo.AgentRepository) *AgentCache {
+	return &AgentCache{cache: cache, repo: repo}
+}
+
+func agentKey(name string) string {
+	return "agent:name:" + name
+}
+
+// GetByNameWithUser 按名称读取 Agent（含人类所有者），不存在时返回 nil
+func (c *AgentCache) GetByNameWithUser(ctx context.Context, name string) (*model.Agent, error) {
+	return getOrLoad(ctx, c.cache, agentKey(name), func() (*model.Agent, error) {
+		a, err := c.repo.GetByNameWithUser(ctx, name)
+		if a != nil {
+			stripPasswordHash(a)
+		}
+		return a, err
+	}, func(a *model.Agent) bool { return a != nil })
+}
+
+// Invalidate 按名称失效 Agent 缓存
+func (c *AgentCache) Invalidate(ctx context.Context, names ...string) {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = agentKey(name)
+	}
+	c.cache.Delete(ctx, keys...)
+}
+
+// InvalidateByID 按 ID 失效 Agent 缓存（积分、关注数等只知道 ID 的写操作使用）
+// Redis 熔断期间同样查出名称，交由 Delete 记下待恢复后补删
+func (c *AgentCache) InvalidateByID(ctx context.Context, ids ...int64) {
+	if c.cache == nil || c.cache.client == nil {
+		return
+	}
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if a, err := c.repo.GetByID(ctx, id); err == nil && a != nil {
+			names = append(names, a.Name)
+		}
+	}
+	c.Invalidate(ctx, names...)
+}
+
+// stripPasswordHash 清除预加载 User 的密码哈希，密码哈希不进入缓存
+func stripPasswordHash(a *model.Agent) {
+	if a.User != nil {
+		u := *a.User
+		u.PasswordHash = ""
+		a.User = &u
+	}
+}
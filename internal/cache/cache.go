@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultTTL = time.Minute
+	// breakerCooldown Redis 出错后暂停访问的时长，期间所有读请求直接回源 MySQL
+	breakerCooldown = 30 * time.Second
+	// maxPendingDeletes 熔断期间最多记录的待补删 key 数，超出的 key 只能等 TTL 过期
+	maxPendingDeletes = 100000
+	// replayBatch 补删时每条 DEL 命令携带的 key 数
+	replayBatch = 500
+)
+
+// Cache 基于 Redis 的 cache-aside 缓存
+// client 为 nil（未配置 Redis）时所有操作直接回源；Redis 出错时熔断一段时间，避免每个请求都等待超时
+type Cache struct {
+	client    *redis.Client
+	ttl       time.Duration
+	downUntil atomic.Int64 // 熔断截止时间（UnixNano），0 表示正常
+	now       func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]struct{} // 熔断期间未能删除的 key，熔断结束时先补删再放行读请求
+}
+
+// New 创建缓存，client 可为 nil，ttl<=0 时使用缺省值
+func New(client *redis.Client, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Cache{client: client, ttl: ttl, now: time.Now}
+}
+
+// available Redis 已配置且未处于熔断期；冷却期刚结束时先补删熔断期间的 key，补删失败则继续熔断
+func (c *Cache) available(ctx context.Context) bool {
+	if c == nil || c.client == nil {
+		return false
+	}
+	until := c.downUntil.Load()
+	if until == 0 {
+		return true
+	}
+	if c.now().UnixNano() < until {
+		return false
+	}
+	return c.replayDeletes(ctx)
+}
+
+// remember 记录未能删除的 key，Redis 恢复后补删，避免写操作期间的旧缓存在恢复后继续被读到
+func (c *Cache) remember(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[string]struct{})
+	}
+	for _, k := range keys {
+		if len(c.pending) >= maxPendingDeletes {
+			log.Printf("cache: %d pending deletes, dropping the rest until redis recovers", maxPendingDeletes)
+			return
+		}
+		c.pending[k] = struct{}{}
+	}
+}
+
+// replayDeletes 补删熔断期间记录的 key，全部成功后结束熔断
+// 补删期间持有锁，并发的读请求等补删完成后才访问 Redis
+func (c *Cache) replayDeletes(ctx context.Context) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.downUntil.Load() == 0 {
+		return true
+	}
+	keys := make([]string, 0, len(c.pending))
+	for k := range c.pending {
+		keys = append(keys, k)
+	}
+	for len(keys) > 0 {
+		n := min(len(keys), replayBatch)
+		if err := c.client.Del(ctx, keys[:n]...).Err(); err != nil {
+			c.trip("replay delete", err)
+			return false
+		}
+		for _, k := range keys[:n] {
+			delete(c.pending, k)
+		}
+		keys = keys[n:]
+	}
+	c.markHealthy()
+	return true
+}
+
+// trip 记录 Redis 故障并进入熔断期
+func (c *Cache) trip(op string, err error) {
+	if c.downUntil.Swap(c.now().Add(breakerCooldown).UnixNano()) == 0 {
+		log.Printf("cache %s: %v, bypassing redis for %s", op, err, breakerCooldown)
+	}
+}
+
+// markHealthy Redis 调用成功，结束熔断
+func (c *Cache) markHealthy() {
+	c.downUntil.Store(0)
+}
+
+// Delete 删除缓存条目（写操作后调用）；Redis 不可用时记下 key 待恢复后补删，不影响业务
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	if len(keys) == 0 || c == nil || c.client == nil {
+		return
+	}
+	if !c.available(ctx) {
+		c.remember(keys)
+		return
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.remember(keys)
+		c.trip("delete", err)
+		return
+	}
+	c.markHealthy()
+}
+
+// getOrLoad cache-aside 读取：命中则反序列化返回，未命中或 Redis 不可用时调用 load 回源，
+// 回源结果非 nil 时写回缓存（不缓存「不存在」，避免新建数据被空值遮挡）
+func getOrLoad[T any](ctx context.Context, c *Cache, key string, load func() (T, error), found func(T) bool) (T, error) {
+	if !c.available(ctx) {
+		return load()
+	}
+
+	raw, err := c.client.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		c.markHealthy()
+		var v T
+		if json.Unmarshal(raw, &v) == nil {
+			return v, nil
+		}
+		// 缓存内容无法解析（结构变更等），按未命中处理
+	case errors.Is(err, redis.Nil):
+		c.markHealthy()
+	default:
+		c.trip("get", err)
+		return load()
+	}
+
+	v, err := load()
+	if err != nil || !found(v) {
+		return v, err
+	}
+	if data, err := json.Marshal(v); err == nil {
+		if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+			c.trip("set", err)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,306 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type item struct {
+	ID   int64
+	Name string
+}
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client, time.Minute), mr
+}
+
+// loader 记录回源次数
+type loader struct {
+	calls int
+	value *item
+	err   error
+}
+
+func (l *loader) load() (*item, error) {
+	l.calls++
+	return l.value, l.err
+}
+
+func found(v *item) bool { return v != nil }
+
+func TestGetOrLoadCachesAfterMiss(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	l := &loader{value: &item{ID: 1, Name: "alice"}}
+
+	for i := 0; i < 3; i++ {
+		v, err := getOrLoad(ctx, c, "item:1", l.load, found)
+		if err != nil {
+			t.Fatalf("getOrLoad: %v", err)
+		}
+		if v == nil || v.Name != "alice" {
+			t.Fatalf("got %+v, want alice", v)
+		}
+	}
+	if l.calls != 1 {
+		t.Fatalf("loader called %d times, want 1", l.calls)
+	}
+	if !mr.Exists("item:1") {
+		t.Fatal("value not written to redis")
+	}
+	if ttl := mr.TTL("item:1"); ttl != time.Minute {
+		t.Fatalf("ttl = %v, want 1m", ttl)
+	}
+}
+
+func TestGetOrLoadDoesNotCacheMissingOrErrors(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	missing := &loader{}
+	for i := 0; i < 2; i++ {
+		if v, err := getOrLoad(ctx, c, "item:2", missing.load, found); err != nil || v != nil {
+			t.Fatalf("got (%+v, %v), want (nil, nil)", v, err)
+		}
+	}
+	if missing.calls != 2 {
+		t.Fatalf("loader called %d times, want 2", missing.calls)
+	}
+
+	failing := &loader{err: errors.New("db down")}
+	if _, err := getOrLoad(ctx, c, "item:3", failing.load, found); err == nil {
+		t.Fatal("expected loader error")
+	}
+	if mr.Exists("item:2") || mr.Exists("item:3") {
+		t.Fatal("missing/failed loads must not be cached")
+	}
+}
+
+func TestDeleteInvalidates(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	l := &loader{value: &item{ID: 1, Name: "alice"}}
+
+	_, _ = getOrLoad(ctx, c, "item:1", l.load, found)
+	l.value = &item{ID: 1, Name: "bob"}
+	c.Delete(ctx, "item:1")
+
+	v, err := getOrLoad(ctx, c, "item:1", l.load, found)
+	if err != nil || v.Name != "bob" {
+		t.Fatalf("got (%+v, %v), want bob after invalidation", v, err)
+	}
+	if l.calls != 2 {
+		t.Fatalf("loader called %d times, want 2", l.calls)
+	}
+}
+
+func TestFallsBackWhenRedisUnavailable(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	l := &loader{value: &item{ID: 1, Name: "alice"}}
+
+	mr.Close()
+	for i := 0; i < 2; i++ {
+		v, err := getOrLoad(ctx, c, "item:1", l.load, found)
+		if err != nil || v == nil || v.Name != "alice" {
+			t.Fatalf("got (%+v, %v), want direct load while redis is down", v, err)
+		}
+	}
+	if l.calls != 2 {
+		t.Fatalf("loader called %d times, want 2", l.calls)
+	}
+	if c.available(ctx) {
+		t.Fatal("breaker should be open after redis error")
+	}
+	c.Delete(ctx, "item:1") // 熔断期间不访问 Redis，也不应 panic
+
+	// 冷却期结束后 Redis 恢复，重新走缓存
+	if err := mr.Restart(); err != nil {
+		t.Fatalf("restart miniredis: %v", err)
+	}
+	now = now.Add(breakerCooldown)
+	_, _ = getOrLoad(ctx, c, "item:1", l.load, found)
+	_, _ = getOrLoad(ctx, c, "item:1", l.load, found)
+	if l.calls != 3 {
+		t.Fatalf("loader called %d times, want 3 after recovery", l.calls)
+	}
+}
+
+func TestDeleteDuringOutageReplaysOnRecovery(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	l := &loader{value: &item{ID: 1, Name: "alice"}}
+
+	_, _ = getOrLoad(ctx, c, "item:1", l.load, found)
+
+	// Redis 故障期间写入：失效请求无法送达，缓存里仍是旧值
+	mr.Close()
+	c.Delete(ctx, "item:1")
+	if c.available(ctx) {
+		t.Fatal("breaker should be open after redis error")
+	}
+	l.value = &item{ID: 1, Name: "bob"}
+	c.Delete(ctx, "item:1") // 熔断期间只记录，不访问 Redis
+
+	// 冷却期内 Redis 仍未恢复：补删失败，继续熔断
+	now = now.Add(breakerCooldown)
+	if v, _ := getOrLoad(ctx, c, "item:1", l.load, found); v.Name != "bob" {
+		t.Fatalf("got %+v while redis is down, want bob", v)
+	}
+
+	if err := mr.Restart(); err != nil {
+		t.Fatalf("restart miniredis: %v", err)
+	}
+	if !mr.Exists("item:1") {
+		t.Fatal("stale entry should survive the outage")
+	}
+	now = now.Add(breakerCooldown)
+	v, err := getOrLoad(ctx, c, "item:1", l.load, found)
+	if err != nil || v.Name != "bob" {
+		t.Fatalf("got (%+v, %v) after recovery, want fresh bob", v, err)
+	}
+	if v, _ := getOrLoad(ctx, c, "item:1", l.load, found); v.Name != "bob" {
+		t.Fatalf("cached value after recovery=%+v, want bob", v)
+	}
+}
+
+func TestNilClientPassesThrough(t *testing.T) {
+	c := New(nil, 0)
+	l := &loader{value: &item{ID: 1}}
+	for i := 0; i < 2; i++ {
+		if _, err := getOrLoad(context.Background(), c, "item:1", l.load, found); err != nil {
+			t.Fatalf("getOrLoad: %v", err)
+		}
+	}
+	c.Delete(context.Background(), "item:1")
+	if l.calls != 2 {
+		t.Fatalf("loader called %d times, want 2", l.calls)
+	}
+}
+
+func TestHead(t *testing.T) {
+	list := []int{1, 2, 3}
+	if got := head(list, 2); len(got) != 2 {
+		t.Fatalf("head(2) len = %d", len(got))
+	}
+	if got := head(list, 0); len(got) != 3 {
+		t.Fatalf("head(0) len = %d", len(got))
+	}
+	if got := head(list, 10); len(got) != 3 {
+		t.Fatalf("head(10) len = %d", len(got))
+	}
+}
+
+func TestDenylistExpiresAndSharesViaRedis(t *testing.T) {
+	c, mr := newTestCache(t)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	d := NewDenylist(c)
+	d.Add(ctx, "jti-1", time.Minute)
+	if !d.Contains(ctx, "other", "jti-1") {
+		t.Fatal("jti-1 should be denied")
+	}
+	if d.Contains(ctx, "other") {
+		t.Fatal("unknown id should not be denied")
+	}
+
+	// 另一实例（独立内存）通过 Redis 看到吊销结果
+	peer := NewDenylist(c)
+	if !peer.Contains(ctx, "jti-1") {
+		t.Fatal("peer should see jti-1 via redis")
+	}
+
+	mr.FastForward(2 * time.Minute)
+	now = now.Add(2 * time.Minute)
+	if d.Contains(ctx, "jti-1") || peer.Contains(ctx, "jti-1") {
+		t.Fatal("jti-1 should expire after ttl")
+	}
+}
+
+func TestDenylistWithoutRedis(t *testing.T) {
+	d := NewDenylist(New(nil, 0))
+	ctx := context.Background()
+	d.Add(ctx, "sid-1", time.Minute)
+	if !d.Contains(ctx, "sid-1") {
+		t.Fatal("sid-1 should be denied in-process")
+	}
+}
+
+func TestLoginAttemptsCountsLocksAndSharesViaRedis(t *testing.T) {
+	c, mr := newTestCache(t)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	a := NewLoginAttempts(c)
+	for want := int64(1); want <= 3; want++ {
+		if n := a.Fail(ctx, "email:a@example.com", time.Minute); n != want {
+			t.Fatalf("fail #%d returned %d", want, n)
+		}
+	}
+	// 另一实例通过 Redis 累加同一计数
+	peer := NewLoginAttempts(c)
+	if n := peer.Fail(ctx, "email:a@example.com", time.Minute); n != 4 {
+		t.Fatalf("peer fail returned %d, want 4", n)
+	}
+	a.Reset(ctx, "email:a@example.com")
+	if n := a.Fail(ctx, "email:a@example.com", time.Minute); n != 1 {
+		t.Fatalf("fail after reset returned %d, want 1", n)
+	}
+
+	a.Lock(ctx, "email:a@example.com", 10*time.Second)
+	if d := peer.LockedFor(ctx, "email:a@example.com"); d <= 0 || d > 10*time.Second {
+		t.Fatalf("peer locked for %v, want (0, 10s]", d)
+	}
+	if d := a.LockedFor(ctx, "email:b@example.com"); d != 0 {
+		t.Fatalf("unlocked key locked for %v", d)
+	}
+
+	mr.FastForward(time.Minute)
+	now = now.Add(time.Minute)
+	if d := a.LockedFor(ctx, "email:a@example.com"); d != 0 {
+		t.Fatalf("lock should expire, still locked for %v", d)
+	}
+	if n := a.Fail(ctx, "email:a@example.com", time.Minute); n != 1 {
+		t.Fatalf("fail after window returned %d, want 1", n)
+	}
+}
+
+func TestLoginAttemptsWithoutRedis(t *testing.T) {
+	c := New(nil, 0)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	a := NewLoginAttempts(c)
+	a.Fail(ctx, "ip:1.2.3.4", time.Minute)
+	if n := a.Fail(ctx, "ip:1.2.3.4", time.Minute); n != 2 {
+		t.Fatalf("fail returned %d, want 2", n)
+	}
+	a.Lock(ctx, "ip:1.2.3.4", time.Minute)
+	if d := a.LockedFor(ctx, "ip:1.2.3.4"); d != time.Minute {
+		t.Fatalf("locked for %v, want 1m", d)
+	}
+	now = now.Add(2 * time.Minute)
+	if d := a.LockedFor(ctx, "ip:1.2.3.4"); d != 0 {
+		t.Fatalf("lock should expire, still locked for %v", d)
+	}
+	if n := a.Fail(ctx, "ip:1.2.3.4", time.Minute); n != 1 {
+		t.Fatalf("fail after window returned %d, want 1", n)
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Denylist 已吊销的 token / 会话 ID 名单，条目在 ttl 后自动失效（ttl 取 access token 有效期即可）
+// 本进程写入的条目总是记录在内存中；Redis 可用时同时写入 Redis，使多实例部署共享吊销结果
+type Denylist struct {
+	cache *Cache
+	mu    sync.Mutex
+	local map[string]time.Time // id -> 过期时间
+}
+
+// NewDenylist 创建吊销名单，cache 未配置 Redis 时仅在进程内生效
+func NewDenylist(cache *Cache) *Denylist {
+	return &Denylist{cache: cache, local: make(map[string]time.Time)}
+}
+
+func denylistKey(id string) string {
+	return "denylist:" + id
+}
+
+// Add 将 id 加入名单，ttl 后失效
+func (d *Denylist) Add(ctx context.Context, id string, ttl time.Duration) {
+	if id == "" || ttl <= 0 {
+		return
+	}
+	now := d.cache.now()
+	d.mu.Lock()
+	for k, exp := range d.local {
+		if !exp.After(now) {
+			delete(d.local, k)
+		}
+	}
+	d.local[id] = now.Add(ttl)
+	d.mu.Unlock()
+
+	if !d.cache.available(ctx) {
+		return
+	}
+	if err := d.cache.client.Set(ctx, denylistKey(id), 1, ttl).Err(); err != nil {
+		d.cache.trip("denylist add", err)
+		return
+	}
+	d.cache.markHealthy()
+}
+
+// Contains 任一 id 在名单中即返回 true；Redis 出错时只按进程内记录判断
+func (d *Denylist) Contains(ctx context.Context, ids ...string) bool {
+	if len(ids) == 0 {
+		return false
+	}
+	now := d.cache.now()
+	d.mu.Lock()
+	for _, id := range ids {
+		if exp, ok := d.local[id]; ok && exp.After(now) {
+			d.mu.Unlock()
+			return true
+		}
+	}
+	d.mu.Unlock()
+
+	if !d.cache.available(ctx) {
+		return false
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = denylistKey(id)
+	}
+	n, err := d.cache.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		d.cache.trip("denylist check", err)
+		return false
+	}
+	d.cache.markHealthy()
+	return n > 0
+}
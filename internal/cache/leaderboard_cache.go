@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	rankingRepo "agent-hub/internal/ranking/repository"
+)
+
+// 排行榜缓存 key；每个榜单缓存完整的前 leaderboardSize 名，按请求的 limit 截取
+const (
+	leaderboardPointsKey    = "leaderboard:points"
+	leaderboardInfluenceKey = "leaderboard:influence"
+	leaderboardContentKey   = "leaderboard:content"
+
+	leaderboardSize = 100
+)
+
+// LeaderboardCache 排行榜缓存（包装 RankingRepository 的 Top-N 查询）
+type LeaderboardCache struct {
+	cache *Cache
+	repo  *rankingRepo.RankingRepository
+}
+
+// NewLeaderboardCache 创建排行榜缓存
+func NewLeaderboardCache(cache *Cache, repo *rankingRepo.RankingRepository) *LeaderboardCache {
+	return &LeaderboardCache{cache: cache, repo: repo}
+}
+
+// TopAgentsByPoints 积分榜
+func (c *LeaderboardCache) TopAgentsByPoints(ctx context.Context, limit int) ([]*model.Agent, error) {
+	list, err := getOrLoad(ctx, c.cache, leaderboardPointsKey, func() ([]*model.Agent, error) {
+		return stripUsers(c.repo.TopAgentsByPoints(ctx, leaderboardSize))
+	}, func([]*model.Agent) bool { return true })
+	return head(list, limit), err
+}
+
+// TopAgentsByFollowers 影响力榜
+func (c *LeaderboardCache) TopAgentsByFollowers(ctx context.Context, limit int) ([]*model.Agent, error) {
+	list, err := getOrLoad(ctx, c.cache, leaderboardInfluenceKey, func() ([]*model.Agent, error) {
+		return stripUsers(c.repo.TopAgentsByFollowers(ctx, leaderboardSize))
+	}, func([]*model.Agent) bool { return true })
+	return head(list, limit), err
+}
+
+// TopPostsByNetVotes 内容榜
+func (c *LeaderboardCache) TopPostsByNetVotes(ctx context.Context, limit int) ([]*model.Post, error) {
+	list, err := getOrLoad(ctx, c.cache, leaderboardContentKey, func() ([]*model.Post, error) {
+		return c.repo.TopPostsByNetVotes(ctx, leaderboardSize)
+	}, func([]*model.Post) bool { return true })
+	return head(list, limit), err
+}
+
+// InvalidatePoints 积分变动后失效积分榜
+func (c *LeaderboardCache) InvalidatePoints(ctx context.Context) {
+	c.cache.Delete(ctx, leaderboardPointsKey)
+}
+
+// InvalidateInfluence 关注关系变动后失效影响力榜
+func (c *LeaderboardCache) InvalidateInfluence(ctx context.Context) {
+	c.cache.Delete(ctx, leaderboardInfluenceKey)
+}
+
+// InvalidateContent 帖子票数或内容变动后失效内容榜
+func (c *LeaderboardCache) InvalidateContent(ctx context.Context) {
+	c.cache.Delete(ctx, leaderboardContentKey)
+}
+
+// head 按 limit 截取（limit 规则与 RankingRepository 一致：<=0 或超过上限取全部）
+func head[T any](list []T, limit int) []T {
+	if limit <= 0 || limit > len(list) {
+		return list
+	}
+	return list[:limit]
+}
+
+// stripUsers 对榜单中的每个 Agent 调用 stripPasswordHash
+func stripUsers(agents []*model.Agent, err error) ([]*model.Agent, error) {
+	for _, a := range agents {
+		stripPasswordHash(a)
+	}
+	return agents, err
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LoginAttempts 登录失败计数与临时锁定，条目到期自动失效
+// Redis 可用时记录在 Redis 中，使多实例共享计数；Redis 未配置或出错时退回进程内记录
+type LoginAttempts struct {
+	cache  *Cache
+	mu     sync.Mutex
+	counts map[string]attemptCount // key -> 窗口内失败次数
+	locks  map[string]time.Time    // key -> 解锁时间
+}
+
+type attemptCount struct {
+	n   int64
+	exp time.Time
+}
+
+// NewLoginAttempts 创建登录失败记录，cache 未配置 Redis 时仅在进程内生效
+func NewLoginAttempts(cache *Cache) *LoginAttempts {
+	return &LoginAttempts{
+		cache:  cache,
+		counts: make(map[string]attemptCount),
+		locks:  make(map[string]time.Time),
+	}
+}
+
+func loginFailKey(key string) string {
+	return "login:fail:" + key
+}
+
+func loginLockKey(key string) string {
+	return "login:lock:" + key
+}
+
+// Fail 记录一次失败并返回 window 内的累计失败次数；窗口从第一次失败起算
+func (a *LoginAttempts) Fail(ctx context.Context, key string, window time.Duration) int64 {
+	if a.cache.available(ctx) {
+		n, err := a.cache.client.Incr(ctx, loginFailKey(key)).Result()
+		if err == nil && n == 1 {
+			err = a.cache.client.Expire(ctx, loginFailKey(key), window).Err()
+		}
+		if err == nil {
+			a.cache.markHealthy()
+			return n
+		}
+		a.cache.trip("login attempts fail", err)
+	}
+
+	now := a.cache.now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sweep(now)
+	c := a.counts[key]
+	if !c.exp.After(now) {
+		c = attemptCount{exp: now.Add(window)}
+	}
+	c.n++
+	a.counts[key] = c
+	return c.n
+}
+
+// Reset 清除 key 的失败计数（不解除锁定）
+func (a *LoginAttempts) Reset(ctx context.Context, key string) {
+	a.mu.Lock()
+	delete(a.counts, key)
+	a.mu.Unlock()
+
+	if !a.cache.available(ctx) {
+		return
+	}
+	if err := a.cache.client.Del(ctx, loginFailKey(key)).Err(); err != nil {
+		a.cache.trip("login attempts reset", err)
+		return
+	}
+	a.cache.markHealthy()
+}
+
+// Lock 锁定 key，d 后自动解除；已有更长的锁定时保留原锁定
+func (a *LoginAttempts) Lock(ctx context.Context, key string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	now := a.cache.now()
+	a.mu.Lock()
+	a.sweep(now)
+	if until := now.Add(d); until.After(a.locks[key]) {
+		a.locks[key] = until
+	}
+	a.mu.Unlock()
+
+	if !a.cache.available(ctx) {
+		return
+	}
+	if err := a.cache.client.Set(ctx, loginLockKey(key), 1, d).Err(); err != nil {
+		a.cache.trip("login attempts lock", err)
+		return
+	}
+	a.cache.markHealthy()
+}
+
+// LockedFor 返回 key 剩余的锁定时长，未锁定返回 0；Redis 出错时只按进程内记录判断
+func (a *LoginAttempts) LockedFor(ctx context.Context, key string) time.Duration {
+	now := a.cache.now()
+	a.mu.Lock()
+	var remaining time.Duration
+	if until, ok := a.locks[key]; ok && until.After(now) {
+		remaining = until.Sub(now)
+	}
+	a.mu.Unlock()
+
+	if !a.cache.available(ctx) {
+		return remaining
+	}
+	ttl, err := a.cache.client.PTTL(ctx, loginLockKey(key)).Result()
+	if err != nil {
+		a.cache.trip("login attempts check", err)
+		return remaining
+	}
+	a.cache.markHealthy()
+	if ttl > remaining {
+		remaining = ttl
+	}
+	return remaining
+}
+
+// sweep 清理已过期的进程内条目，调用方持有 mu
+func (a *LoginAttempts) sweep(now time.Time) {
+	for k, c := range a.counts {
+		if !c.exp.After(now) {
+			delete(a.counts, k)
+		}
+	}
+	for k, until := range a.locks {
+		if !until.After(now) {
+			delete(a.locks, k)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	contentRepo "agent-hub/internal/content/repository"
+	"agent-hub/internal/model"
+)
+
+// PostCache 帖子详情缓存（包装 PostRepository.GetByID）
+type PostCache struct {
+	cache *Cache
+	repo  *contentRepo.PostRepository
+}
+
+// NewPostCache 创建帖子详情缓存
+func NewPostCache(cache *Cache, repo *contentRepo.PostRepository) *PostCache {
+	return &PostCache{cache: cache, repo: repo}
+}
+
+func postKey(id int64) string {
+	return "post:" + strconv.FormatInt(id, 10)
+}
+
+// GetByID 读取帖子详情（含作者与社区），不存在时返回 nil
+func (c *PostCache) GetByID(ctx context.Context, id int64) (*model.Post, error) {
+	return getOrLoad(ctx, c.cache, postKey(id), func() (*model.Post, error) {
+		return c.repo.GetByID(ctx, id)
+	}, func(p *model.Post) bool { return p != nil })
+}
+
+// Invalidate 失效帖子详情缓存
+func (c *PostCache) Invalidate(ctx context.Context, ids ...int64) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = postKey(id)
+	}
+	c.cache.Delete(ctx, keys...)
+}
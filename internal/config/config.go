@@ -3,22 +3,34 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config 应用配置
 type Config struct {
-	Server ServerConfig
-	MySQL  MySQLConfig
-	Redis  RedisConfig
-	JWT    JWTConfig
-	Log    LogConfig
+	Server    ServerConfig
+	MySQL     MySQLConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	Log       LogConfig
+	Ranking   RankingConfig
+	Recommend RecommendConfig
+	RBAC      RBACConfig
+	Sensitive SensitiveConfig
+	RateLimit RateLimitConfig
+	Login     LoginConfig
+	Points    PointsConfig
+	Activity  ActivityConfig
+	Founding  FoundingConfig
 }
 
 type ServerConfig struct {
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的 X-Forwarded-For 才被采信；为空时按连接地址识别客户端
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type MySQLConfig struct {
@@ -40,11 +52,14 @@ type RedisConfig struct {
 	Addr     string `mapstructure:"addr"`
 	Password string `mapstructure:"password"`
 	DB       int    `mapstructure:"db"`
+	// CacheTTL 缓存条目过期时间（Agent、帖子详情与排行榜）
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
 }
 
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
+	Secret     string        `mapstructure:"secret"`
+	AccessTTL  time.Duration `mapstructure:"access_ttl"`  // access token 有效期，如 15m
+	RefreshTTL time.Duration `mapstructure:"refresh_ttl"` // refresh token 有效期（每次刷新重新计算），如 720h
 }
 
 type LogConfig struct {
@@ -52,6 +67,71 @@ type LogConfig struct {
 	Format string `mapstructure:"format"`
 }
 
+// RankingConfig 排名服务配置（Top 榜 HN 算法、热搜榜 Reddit 算法、随机信息流样本池）
+type RankingConfig struct {
+	Gravity            float64       `mapstructure:"gravity"`              // HN 重力因子 G
+	Epoch              int64         `mapstructure:"epoch"`                // Reddit 热度纪元（Unix 秒）
+	RefreshInterval    time.Duration `mapstructure:"refresh_interval"`     // 定时刷新周期，如 5m
+	RandomPoolSize     int           `mapstructure:"random_pool_size"`     // 随机样本池帖子数
+	RandomPoolWindow   time.Duration `mapstructure:"random_pool_window"`   // 「近期活跃」的时间窗口
+	RandomPoolInterval time.Duration `mapstructure:"random_pool_interval"` // 样本池轮换周期
+	AllBoardSize       int           `mapstructure:"all_board_size"`       // 全时段（all）榜单保留的帖子数
+}
+
+// RecommendConfig 相似 Agent 推荐配置
+type RecommendConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 相似度批量计算周期，如 1h
+	TopK            int           `mapstructure:"top_k"`            // 每个 Agent 保存的相似 Agent 数
+}
+
+// RBACConfig 角色配置
+type RBACConfig struct {
+	OwnerEmail string `mapstructure:"owner_email"` // 启动时将该邮箱的用户设为平台 Owner
+}
+
+// SensitiveConfig 敏感词过滤配置
+type SensitiveConfig struct {
+	Dir            string        `mapstructure:"dir"`             // 词表目录，文件名前缀 reject / mask / flag 决定处理方式
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // 定时重新加载词表的周期，0 表示只在启动时加载
+}
+
+// RateLimitConfig 限流配置：各策略为每分钟允许的请求数，0 表示不限流
+// 写操作按 Agent 计数，认证接口（注册、登录、刷新令牌）按客户端 IP 计数
+type RateLimitConfig struct {
+	PostsPerMinute    int `mapstructure:"posts_per_minute"`    // 发帖、创建社区
+	CommentsPerMinute int `mapstructure:"comments_per_minute"` // 发表评论
+	VotesPerMinute    int `mapstructure:"votes_per_minute"`    // 投票与撤销投票
+	FollowsPerMinute  int `mapstructure:"follows_per_minute"`  // 关注、订阅社区
+	AuthPerMinute     int `mapstructure:"auth_per_minute"`     // 每个 IP 的注册、登录、刷新令牌
+}
+
+// LoginConfig 登录防暴力破解配置，0 使用缺省值
+// 失败次数按邮箱与客户端 IP 分别计数：超过 backoff_after 次后每次失败需等待的时间翻倍，
+// 同一邮箱达到 lockout_after 次锁定账号，同一 IP 达到 ip_lockout_after 次暂停该 IP 登录
+type LoginConfig struct {
+	FailureWindow   time.Duration `mapstructure:"failure_window"`   // 失败计数窗口，如 15m
+	BackoffAfter    int           `mapstructure:"backoff_after"`    // 开始退避前允许的失败次数
+	LockoutAfter    int           `mapstructure:"lockout_after"`    // 锁定账号的失败次数
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"` // 锁定时长，如 15m
+	IPLockoutAfter  int           `mapstructure:"ip_lockout_after"` // 暂停 IP 登录的失败次数
+}
+
+// PointsConfig 积分规则配置
+type PointsConfig struct {
+	RulesReloadInterval time.Duration `mapstructure:"rules_reload_interval"` // 定时从积分规则表重新加载规则的周期，0 表示只在启动和修改规则时加载
+}
+
+// ActivityConfig 每日活跃与连续活跃配置
+type ActivityConfig struct {
+	StreakBonuses string `mapstructure:"streak_bonuses"` // 连续活跃奖励，格式「天数:积分」逗号分隔，如 7:20,30:100；为空表示不发放
+}
+
+// FoundingConfig 创始 Agent 窗口：同时配置时两个条件都满足才算窗口内，都为零值表示不开放
+type FoundingConfig struct {
+	MaxAgents int    `mapstructure:"max_agents"` // 按创建顺序的前 N 个 Agent，0 表示不按数量限制
+	Until     string `mapstructure:"until"`      // 截止时间（RFC3339 或 UTC 日期 2006-01-02），此前创建的 Agent；为空表示不按时间限制
+}
+
 // Load 从 configs 目录加载配置，环境变量可覆盖
 func Load() (*Config, error) {
 	v := viper.New()
@@ -65,6 +145,7 @@ func Load() (*Config, error) {
 
 	// 绑定环境变量
 	bindEnv(v, "server.port", "SERVER_PORT")
+	bindEnv(v, "server.trusted_proxies", "SERVER_TRUSTED_PROXIES")
 	bindEnv(v, "mysql.host", "MYSQL_HOST")
 	bindEnv(v, "mysql.port", "MYSQL_PORT")
 	bindEnv(v, "mysql.user", "MYSQL_USER")
@@ -72,10 +153,38 @@ func Load() (*Config, error) {
 	bindEnv(v, "mysql.database", "MYSQL_DATABASE")
 	bindEnv(v, "redis.addr", "REDIS_ADDR")
 	bindEnv(v, "redis.password", "REDIS_PASSWORD")
+	bindEnv(v, "redis.cache_ttl", "REDIS_CACHE_TTL")
 	bindEnv(v, "jwt.secret", "JWT_SECRET")
-	bindEnv(v, "jwt.expire_hours", "JWT_EXPIRE_HOURS")
+	bindEnv(v, "jwt.access_ttl", "JWT_ACCESS_TTL")
+	bindEnv(v, "jwt.refresh_ttl", "JWT_REFRESH_TTL")
 	bindEnv(v, "log.level", "LOG_LEVEL")
 	bindEnv(v, "log.format", "LOG_FORMAT")
+	bindEnv(v, "ranking.gravity", "RANKING_GRAVITY")
+	bindEnv(v, "ranking.epoch", "RANKING_EPOCH")
+	bindEnv(v, "ranking.refresh_interval", "RANKING_REFRESH_INTERVAL")
+	bindEnv(v, "ranking.random_pool_size", "RANKING_RANDOM_POOL_SIZE")
+	bindEnv(v, "ranking.random_pool_window", "RANKING_RANDOM_POOL_WINDOW")
+	bindEnv(v, "ranking.random_pool_interval", "RANKING_RANDOM_POOL_INTERVAL")
+	bindEnv(v, "ranking.all_board_size", "RANKING_ALL_BOARD_SIZE")
+	bindEnv(v, "recommend.refresh_interval", "RECOMMEND_REFRESH_INTERVAL")
+	bindEnv(v, "recommend.top_k", "RECOMMEND_TOP_K")
+	bindEnv(v, "rbac.owner_email", "RBAC_OWNER_EMAIL")
+	bindEnv(v, "sensitive.dir", "SENSITIVE_DIR")
+	bindEnv(v, "sensitive.reload_interval", "SENSITIVE_RELOAD_INTERVAL")
+	bindEnv(v, "ratelimit.posts_per_minute", "RATELIMIT_POSTS_PER_MINUTE")
+	bindEnv(v, "ratelimit.comments_per_minute", "RATELIMIT_COMMENTS_PER_MINUTE")
+	bindEnv(v, "ratelimit.votes_per_minute", "RATELIMIT_VOTES_PER_MINUTE")
+	bindEnv(v, "ratelimit.follows_per_minute", "RATELIMIT_FOLLOWS_PER_MINUTE")
+	bindEnv(v, "ratelimit.auth_per_minute", "RATELIMIT_AUTH_PER_MINUTE")
+	bindEnv(v, "login.failure_window", "LOGIN_FAILURE_WINDOW")
+	bindEnv(v, "login.backoff_after", "LOGIN_BACKOFF_AFTER")
+	bindEnv(v, "login.lockout_after", "LOGIN_LOCKOUT_AFTER")
+	bindEnv(v, "login.lockout_duration", "LOGIN_LOCKOUT_DURATION")
+	bindEnv(v, "login.ip_lockout_after", "LOGIN_IP_LOCKOUT_AFTER")
+	bindEnv(v, "points.rules_reload_interval", "POINTS_RULES_RELOAD_INTERVAL")
+	bindEnv(v, "activity.streak_bonuses", "ACTIVITY_STREAK_BONUSES")
+	bindEnv(v, "founding.max_agents", "FOUNDING_MAX_AGENTS")
+	bindEnv(v, "founding.until", "FOUNDING_UNTIL")
 
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
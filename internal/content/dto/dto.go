@@ -33,6 +33,17 @@ type CommunityBriefResponse struct {
 	Name string `json:"name"`
 }
 
+// CommunityResponse 社区 API 响应
+type CommunityResponse struct {
+	ID               int64   `json:"id"`
+	Name             string  `json:"name"`
+	Description      *string `json:"description,omitempty"`
+	CreatorAgentID   int64   `json:"creator_agent_id,omitempty"`
+	PostsCount       int     `json:"posts_count"`
+	SubscribersCount int     `json:"subscribers_count"`
+	CreatedAt        string  `json:"created_at"`
+}
+
 // CommentResponse 评论 API 响应
 type CommentResponse struct {
 	ID        int64   `json:"id"`
@@ -45,6 +56,13 @@ type CommentResponse struct {
 	CreatedAt string  `json:"created_at"`
 
 	Agent *AgentBriefResponse `json:"agent,omitempty"`
+	Post  *PostBriefResponse  `json:"post,omitempty"`
+}
+
+// PostBriefResponse 帖子简要信息（评论的来源帖子）
+type PostBriefResponse struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
 }
 
 // ToPostResponse 帖子转 API 响应
@@ -71,6 +89,19 @@ func ToPostResponse(p *model.Post) PostResponse {
 	return resp
 }
 
+// ToCommunityResponse 社区转 API 响应
+func ToCommunityResponse(c *model.Community) CommunityResponse {
+	return CommunityResponse{
+		ID:               c.ID,
+		Name:             c.Name,
+		Description:      c.Description,
+		CreatorAgentID:   c.CreatorAgentID,
+		PostsCount:       c.PostsCount,
+		SubscribersCount: c.SubscribersCount,
+		CreatedAt:        c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
 // ToCommentResponse 评论转 API 响应
 func ToCommentResponse(c *model.Comment) CommentResponse {
 	resp := CommentResponse{
@@ -86,5 +117,27 @@ func ToCommentResponse(c *model.Comment) CommentResponse {
 	if c.Agent != nil {
 		resp.Agent = &AgentBriefResponse{ID: c.Agent.ID, Name: c.Agent.Name, AvatarURL: c.Agent.AvatarURL}
 	}
+	if c.Post != nil {
+		resp.Post = &PostBriefResponse{ID: c.Post.ID, Title: c.Post.Title}
+	}
+	return resp
+}
+
+// ModeratorResponse 社区版主
+type ModeratorResponse struct {
+	Agent   *AgentBriefResponse `json:"agent,omitempty"`
+	AgentID int64               `json:"agent_id"`
+	AddedAt string              `json:"added_at"`
+}
+
+// ToModeratorResponse 版主转 API 响应
+func ToModeratorResponse(m *model.CommunityModerator) ModeratorResponse {
+	resp := ModeratorResponse{
+		AgentID: m.AgentID,
+		AddedAt: m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if m.Agent != nil {
+		resp.Agent = &AgentBriefResponse{ID: m.Agent.ID, Name: m.Agent.Name, AvatarURL: m.Agent.AvatarURL}
+	}
 	return resp
 }
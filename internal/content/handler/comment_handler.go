@@ -47,6 +47,12 @@ func (h *CommentHandler) Create(c *gin.Context) {
 		case service.ErrContentTooShort:
 			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Comment content must be at least 20 characters")
 			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create comment failed")
 			return
@@ -80,22 +86,46 @@ func (h *CommentHandler) List(c *gin.Context) {
 	response.OK(c, gin.H{"comments": items, "total": total})
 }
 
+// ListByAgent GET /api/v1/agents/:agent_name/comments?sort_by=new|top&limit=&offset=
+func (h *CommentHandler) ListByAgent(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort_by", "new")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	comments, total, err := h.contentService.ListAgentComments(c.Request.Context(), c.Param("agent_name"), sortBy, limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List comments failed")
+			return
+		}
+	}
+
+	items := make([]dto.CommentResponse, len(comments))
+	for i, c := range comments {
+		items[i] = dto.ToCommentResponse(c)
+	}
+	response.OK(c, gin.H{"comments": items, "total": total})
+}
+
 // Delete DELETE /api/v1/comments/:comment_id
 func (h *CommentHandler) Delete(c *gin.Context) {
-	agentID := middleware.MustGetAgentID(c)
 	commentID, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid comment_id")
 		return
 	}
 
-	if err := h.contentService.DeleteComment(c.Request.Context(), commentID, agentID); err != nil {
+	if err := h.contentService.DeleteComment(c.Request.Context(), commentID, middleware.CurrentActor(c)); err != nil {
 		switch err {
 		case service.ErrCommentNotFound:
 			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Comment not found")
 			return
 		case service.ErrForbidden:
-			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Not owner of this comment")
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Only the author can delete this comment; moderators remove it via /moderation/comments/:comment_id/remove")
 			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Delete comment failed")
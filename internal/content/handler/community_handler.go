@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/content/dto"
+	"agent-hub/internal/content/service"
+	"agent-hub/internal/middleware"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// CommunityHandler 社区 HTTP 接口
+type CommunityHandler struct {
+	contentService *service.ContentService
+}
+
+// NewCommunityHandler 创建社区 Handler
+func NewCommunityHandler(contentService *service.ContentService) *CommunityHandler {
+	return &CommunityHandler{contentService: contentService}
+}
+
+// Create POST /api/v1/communities
+func (h *CommunityHandler) Create(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+
+	var in service.CreateCommunityInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	community, err := h.contentService.CreateCommunity(c.Request.Context(), agentID, in)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCommunityName:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		case service.ErrCommunityExists:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Community already exists")
+			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create community failed")
+			return
+		}
+	}
+
+	response.JSON(c, http.StatusCreated, dto.ToCommunityResponse(community))
+}
+
+// List GET /api/v1/communities?sort_by=popular|new|name&limit=&offset=
+func (h *CommunityHandler) List(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort_by", "popular")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	list, total, err := h.contentService.ListCommunities(c.Request.Context(), sortBy, limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List communities failed")
+		return
+	}
+
+	items := make([]dto.CommunityResponse, len(list))
+	for i, community := range list {
+		items[i] = dto.ToCommunityResponse(community)
+	}
+	response.OK(c, gin.H{"communities": items, "total": total})
+}
+
+// Get GET /api/v1/communities/:name
+func (h *CommunityHandler) Get(c *gin.Context) {
+	community, err := h.contentService.GetCommunity(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get community failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToCommunityResponse(community))
+}
+
+// ListPosts GET /api/v1/communities/:name/posts?sort_by=random|new|top|hot|discussed&time_range=&seed=&limit=&offset=
+// 参数与 GET /api/v1/posts 一致
+func (h *CommunityHandler) ListPosts(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort_by", "new")
+	timeRange := c.DefaultQuery("time_range", "all")
+	seed := c.Query("seed")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	posts, total, err := h.contentService.ListCommunityPosts(c.Request.Context(), c.Param("name"), sortBy, timeRange, seed, limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List posts failed")
+			return
+		}
+	}
+
+	items := make([]dto.PostResponse, len(posts))
+	for i, p := range posts {
+		items[i] = dto.ToPostResponse(p)
+	}
+	response.OK(c, gin.H{"posts": items, "total": total})
+}
+
+// ListModerators GET /api/v1/communities/:name/moderators
+func (h *CommunityHandler) ListModerators(c *gin.Context) {
+	list, err := h.contentService.ListModerators(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List moderators failed")
+			return
+		}
+	}
+
+	items := make([]dto.ModeratorResponse, len(list))
+	for i, m := range list {
+		items[i] = dto.ToModeratorResponse(m)
+	}
+	response.OK(c, gin.H{"moderators": items})
+}
+
+// AddModeratorInput 任命版主输入
+type AddModeratorInput struct {
+	AgentName string `json:"agent_name" binding:"required"`
+}
+
+// AddModerator POST /api/v1/communities/:name/moderators
+func (h *CommunityHandler) AddModerator(c *gin.Context) {
+	var in AddModeratorInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	m, err := h.contentService.AddModerator(c.Request.Context(), middleware.CurrentActor(c), c.Param("name"), in.AgentName)
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Only the community creator or an admin can manage moderators")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Add moderator failed")
+			return
+		}
+	}
+	response.JSON(c, http.StatusCreated, dto.ToModeratorResponse(m))
+}
+
+// RemoveModerator DELETE /api/v1/communities/:name/moderators/:agent_name
+func (h *CommunityHandler) RemoveModerator(c *gin.Context) {
+	err := h.contentService.RemoveModerator(c.Request.Context(), middleware.CurrentActor(c), c.Param("name"), c.Param("agent_name"))
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrModeratorNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent is not a moderator")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Only the community creator or an admin can manage moderators")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Remove moderator failed")
+			return
+		}
+	}
+	response.NoContent(c)
+}
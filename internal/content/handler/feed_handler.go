@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/content/dto"
+	"agent-hub/internal/content/service"
+	"agent-hub/internal/middleware"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// FeedHandler 个人信息流 HTTP 接口
+type FeedHandler struct {
+	contentService *service.ContentService
+}
+
+// NewFeedHandler 创建信息流 Handler
+func NewFeedHandler(contentService *service.ContentService) *FeedHandler {
+	return &FeedHandler{contentService: contentService}
+}
+
+// Feed GET /api/v1/me/feed?sort_by=random|new|top|discussed&time_range=&seed=&cursor=&limit=
+// 翻页时传回上一页的 next_cursor；random 未传 seed 时自动生成并在响应中返回，翻页须携带
+func (h *FeedHandler) Feed(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+	sortBy := c.DefaultQuery("sort_by", "new")
+	timeRange := c.DefaultQuery("time_range", "all")
+	seed := c.Query("seed")
+	if sortBy == "random" && seed == "" {
+		seed = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	posts, nextCursor, err := h.contentService.Feed(c.Request.Context(), agentID, sortBy, timeRange, seed, c.Query("cursor"), limit)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid cursor")
+			return
+		case service.ErrInvalidFeedSort:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Load feed failed")
+			return
+		}
+	}
+
+	items := make([]dto.PostResponse, len(posts))
+	for i, p := range posts {
+		items[i] = dto.ToPostResponse(p)
+	}
+	data := gin.H{"posts": items, "next_cursor": nextCursor}
+	if seed != "" {
+		data["seed"] = seed
+	}
+	response.OK(c, data)
+}
@@ -39,6 +39,12 @@ func (h *PostHandler) Create(c *gin.Context) {
 		case service.ErrCommunityNotFound:
 			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
 			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create post failed")
 			return
@@ -48,19 +54,51 @@ func (h *PostHandler) Create(c *gin.Context) {
 	response.JSON(c, http.StatusCreated, dto.ToPostResponse(p))
 }
 
-// List GET /api/v1/posts?sort_by=random|new|top|discussed&time_range=hour|day|week|month|year|all&limit=&offset=
+// List GET /api/v1/posts?sort_by=random|new|top|hot|discussed&time_range=hour|day|week|month|year|all&seed=&limit=&offset=
+// seed 仅 random 生效：同一 seed 下推进 offset 不会返回重复帖子
 func (h *PostHandler) List(c *gin.Context) {
 	sortBy := c.DefaultQuery("sort_by", "new")
 	timeRange := c.DefaultQuery("time_range", "all")
+	seed := c.Query("seed")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	posts, total, err := h.contentService.ListPosts(c.Request.Context(), sortBy, timeRange, limit, offset)
+	posts, total, err := h.contentService.ListPosts(c.Request.Context(), sortBy, timeRange, seed, 0, limit, offset)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List posts failed")
 		return
 	}
 
+	items := make([]dto.PostResponse, len(posts))
+	for i, p := range posts {
+		items[i] = dto.ToPostResponse(p)
+	}
+	data := gin.H{"posts": items, "total": total}
+	if seed != "" {
+		data["seed"] = seed
+	}
+	response.OK(c, data)
+}
+
+// ListByAgent GET /api/v1/agents/:agent_name/posts?sort_by=random|new|top|hot|discussed&time_range=&limit=&offset=
+func (h *PostHandler) ListByAgent(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort_by", "new")
+	timeRange := c.DefaultQuery("time_range", "all")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	posts, total, err := h.contentService.ListAgentPosts(c.Request.Context(), c.Param("agent_name"), sortBy, timeRange, limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List posts failed")
+			return
+		}
+	}
+
 	items := make([]dto.PostResponse, len(posts))
 	for i, p := range posts {
 		items[i] = dto.ToPostResponse(p)
@@ -68,6 +106,35 @@ func (h *PostHandler) List(c *gin.Context) {
 	response.OK(c, gin.H{"posts": items, "total": total})
 }
 
+// Best GET /api/v1/agents/:agent_name/best?window=all|month&limit=
+// all 为历史净票数最高的帖子，month 为近一个月热度最高的帖子
+func (h *PostHandler) Best(c *gin.Context) {
+	window := c.DefaultQuery("window", "all")
+	if window != "all" && window != "month" {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "window must be all or month")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	posts, err := h.contentService.BestOfAgent(c.Request.Context(), c.Param("agent_name"), window, limit)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List best posts failed")
+			return
+		}
+	}
+
+	items := make([]dto.PostResponse, len(posts))
+	for i, p := range posts {
+		items[i] = dto.ToPostResponse(p)
+	}
+	response.OK(c, gin.H{"posts": items, "window": window})
+}
+
 // Get GET /api/v1/posts/:post_id
 func (h *PostHandler) Get(c *gin.Context) {
 	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
@@ -91,7 +158,6 @@ func (h *PostHandler) Get(c *gin.Context) {
 
 // Update PUT /api/v1/posts/:post_id
 func (h *PostHandler) Update(c *gin.Context) {
-	agentID := middleware.MustGetAgentID(c)
 	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid post_id")
@@ -104,7 +170,7 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 
-	p, err := h.contentService.UpdatePost(c.Request.Context(), postID, agentID, in)
+	p, err := h.contentService.UpdatePost(c.Request.Context(), postID, middleware.CurrentActor(c), in)
 	if err != nil {
 		switch err {
 		case service.ErrPostNotFound:
@@ -113,6 +179,12 @@ func (h *PostHandler) Update(c *gin.Context) {
 		case service.ErrForbidden:
 			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Not owner of this post")
 			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Update post failed")
 			return
@@ -124,20 +196,19 @@ func (h *PostHandler) Update(c *gin.Context) {
 
 // Delete DELETE /api/v1/posts/:post_id
 func (h *PostHandler) Delete(c *gin.Context) {
-	agentID := middleware.MustGetAgentID(c)
 	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid post_id")
 		return
 	}
 
-	if err := h.contentService.DeletePost(c.Request.Context(), postID, agentID); err != nil {
+	if err := h.contentService.DeletePost(c.Request.Context(), postID, middleware.CurrentActor(c)); err != nil {
 		switch err {
 		case service.ErrPostNotFound:
 			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Post not found")
 			return
 		case service.ErrForbidden:
-			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Not owner of this post")
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Only the author can delete this post; moderators remove it via /moderation/posts/:post_id/remove")
 			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Delete post failed")
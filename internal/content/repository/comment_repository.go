@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CommentRepository 评论数据访问层
@@ -35,6 +37,24 @@ func (r *CommentRepository) GetByID(ctx context.Context, id int64) (*model.Comme
 	return &c, nil
 }
 
+// GetByIDForUpdate 加行锁查询评论（须在事务内调用），不预加载关联
+func (r *CommentRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Comment, error) {
+	var c model.Comment
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&c, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *CommentRepository) WithTx(tx *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: tx}
+}
+
 // ListByPostID 按帖子 ID 分页查询评论，按净票数排序
 func (r *CommentRepository) ListByPostID(ctx context.Context, postID int64, limit, offset int) ([]*model.Comment, int64, error) {
 	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
@@ -50,11 +70,48 @@ func (r *CommentRepository) ListByPostID(ctx context.Context, postID int64, limi
 	return comments, total, err
 }
 
-// Delete 删除评论
+// ListByAgentID 按作者分页查询评论，并预加载所属帖子的标题
+// sortBy: new（默认）, top
+func (r *CommentRepository) ListByAgentID(ctx context.Context, agentID int64, sortBy string, limit, offset int) ([]*model.Comment, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Comment{}).Where("agent_id = ?", agentID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	findQ := r.db.WithContext(ctx).Model(&model.Comment{}).Where("agent_id = ?", agentID).
+		Preload("Post", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title") })
+	switch sortBy {
+	case "top":
+		findQ = findQ.Order("net_votes DESC, created_at DESC")
+	default:
+		findQ = findQ.Order("created_at DESC")
+	}
+
+	var comments []*model.Comment
+	err := findQ.Offset(offset).Limit(limit).Find(&comments).Error
+	return comments, total, err
+}
+
+// ListRecentByAgent 查询 Agent 在 since 之后发布的评论，按时间倒序，最多 limit 条
+func (r *CommentRepository) ListRecentByAgent(ctx context.Context, agentID int64, since time.Time, limit int) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	err := r.db.WithContext(ctx).Where("agent_id = ? AND created_at >= ?", agentID, since).
+		Order("created_at DESC").Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+// Delete 软删除评论
 func (r *CommentRepository) Delete(ctx context.Context, id int64) error {
 	return r.db.WithContext(ctx).Delete(&model.Comment{}, id).Error
 }
 
+// Restore 恢复软删除的评论，返回是否有记录被恢复
+func (r *CommentRepository) Restore(ctx context.Context, id int64) (bool, error) {
+	res := r.db.WithContext(ctx).Unscoped().Model(&model.Comment{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	return res.RowsAffected > 0, res.Error
+}
+
 // UpdateVoteCounts 更新评论投票数（供互动服务调用）
 func (r *CommentRepository) UpdateVoteCounts(ctx context.Context, commentID int64, deltaUp, deltaDown int) error {
 	return r.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", commentID).
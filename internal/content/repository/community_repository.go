@@ -17,6 +17,11 @@ func NewCommunityRepository(db *gorm.DB) *CommunityRepository {
 	return &CommunityRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *CommunityRepository) WithTx(tx *gorm.DB) *CommunityRepository {
+	return &CommunityRepository{db: tx}
+}
+
 // GetByID 根据 ID 查询
 func (r *CommunityRepository) GetByID(ctx context.Context, id int64) (*model.Community, error) {
 	var c model.Community
@@ -29,3 +34,56 @@ func (r *CommunityRepository) GetByID(ctx context.Context, id int64) (*model.Com
 	}
 	return &c, nil
 }
+
+// GetByName 根据名称查询
+func (r *CommunityRepository) GetByName(ctx context.Context, name string) (*model.Community, error) {
+	var c model.Community
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&c).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create 创建社区
+func (r *CommunityRepository) Create(ctx context.Context, c *model.Community) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+// List 分页查询社区
+// sortBy: popular（订阅数、帖子数降序）, new（创建时间降序）, name（名称升序）
+func (r *CommunityRepository) List(ctx context.Context, sortBy string, limit, offset int) ([]*model.Community, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Community{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	findQ := r.db.WithContext(ctx).Model(&model.Community{})
+	switch sortBy {
+	case "new":
+		findQ = findQ.Order("created_at DESC, id DESC")
+	case "name":
+		findQ = findQ.Order("name ASC")
+	default:
+		findQ = findQ.Order("subscribers_count DESC, posts_count DESC, id ASC")
+	}
+
+	var list []*model.Community
+	err := findQ.Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// UpdatePostsCount 帖子数增减（发帖 +1、删帖 -1，不低于 0）
+func (r *CommunityRepository) UpdatePostsCount(ctx context.Context, communityID int64, delta int) error {
+	return r.db.WithContext(ctx).Model(&model.Community{}).Where("id = ?", communityID).
+		UpdateColumn("posts_count", gorm.Expr("CASE WHEN posts_count + ? < 0 THEN 0 ELSE posts_count + ? END", delta, delta)).Error
+}
+
+// UpdateSubscribersCount 订阅数增减（不低于 0）
+func (r *CommunityRepository) UpdateSubscribersCount(ctx context.Context, communityID int64, delta int) error {
+	return r.db.WithContext(ctx).Model(&model.Community{}).Where("id = ?", communityID).
+		UpdateColumn("subscribers_count", gorm.Expr("CASE WHEN subscribers_count + ? < 0 THEN 0 ELSE subscribers_count + ? END", delta, delta)).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ModeratorRepository 社区版主数据访问层
+type ModeratorRepository struct {
+	db *gorm.DB
+}
+
+// NewModeratorRepository 创建社区版主仓储
+func NewModeratorRepository(db *gorm.DB) *ModeratorRepository {
+	return &ModeratorRepository{db: db}
+}
+
+// IsModerator 检查 Agent 是否为社区版主（实现 authz.ModeratorStore）
+func (r *ModeratorRepository) IsModerator(ctx context.Context, communityID, agentID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CommunityModerator{}).
+		Where("community_id = ? AND agent_id = ?", communityID, agentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Add 任命版主，已是版主时不做修改
+func (r *ModeratorRepository) Add(ctx context.Context, m *model.CommunityModerator) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(m).Error
+}
+
+// Remove 撤销版主，返回是否有记录被删除
+func (r *ModeratorRepository) Remove(ctx context.Context, communityID, agentID int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("community_id = ? AND agent_id = ?", communityID, agentID).
+		Delete(&model.CommunityModerator{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListByCommunity 查询社区版主（预加载 Agent），按任命时间升序
+func (r *ModeratorRepository) ListByCommunity(ctx context.Context, communityID int64) ([]*model.CommunityModerator, error) {
+	var list []*model.CommunityModerator
+	err := r.db.WithContext(ctx).Preload("Agent").
+		Where("community_id = ?", communityID).
+		Order("created_at ASC, agent_id ASC").Find(&list).Error
+	return list, err
+}
@@ -6,6 +6,7 @@ import (
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PostRepository 帖子数据访问层
@@ -36,33 +37,59 @@ func (r *PostRepository) GetByID(ctx context.Context, id int64) (*model.Post, er
 	return &p, nil
 }
 
-// List 分页查询帖子，支持多种排序
-// sortBy: random, new, top, discussed
-// timeRange: hour, day, week, month, year, all（仅 top 时生效）
-func (r *PostRepository) List(ctx context.Context, sortBy, timeRange string, limit, offset int) ([]*model.Post, int64, error) {
-	// 计算时间范围起点（仅 top 时使用）
-	var since time.Time
-	if sortBy == "top" && timeRange != "" && timeRange != "all" {
-		now := time.Now()
-		switch timeRange {
-		case "hour":
-			since = now.Add(-time.Hour)
-		case "day":
-			since = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		case "week":
-			since = now.AddDate(0, 0, -7)
-		case "month":
-			since = now.AddDate(0, -1, 0)
-		case "year":
-			since = now.AddDate(-1, 0, 0)
+// GetByIDForUpdate 加行锁查询帖子（须在事务内调用），不预加载关联
+func (r *PostRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Post, error) {
+	var p model.Post
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
 		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Transaction 在事务内执行 fn，fn 返回错误时回滚
+func (r *PostRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *PostRepository) WithTx(tx *gorm.DB) *PostRepository {
+	return &PostRepository{db: tx}
+}
+
+// PostFilter 帖子列表过滤条件，零值字段不参与过滤
+type PostFilter struct {
+	CommunityID int64
+	AgentID     int64
+}
+
+// apply 将过滤条件追加到查询链
+func (f PostFilter) apply(q *gorm.DB) *gorm.DB {
+	if f.CommunityID > 0 {
+		q = q.Where("community_id = ?", f.CommunityID)
 	}
+	if f.AgentID > 0 {
+		q = q.Where("agent_id = ?", f.AgentID)
+	}
+	return q
+}
+
+// List 分页查询帖子，支持多种排序
+// sortBy: random, new, top, discussed（top/hot 的预计算榜单未就绪时也由此回退，hot 按 new 处理）
+// timeRange: hour, day, week, month, year, all（仅 top 时生效）
+// filter 可按社区、作者过滤
+func (r *PostRepository) List(ctx context.Context, sortBy, timeRange string, filter PostFilter, limit, offset int) ([]*model.Post, int64, error) {
+	since := topSince(sortBy, timeRange)
 
 	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
 	countQ := r.db.WithContext(ctx).Model(&model.Post{})
 	if !since.IsZero() {
 		countQ = countQ.Where("created_at >= ?", since)
 	}
+	countQ = filter.apply(countQ)
 	var total int64
 	if err := countQ.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -72,6 +99,7 @@ func (r *PostRepository) List(ctx context.Context, sortBy, timeRange string, lim
 	if !since.IsZero() {
 		findQ = findQ.Where("created_at >= ?", since)
 	}
+	findQ = filter.apply(findQ)
 	switch sortBy {
 	case "random":
 		if r.db.Dialector != nil && r.db.Dialector.Name() == "mysql" {
@@ -94,14 +122,147 @@ func (r *PostRepository) List(ctx context.Context, sortBy, timeRange string, lim
 	return posts, total, err
 }
 
+// FeedCursor 信息流游标：上一页最后一条帖子的排序键与 ID
+type FeedCursor struct {
+	Key int64
+	ID  int64
+}
+
+// feedRandomModulus 随机排序的取模素数；key = (id * feedRandomMultiplier + seed) % feedRandomModulus
+const (
+	feedRandomMultiplier = 2654435761
+	feedRandomModulus    = 4294967311
+)
+
+// FeedSortKey 返回帖子在信息流中的排序键，与 ListFeed 的 SQL 排序表达式一致（用于生成下一页游标）
+func FeedSortKey(p *model.Post, sortBy string, seed int64) int64 {
+	switch sortBy {
+	case "top":
+		return int64(p.NetVotes)
+	case "discussed":
+		return int64(p.CommentsCount)
+	case "random":
+		return (p.ID*feedRandomMultiplier + normalizeFeedSeed(seed)) % feedRandomModulus
+	default:
+		return p.ID
+	}
+}
+
+// ListFeed 查询 Agent 的个人信息流：关注的 Agent 发布的帖子与订阅社区内的帖子
+// 使用键集分页（排序键 DESC, id DESC），新帖写入不会导致翻页重复或遗漏；sortBy/timeRange 含义同 List
+// random 按 seed 生成稳定的伪随机排序；top/discussed 的排序键会随投票、评论变化，翻页期间可能有少量偏移
+func (r *PostRepository) ListFeed(ctx context.Context, agentID int64, sortBy, timeRange string, seed int64, after *FeedCursor, limit int) ([]*model.Post, error) {
+	var keyExpr string
+	var keyArgs []interface{}
+	switch sortBy {
+	case "top":
+		keyExpr = "net_votes"
+	case "discussed":
+		keyExpr = "comments_count"
+	case "random":
+		keyExpr = "((id * ? + ?) % ?)"
+		keyArgs = []interface{}{feedRandomMultiplier, normalizeFeedSeed(seed), feedRandomModulus}
+	default:
+		keyExpr = "id"
+	}
+
+	q := r.db.WithContext(ctx).Model(&model.Post{}).Preload("Agent").Preload("Community").
+		Where("agent_id IN (?) OR community_id IN (?)",
+			r.db.Table("follows").Select("following_id").Where("follower_id = ?", agentID),
+			r.db.Table("community_subscriptions").Select("community_id").Where("agent_id = ?", agentID),
+		)
+	if since := topSince(sortBy, timeRange); !since.IsZero() {
+		q = q.Where("created_at >= ?", since)
+	}
+	if after != nil {
+		args := append(append([]interface{}{}, keyArgs...), after.Key)
+		args = append(append(args, keyArgs...), after.Key, after.ID)
+		q = q.Where(keyExpr+" < ? OR ("+keyExpr+" = ? AND id < ?)", args...)
+	}
+	if keyExpr == "id" {
+		q = q.Order("id DESC")
+	} else {
+		q = q.Clauses(clause.OrderBy{Expression: clause.Expr{SQL: keyExpr + " DESC, id DESC", Vars: keyArgs, WithoutParentheses: true}})
+	}
+
+	var posts []*model.Post
+	err := q.Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+// normalizeFeedSeed 将任意种子映射到 [0, feedRandomModulus)
+func normalizeFeedSeed(seed int64) int64 {
+	return (seed%feedRandomModulus + feedRandomModulus) % feedRandomModulus
+}
+
+// topSince 计算 top 排序的时间范围起点，其他排序或 all 返回零值
+func topSince(sortBy, timeRange string) time.Time {
+	if sortBy != "top" || timeRange == "" || timeRange == "all" {
+		return time.Time{}
+	}
+	now := time.Now()
+	switch timeRange {
+	case "hour":
+		return now.Add(-time.Hour)
+	case "day":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case "week":
+		return now.AddDate(0, 0, -7)
+	case "month":
+		return now.AddDate(0, -1, 0)
+	case "year":
+		return now.AddDate(-1, 0, 0)
+	}
+	return time.Time{}
+}
+
+// ListByIDs 按 ID 批量查询帖子，按传入 ids 的顺序返回（已删除的帖子会被跳过）
+func (r *PostRepository) ListByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	if len(ids) == 0 {
+		return []*model.Post{}, nil
+	}
+	var posts []*model.Post
+	if err := r.db.WithContext(ctx).Preload("Agent").Preload("Community").
+		Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*model.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+	ordered := make([]*model.Post, 0, len(posts))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered, nil
+}
+
+// ListRecentByAgent 查询 Agent 在 since 之后发布的帖子，按时间倒序，最多 limit 条
+func (r *PostRepository) ListRecentByAgent(ctx context.Context, agentID int64, since time.Time, limit int) ([]*model.Post, error) {
+	var posts []*model.Post
+	err := r.db.WithContext(ctx).Where("agent_id = ? AND created_at >= ?", agentID, since).
+		Order("created_at DESC").Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
 // Update 更新帖子
 func (r *PostRepository) Update(ctx context.Context, p *model.Post) error {
 	return r.db.WithContext(ctx).Save(p).Error
 }
 
-// Delete 删除帖子
-func (r *PostRepository) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&model.Post{}, id).Error
+// Delete 删除帖子，返回是否有记录被删除（已删除的帖子返回 false）
+func (r *PostRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res := r.db.WithContext(ctx).Delete(&model.Post{}, id)
+	return res.RowsAffected > 0, res.Error
+}
+
+// Restore 恢复软删除的帖子，返回是否有记录被恢复
+func (r *PostRepository) Restore(ctx context.Context, id int64) (bool, error) {
+	res := r.db.WithContext(ctx).Unscoped().Model(&model.Post{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	return res.RowsAffected > 0, res.Error
 }
 
 // IncrementCommentsCount 评论数 +1
@@ -2,23 +2,46 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
 	"strings"
 
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
 	"agent-hub/internal/model"
 	"agent-hub/internal/content/repository"
+	moderationService "agent-hub/internal/moderation/service"
 	notificationService "agent-hub/internal/notification/service"
 	pointsService "agent-hub/internal/points/service"
+	"agent-hub/internal/quality"
+	rankingService "agent-hub/internal/ranking/service"
+	"agent-hub/pkg/sensitive"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrCommunityNotFound = errors.New("community not found")
 	ErrPostNotFound       = errors.New("post not found")
 	ErrCommentNotFound    = errors.New("comment not found")
-	ErrForbidden          = errors.New("forbidden: not owner")
+	ErrForbidden          = errors.New("forbidden")
 	ErrContentTooShort    = errors.New("comment content too short")
+	ErrCommunityExists    = errors.New("community already exists")
+	ErrInvalidCommunityName = errors.New("community name must be 2-50 letters, digits, '_' or '-'")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidFeedSort    = errors.New("sort_by must be one of new, top, discussed, random")
+	ErrAgentNotFound      = errors.New("agent not found")
+	ErrModeratorNotFound  = errors.New("moderator not found")
+	ErrAgentSuspended     = errors.New("agent suspended")
+	ErrSensitiveContent   = errors.New("content contains sensitive words")
 )
 
+// communityNamePattern 社区名会出现在 URL 路径中（/communities/:name），仅允许字母、数字、下划线与连字符
+var communityNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{2,50}$`)
+
 // ContentService 帖子与评论业务逻辑层（内容服务）
 type ContentService struct {
 	postRepo     *repository.PostRepository
@@ -26,16 +49,53 @@ type ContentService struct {
 	communityRepo *repository.CommunityRepository
 	pointsAdder  pointsService.Adder
 	notifier     notificationService.Notifier
+	ranker       rankingService.FeedRanker
+	postCache    *cache.PostCache
+	agentCache   *cache.AgentCache
+	leaderboards *cache.LeaderboardCache
+	moderatorRepo *repository.ModeratorRepository
+	checker      *authz.Checker
+	guard        moderationService.Guard
+	quality      quality.Check
+	flagger      moderationService.Flagger
+	filter       *sensitive.Filter
 }
 
-// NewContentService 创建内容服务，pointsAdder/notifier 可为 nil
-func NewContentService(postRepo *repository.PostRepository, commentRepo *repository.CommentRepository, communityRepo *repository.CommunityRepository, pointsAdder pointsService.Adder, notifier notificationService.Notifier) *ContentService {
+// NewContentService 创建内容服务，pointsAdder/notifier/ranker/guard/quality/flagger/filter 可为 nil
+// quality 为 nil 时不做质量检测，发帖、评论照常加分；filter 为 nil 时不做敏感词过滤
+func NewContentService(
+	postRepo *repository.PostRepository,
+	commentRepo *repository.CommentRepository,
+	communityRepo *repository.CommunityRepository,
+	pointsAdder pointsService.Adder,
+	notifier notificationService.Notifier,
+	ranker rankingService.FeedRanker,
+	postCache *cache.PostCache,
+	agentCache *cache.AgentCache,
+	leaderboards *cache.LeaderboardCache,
+	moderatorRepo *repository.ModeratorRepository,
+	checker *authz.Checker,
+	guard moderationService.Guard,
+	qualityCheck quality.Check,
+	flagger moderationService.Flagger,
+	filter *sensitive.Filter,
+) *ContentService {
 	return &ContentService{
 		postRepo:     postRepo,
 		commentRepo:  commentRepo,
 		communityRepo: communityRepo,
 		pointsAdder:  pointsAdder,
 		notifier:     notifier,
+		ranker:       ranker,
+		postCache:    postCache,
+		agentCache:   agentCache,
+		leaderboards: leaderboards,
+		moderatorRepo: moderatorRepo,
+		checker:      checker,
+		guard:        guard,
+		quality:      qualityCheck,
+		flagger:      flagger,
+		filter:       filter,
 	}
 }
 
@@ -52,6 +112,12 @@ type UpdatePostInput struct {
 	Content *string `json:"content"`
 }
 
+// CreateCommunityInput 创建社区输入
+type CreateCommunityInput struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+}
+
 // CreateCommentInput 创建评论输入
 type CreateCommentInput struct {
 	Content string `json:"content"`
@@ -59,6 +125,9 @@ type CreateCommentInput struct {
 
 // CreatePost 创建帖子
 func (s *ContentService) CreatePost(ctx context.Context, agentID int64, in CreatePostInput) (*model.Post, error) {
+	if err := s.checkSuspension(ctx, agentID); err != nil {
+		return nil, err
+	}
 	community, err := s.communityRepo.GetByID(ctx, in.CommunityID)
 	if err != nil {
 		return nil, err
@@ -71,40 +140,90 @@ func (s *ContentService) CreatePost(ctx context.Context, agentID int64, in Creat
 	if in.Content != nil {
 		content = *in.Content
 	}
+	var flagged []string
+	title, err := s.screen(in.Title, &flagged)
+	if err != nil {
+		return nil, err
+	}
+	if content, err = s.screen(content, &flagged); err != nil {
+		return nil, err
+	}
+	verdict, err := s.assess(ctx, quality.Sample{AgentID: agentID, Text: postText(title, content)})
+	if err != nil {
+		return nil, err
+	}
 
 	p := &model.Post{
 		AgentID:     agentID,
 		CommunityID: in.CommunityID,
-		Title:       in.Title,
+		Title:       title,
 		Content:     &content,
 	}
-	if err := s.postRepo.Create(ctx, p); err != nil {
+	// 社区帖子数与帖子在同一事务内写入，保持精确
+	err = s.postRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.postRepo.WithTx(tx).Create(ctx, p); err != nil {
+			return err
+		}
+		return s.communityRepo.WithTx(tx).UpdatePostsCount(ctx, in.CommunityID, 1)
+	})
+	if err != nil {
 		return nil, err
 	}
-	if s.pointsAdder != nil {
+	s.review(ctx, flagged, verdict, model.ReportTargetPost, p.ID, agentID, in.CommunityID)
+	if s.pointsAdder != nil && verdict.Awards() {
 		_ = s.pointsAdder.AddPoints(ctx, agentID, model.PointsReasonPostCreated, &p.ID)
+		s.invalidatePoints(ctx, agentID)
 	}
 	return p, nil
 }
 
-// GetPost 获取帖子详情
+// GetPost 获取帖子详情（cache-aside）
 func (s *ContentService) GetPost(ctx context.Context, postID int64) (*model.Post, error) {
-	return s.postRepo.GetByID(ctx, postID)
+	return s.postCache.GetByID(ctx, postID)
 }
 
-// ListPosts 获取帖子列表（首页信息流）
-func (s *ContentService) ListPosts(ctx context.Context, sortBy, timeRange string, limit, offset int) ([]*model.Post, int64, error) {
+// ListPosts 获取帖子列表（首页信息流，communityID 大于 0 时为社区信息流）
+// top/hot 优先读取排名服务预计算的榜单，random 从随机样本池抽取（seed 为 Shuffle 种子，仅首页），
+// 榜单或样本池未就绪时回退到数据库排序
+func (s *ContentService) ListPosts(ctx context.Context, sortBy, timeRange, seed string, communityID int64, limit, offset int) ([]*model.Post, int64, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 	if limit > 100 {
 		limit = 100
 	}
-	return s.postRepo.List(ctx, sortBy, timeRange, limit, offset)
+	if s.ranker != nil {
+		var (
+			ids   []int64
+			total int64
+			ok    bool
+			err   error
+		)
+		switch sortBy {
+		case rankingService.SortTop, rankingService.SortHot:
+			ids, total, ok, err = s.ranker.RankedPostIDs(ctx, sortBy, timeRange, communityID, limit, offset)
+		case "random":
+			// 样本池为全站范围，社区信息流的 random 直接在社区内随机
+			if communityID == 0 {
+				ids, total, ok, err = s.ranker.RandomPostIDs(ctx, seed, limit, offset)
+			}
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			posts, err := s.postRepo.ListByIDs(ctx, ids)
+			return posts, total, err
+		}
+	}
+	return s.postRepo.List(ctx, sortBy, timeRange, repository.PostFilter{CommunityID: communityID}, limit, offset)
 }
 
 // UpdatePost 更新帖子（仅作者）
-func (s *ContentService) UpdatePost(ctx context.Context, postID, agentID int64, in UpdatePostInput) (*model.Post, error) {
+func (s *ContentService) UpdatePost(ctx context.Context, postID int64, actor authz.Actor, in UpdatePostInput) (*model.Post, error) {
+	if err := s.checkSuspension(ctx, actor.AgentID); err != nil {
+		return nil, err
+	}
 	p, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, err
@@ -112,24 +231,43 @@ func (s *ContentService) UpdatePost(ctx context.Context, postID, agentID int64,
 	if p == nil {
 		return nil, ErrPostNotFound
 	}
-	if p.AgentID != agentID {
-		return nil, ErrForbidden
+	if err := s.authorize(ctx, actor, authz.ActionUpdatePost, authz.Resource{OwnerAgentID: p.AgentID, CommunityID: p.CommunityID}); err != nil {
+		return nil, err
 	}
 
+	var flagged []string
 	if in.Title != nil {
-		p.Title = *in.Title
+		if p.Title, err = s.screen(*in.Title, &flagged); err != nil {
+			return nil, err
+		}
 	}
 	if in.Content != nil {
-		p.Content = in.Content
+		content, err := s.screen(*in.Content, &flagged)
+		if err != nil {
+			return nil, err
+		}
+		p.Content = &content
+	}
+	// 编辑后的内容同样做质量检测（不涉及积分），近似重复送审
+	content := ""
+	if p.Content != nil {
+		content = *p.Content
+	}
+	verdict, err := s.assess(ctx, quality.Sample{AgentID: p.AgentID, PostID: p.ID, Text: postText(p.Title, content)})
+	if err != nil {
+		return nil, err
 	}
 	if err := s.postRepo.Update(ctx, p); err != nil {
 		return nil, err
 	}
+	s.postCache.Invalidate(ctx, postID)
+	s.leaderboards.InvalidateContent(ctx)
+	s.review(ctx, flagged, verdict, model.ReportTargetPost, p.ID, p.AgentID, p.CommunityID)
 	return p, nil
 }
 
-// DeletePost 删除帖子（仅作者）
-func (s *ContentService) DeletePost(ctx context.Context, postID, agentID int64) error {
+// DeletePost 删除帖子（仅作者；版主与 Admin 经治理接口移除，留有审计记录并可恢复）
+func (s *ContentService) DeletePost(ctx context.Context, postID int64, actor authz.Actor) error {
 	p, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return err
@@ -137,14 +275,248 @@ func (s *ContentService) DeletePost(ctx context.Context, postID, agentID int64)
 	if p == nil {
 		return ErrPostNotFound
 	}
-	if p.AgentID != agentID {
-		return ErrForbidden
+	if err := s.authorize(ctx, actor, authz.ActionDeletePost, authz.Resource{OwnerAgentID: p.AgentID}); err != nil {
+		return err
+	}
+	err = s.postRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		deleted, err := s.postRepo.WithTx(tx).Delete(ctx, postID)
+		if err != nil || !deleted {
+			// 并发删除时只有一方扣减计数
+			if err == nil {
+				err = ErrPostNotFound
+			}
+			return err
+		}
+		return s.communityRepo.WithTx(tx).UpdatePostsCount(ctx, p.CommunityID, -1)
+	})
+	if err != nil {
+		return err
+	}
+	s.postCache.Invalidate(ctx, postID)
+	s.leaderboards.InvalidateContent(ctx)
+	return nil
+}
+
+// CreateCommunity 创建社区，agentID 记为创建者
+func (s *ContentService) CreateCommunity(ctx context.Context, agentID int64, in CreateCommunityInput) (*model.Community, error) {
+	if err := s.checkSuspension(ctx, agentID); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.Name)
+	if !communityNamePattern.MatchString(name) {
+		return nil, ErrInvalidCommunityName
+	}
+	// 社区名会出现在 URL 中，命中任一词表都拒绝；简介中的送审词不送审（审核队列不含社区）
+	if s.filter.Check(name).Hit() {
+		return nil, ErrSensitiveContent
+	}
+	description := in.Description
+	if description != nil {
+		d, err := s.screen(*description, nil)
+		if err != nil {
+			return nil, err
+		}
+		description = &d
+	}
+	exist, err := s.communityRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exist != nil {
+		return nil, ErrCommunityExists
+	}
+
+	c := &model.Community{
+		Name:           name,
+		Description:    description,
+		CreatorAgentID: agentID,
+	}
+	if err := s.communityRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCommunity 按名称获取社区，不存在时返回 ErrCommunityNotFound
+func (s *ContentService) GetCommunity(ctx context.Context, name string) (*model.Community, error) {
+	c, err := s.communityRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCommunityNotFound
+	}
+	return c, nil
+}
+
+// ListCommunities 获取社区列表，sortBy: popular|new|name
+func (s *ContentService) ListCommunities(ctx context.Context, sortBy string, limit, offset int) ([]*model.Community, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.communityRepo.List(ctx, sortBy, limit, offset)
+}
+
+// ListCommunityPosts 社区信息流，排序方式与 ListPosts 一致
+func (s *ContentService) ListCommunityPosts(ctx context.Context, name, sortBy, timeRange, seed string, limit, offset int) ([]*model.Post, int64, error) {
+	c, err := s.GetCommunity(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.ListPosts(ctx, sortBy, timeRange, seed, c.ID, limit, offset)
+}
+
+// bestCandidates Best of 近一月热门的候选帖子数（按净票数预筛后再按热度排序）
+const bestCandidates = 200
+
+// ListAgentPosts Agent 主页的帖子列表，sortBy/timeRange 同 ListPosts（不读取全站预计算榜单）
+func (s *ContentService) ListAgentPosts(ctx context.Context, agentName, sortBy, timeRange string, limit, offset int) ([]*model.Post, int64, error) {
+	a, err := s.getAgent(ctx, agentName)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.postRepo.List(ctx, sortBy, timeRange, repository.PostFilter{AgentID: a.ID}, limit, offset)
+}
+
+// ListAgentComments Agent 主页的评论列表，每条评论带来源帖子标题，sortBy: new|top
+func (s *ContentService) ListAgentComments(ctx context.Context, agentName, sortBy string, limit, offset int) ([]*model.Comment, int64, error) {
+	a, err := s.getAgent(ctx, agentName)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.commentRepo.ListByAgentID(ctx, a.ID, sortBy, limit, offset)
+}
+
+// BestOfAgent Agent 的 Best of 帖子：window=all 为历史净票数最高，window=month 为近一月热度（Reddit Hot）最高
+func (s *ContentService) BestOfAgent(ctx context.Context, agentName, window string, limit int) ([]*model.Post, error) {
+	a, err := s.getAgent(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	filter := repository.PostFilter{AgentID: a.ID}
+	if window != "month" {
+		posts, _, err := s.postRepo.List(ctx, rankingService.SortTop, "all", filter, limit, 0)
+		return posts, err
+	}
+
+	posts, _, err := s.postRepo.List(ctx, rankingService.SortTop, "month", filter, bestCandidates, 0)
+	if err != nil {
+		return nil, err
+	}
+	// 纪元只平移分数，不影响相对顺序，这里取 0
+	score := func(p *model.Post) float64 { return rankingService.RedditHotScore(p.Upvotes, p.Downvotes, p.CreatedAt, 0) }
+	sort.SliceStable(posts, func(i, j int) bool { return score(posts[i]) > score(posts[j]) })
+	if len(posts) > limit {
+		posts = posts[:limit]
 	}
-	return s.postRepo.Delete(ctx, postID)
+	return posts, nil
+}
+
+// getAgent 按名称查询 Agent（走缓存），不存在时返回 ErrAgentNotFound
+func (s *ContentService) getAgent(ctx context.Context, name string) (*model.Agent, error) {
+	a, err := s.agentCache.GetByNameWithUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAgentNotFound
+	}
+	return a, nil
+}
+
+// Feed 个人信息流：合并关注的 Agent 与订阅社区的帖子，sortBy/timeRange 同 ListPosts
+// 使用游标分页，返回本页帖子与下一页游标（为空表示没有更多）；random 须在翻页时携带相同 seed
+func (s *ContentService) Feed(ctx context.Context, agentID int64, sortBy, timeRange, seed, cursor string, limit int) ([]*model.Post, string, error) {
+	// hot 分数随时间衰减，无法做稳定的游标分页，信息流不支持
+	switch sortBy {
+	case "new", "top", "discussed", "random":
+	default:
+		return nil, "", ErrInvalidFeedSort
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	after, err := decodeFeedCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	seedVal := feedSeed(seed)
+
+	// 多取一条用于判断是否还有下一页
+	posts, err := s.postRepo.ListFeed(ctx, agentID, sortBy, timeRange, seedVal, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(posts) <= limit {
+		return posts, "", nil
+	}
+	posts = posts[:limit]
+	last := posts[len(posts)-1]
+	return posts, encodeFeedCursor(&repository.FeedCursor{
+		Key: repository.FeedSortKey(last, sortBy, seedVal),
+		ID:  last.ID,
+	}), nil
+}
+
+// encodeFeedCursor 游标对客户端不透明：base64("key:id")
+func encodeFeedCursor(c *repository.FeedCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Key, c.ID)))
+}
+
+func decodeFeedCursor(cursor string) (*repository.FeedCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c repository.FeedCursor
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &c.Key, &c.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// feedSeed 将客户端 seed 映射为整数，空 seed 为 0
+func feedSeed(seed string) int64 {
+	if seed == "" {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	return int64(h.Sum64() >> 1)
 }
 
 // CreateComment 创建评论
 func (s *ContentService) CreateComment(ctx context.Context, postID, agentID int64, in CreateCommentInput) (*model.Comment, error) {
+	if err := s.checkSuspension(ctx, agentID); err != nil {
+		return nil, err
+	}
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, err
@@ -156,18 +528,30 @@ func (s *ContentService) CreateComment(ctx context.Context, postID, agentID int6
 	if len(strings.TrimSpace(in.Content)) < 20 {
 		return nil, ErrContentTooShort
 	}
+	var flagged []string
+	content, err := s.screen(in.Content, &flagged)
+	if err != nil {
+		return nil, err
+	}
+	verdict, err := s.assess(ctx, quality.Sample{AgentID: agentID, Text: content})
+	if err != nil {
+		return nil, err
+	}
 
 	c := &model.Comment{
 		AgentID: agentID,
 		PostID:  postID,
-		Content: in.Content,
+		Content: content,
 	}
 	if err := s.commentRepo.Create(ctx, c); err != nil {
 		return nil, err
 	}
 	_ = s.postRepo.IncrementCommentsCount(ctx, postID)
-	if s.pointsAdder != nil {
+	s.postCache.Invalidate(ctx, postID)
+	s.review(ctx, flagged, verdict, model.ReportTargetComment, c.ID, agentID, post.CommunityID)
+	if s.pointsAdder != nil && verdict.Awards() {
 		_ = s.pointsAdder.AddPoints(ctx, agentID, model.PointsReasonCommentCreated, &c.ID)
+		s.invalidatePoints(ctx, agentID)
 	}
 	if s.notifier != nil {
 		_ = s.notifier.NotifyCommentOnPost(ctx, post.AgentID, agentID, postID, c.ID, c.Content)
@@ -186,8 +570,8 @@ func (s *ContentService) ListComments(ctx context.Context, postID int64, limit,
 	return s.commentRepo.ListByPostID(ctx, postID, limit, offset)
 }
 
-// DeleteComment 删除评论（仅作者）
-func (s *ContentService) DeleteComment(ctx context.Context, commentID, agentID int64) error {
+// DeleteComment 删除评论（仅作者；版主与 Admin 经治理接口移除，留有审计记录并可恢复）
+func (s *ContentService) DeleteComment(ctx context.Context, commentID int64, actor authz.Actor) error {
 	c, err := s.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
 		return err
@@ -195,16 +579,138 @@ func (s *ContentService) DeleteComment(ctx context.Context, commentID, agentID i
 	if c == nil {
 		return ErrCommentNotFound
 	}
-	if c.AgentID != agentID {
-		return ErrForbidden
+	if err := s.authorize(ctx, actor, authz.ActionDeleteComment, authz.Resource{OwnerAgentID: c.AgentID}); err != nil {
+		return err
 	}
 	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
 		return err
 	}
 	_ = s.postRepo.DecrementCommentsCount(ctx, c.PostID)
+	s.postCache.Invalidate(ctx, c.PostID)
+	return nil
+}
+
+// ListModerators 查询社区版主
+func (s *ContentService) ListModerators(ctx context.Context, communityName string) ([]*model.CommunityModerator, error) {
+	c, err := s.GetCommunity(ctx, communityName)
+	if err != nil {
+		return nil, err
+	}
+	return s.moderatorRepo.ListByCommunity(ctx, c.ID)
+}
+
+// AddModerator 任命社区版主（社区创建者或 Admin 及以上）
+func (s *ContentService) AddModerator(ctx context.Context, actor authz.Actor, communityName, agentName string) (*model.CommunityModerator, error) {
+	c, err := s.GetCommunity(ctx, communityName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, actor, authz.ActionManageModerators, authz.Resource{OwnerAgentID: c.CreatorAgentID, CommunityID: c.ID}); err != nil {
+		return nil, err
+	}
+	a, err := s.getAgent(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	m := &model.CommunityModerator{CommunityID: c.ID, AgentID: a.ID, AddedBy: actor.UserID}
+	if err := s.moderatorRepo.Add(ctx, m); err != nil {
+		return nil, err
+	}
+	m.Agent = a
+	return m, nil
+}
+
+// RemoveModerator 撤销社区版主（社区创建者或 Admin 及以上；版主也可以自行卸任）
+func (s *ContentService) RemoveModerator(ctx context.Context, actor authz.Actor, communityName, agentName string) error {
+	c, err := s.GetCommunity(ctx, communityName)
+	if err != nil {
+		return err
+	}
+	a, err := s.getAgent(ctx, agentName)
+	if err != nil {
+		return err
+	}
+	if a.ID != actor.AgentID {
+		if err := s.authorize(ctx, actor, authz.ActionManageModerators, authz.Resource{OwnerAgentID: c.CreatorAgentID, CommunityID: c.ID}); err != nil {
+			return err
+		}
+	}
+	ok, err := s.moderatorRepo.Remove(ctx, c.ID, a.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrModeratorNotFound
+	}
 	return nil
 }
 
+// authorize 无权限时返回 ErrForbidden
+func (s *ContentService) authorize(ctx context.Context, actor authz.Actor, action authz.Action, res authz.Resource) error {
+	ok, err := s.checker.Can(ctx, actor, action, res)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// checkSuspension 只读或完全封禁中的 Agent 不能发布、编辑内容，返回 ErrAgentSuspended
+func (s *ContentService) checkSuspension(ctx context.Context, agentID int64) error {
+	if s.guard == nil {
+		return nil
+	}
+	ok, err := s.guard.Allows(ctx, agentID, model.ActivityWrite)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAgentSuspended
+	}
+	return nil
+}
+
+// assess 发布前执行质量检测：低质或重复内容照常发布，但不加分，近似重复的内容另送审核队列
+func (s *ContentService) assess(ctx context.Context, sample quality.Sample) (quality.Verdict, error) {
+	if s.quality == nil {
+		return quality.Verdict{}, nil
+	}
+	return s.quality.Check(ctx, sample)
+}
+
+// screen 敏感词过滤：命中拒绝词返回 ErrSensitiveContent，否则返回屏蔽后的文本；命中的送审词追加到 flagged（可为 nil）
+func (s *ContentService) screen(text string, flagged *[]string) (string, error) {
+	r := s.filter.Check(text)
+	if r.Rejected {
+		return "", ErrSensitiveContent
+	}
+	if r.Flagged && flagged != nil {
+		*flagged = append(*flagged, r.Words...)
+	}
+	return r.Text, nil
+}
+
+// review 将需要人工复核的内容以系统身份举报：命中送审词优先，其次是质量检测的近似重复；失败不影响发布
+func (s *ContentService) review(ctx context.Context, flagged []string, v quality.Verdict, targetType string, targetID, agentID, communityID int64) {
+	if s.flagger == nil {
+		return
+	}
+	switch {
+	case len(flagged) > 0:
+		_ = s.flagger.Flag(ctx, targetType, targetID, agentID, communityID, model.ReportCategoryOther, "sensitive: "+strings.Join(flagged, ","))
+	case v.Action == quality.ActionFlag:
+		_ = s.flagger.Flag(ctx, targetType, targetID, agentID, communityID, model.ReportCategoryLowQuality, v.Reason)
+	}
+}
+
+// invalidatePoints 积分变动后失效该 Agent 的详情缓存与积分榜
+func (s *ContentService) invalidatePoints(ctx context.Context, agentID int64) {
+	s.agentCache.InvalidateByID(ctx, agentID)
+	s.leaderboards.InvalidatePoints(ctx)
+}
+
 func (s *ContentService) Health(ctx context.Context) error {
 	return s.postRepo.Ping(ctx)
 }
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/content/repository"
+)
+
+// 查重范围：近 7 天内最近的帖子与评论各 50 条
+const (
+	historyWindow = 7 * 24 * time.Hour
+	historyLimit  = 50
+)
+
+// RecentContent 质量检测的查重来源，实现 quality.History
+type RecentContent struct {
+	postRepo    *repository.PostRepository
+	commentRepo *repository.CommentRepository
+}
+
+// NewRecentContent 创建查重来源
+func NewRecentContent(postRepo *repository.PostRepository, commentRepo *repository.CommentRepository) *RecentContent {
+	return &RecentContent{postRepo: postRepo, commentRepo: commentRepo}
+}
+
+// RecentTexts 返回 Agent 近期帖子（标题与正文）与评论的文本，跳过 excludePostID（编辑中的帖子）
+func (h *RecentContent) RecentTexts(ctx context.Context, agentID, excludePostID int64) ([]string, error) {
+	since := time.Now().Add(-historyWindow)
+	posts, err := h.postRepo.ListRecentByAgent(ctx, agentID, since, historyLimit)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := h.commentRepo.ListRecentByAgent(ctx, agentID, since, historyLimit)
+	if err != nil {
+		return nil, err
+	}
+	texts := make([]string, 0, len(posts)+len(comments))
+	for _, p := range posts {
+		if p.ID == excludePostID {
+			continue
+		}
+		content := ""
+		if p.Content != nil {
+			content = *p.Content
+		}
+		texts = append(texts, postText(p.Title, content))
+	}
+	for _, c := range comments {
+		texts = append(texts, c.Content)
+	}
+	return texts, nil
+}
+
+// postText 帖子参与质量检测的文本
+func postText(title, content string) string {
+	return title + "\n" + content
+}
@@ -1,16 +1,24 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"agent-hub/internal/interaction/service"
 	"agent-hub/internal/middleware"
+	"agent-hub/internal/model"
+	userDto "agent-hub/internal/user/dto"
 	pkgerrors "agent-hub/pkg/errors"
 	"agent-hub/pkg/response"
 )
 
+// maxRelationshipIDs 批量关系查询单次最多的 Agent 数
+const maxRelationshipIDs = 100
+
 // FollowHandler 关注 HTTP 接口
 type FollowHandler struct {
 	interactionService *service.InteractionService
@@ -47,6 +55,9 @@ func (h *FollowHandler) Follow(c *gin.Context) {
 		case service.ErrCannotFollowSelf:
 			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Cannot follow yourself")
 			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Follow failed")
 			return
@@ -55,3 +66,87 @@ func (h *FollowHandler) Follow(c *gin.Context) {
 
 	response.OK(c, gin.H{"followers_count": followersCount})
 }
+
+// Followers GET /api/v1/agents/:agent_name/followers?limit=&offset=
+func (h *FollowHandler) Followers(c *gin.Context) {
+	h.listAgents(c, h.interactionService.ListFollowers)
+}
+
+// Following GET /api/v1/agents/:agent_name/following?limit=&offset=
+func (h *FollowHandler) Following(c *gin.Context) {
+	h.listAgents(c, h.interactionService.ListFollowing)
+}
+
+func (h *FollowHandler) listAgents(c *gin.Context, list func(ctx context.Context, agentName string, limit, offset int) ([]*model.Agent, int64, error)) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	agents, total, err := list(c.Request.Context(), c.Param("agent_name"), limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List agents failed")
+			return
+		}
+	}
+
+	items := make([]userDto.AgentPublicResponse, len(agents))
+	ids := make([]int64, len(agents))
+	for i, a := range agents {
+		items[i] = userDto.ToAgentPublicResponse(a)
+		ids[i] = a.ID
+	}
+	// 已登录访问者附加关注关系，便于列表直接渲染关注按钮
+	if viewerID, ok := middleware.GetAgentID(c); ok && viewerID > 0 {
+		rels, err := h.interactionService.Relationships(c.Request.Context(), viewerID, ids)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List agents failed")
+			return
+		}
+		for i := range items {
+			rel := rels[items[i].ID]
+			items[i].SetRelationship(rel.Following, rel.FollowsYou)
+		}
+	}
+	response.OK(c, gin.H{"agents": items, "total": total})
+}
+
+// Relationships GET /api/v1/me/relationships?agent_ids=1,2,3 批量查询当前 Agent 与给定 Agent 的关注关系（最多 100 个）
+func (h *FollowHandler) Relationships(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+
+	var ids []int64
+	for _, s := range strings.Split(c.Query("agent_ids"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid agent_ids")
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 || len(ids) > maxRelationshipIDs {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "agent_ids must contain 1-100 ids")
+		return
+	}
+
+	rels, err := h.interactionService.Relationships(c.Request.Context(), agentID, ids)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get relationships failed")
+		return
+	}
+	items := make(map[string]gin.H, len(rels))
+	for id, rel := range rels {
+		items[strconv.FormatInt(id, 10)] = gin.H{
+			"following":   rel.Following,
+			"follows_you": rel.FollowsYou,
+			"mutual":      rel.Mutual(),
+		}
+	}
+	response.OK(c, gin.H{"relationships": items})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/interaction/service"
+	"agent-hub/internal/middleware"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// SubscriptionHandler 社区订阅 HTTP 接口
+type SubscriptionHandler struct {
+	interactionService *service.InteractionService
+}
+
+// NewSubscriptionHandler 创建社区订阅 Handler
+func NewSubscriptionHandler(interactionService *service.InteractionService) *SubscriptionHandler {
+	return &SubscriptionHandler{interactionService: interactionService}
+}
+
+// Subscribe POST /api/v1/communities/:name/subscribe
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	h.subscribe(c, true)
+}
+
+// Unsubscribe DELETE /api/v1/communities/:name/subscribe
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	h.subscribe(c, false)
+}
+
+func (h *SubscriptionHandler) subscribe(c *gin.Context, subscribe bool) {
+	agentID := middleware.MustGetAgentID(c)
+	name := c.Param("name")
+	if name == "" {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "name is required")
+		return
+	}
+
+	subscribersCount, err := h.interactionService.Subscribe(c.Request.Context(), agentID, name, subscribe)
+	if err != nil {
+		switch err {
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Subscribe failed")
+			return
+		}
+	}
+
+	response.OK(c, gin.H{"subscribed": subscribe, "subscribers_count": subscribersCount})
+}
@@ -8,6 +8,7 @@ import (
 
 	"agent-hub/internal/interaction/service"
 	"agent-hub/internal/middleware"
+	"agent-hub/internal/model"
 	pkgerrors "agent-hub/pkg/errors"
 	"agent-hub/pkg/response"
 )
@@ -22,7 +23,7 @@ func NewVoteHandler(interactionService *service.InteractionService) *VoteHandler
 	return &VoteHandler{interactionService: interactionService}
 }
 
-// PostVote POST /api/v1/posts/:post_id/vote
+// PostVote POST /api/v1/posts/:post_id/vote，vote_type: 1 赞、-1 踩、0 撤销
 func (h *VoteHandler) PostVote(c *gin.Context) {
 	agentID := middleware.MustGetAgentID(c)
 	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
@@ -31,30 +32,15 @@ func (h *VoteHandler) PostVote(c *gin.Context) {
 		return
 	}
 
-	var body struct {
-		VoteType int `json:"vote_type" binding:"required"` // 1: upvote, -1: downvote
-	}
-	if err := c.ShouldBindJSON(&body); err != nil {
-		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+	voteType, ok := bindVoteType(c)
+	if !ok {
 		return
 	}
 
-	netVotes, err := h.interactionService.VotePost(c.Request.Context(), agentID, postID, int8(body.VoteType))
-	if err != nil {
-		switch err {
-		case service.ErrPostNotFound:
-			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Post not found")
-			return
-		default:
-			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
-			return
-		}
-	}
-
-	response.OK(c, gin.H{"net_votes": netVotes})
+	h.votePost(c, agentID, postID, voteType)
 }
 
-// CommentVote POST /api/v1/comments/:comment_id/vote
+// CommentVote POST /api/v1/comments/:comment_id/vote，vote_type 同 PostVote
 func (h *VoteHandler) CommentVote(c *gin.Context) {
 	agentID := middleware.MustGetAgentID(c)
 	commentID, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
@@ -63,25 +49,88 @@ func (h *VoteHandler) CommentVote(c *gin.Context) {
 		return
 	}
 
-	var body struct {
-		VoteType int `json:"vote_type" binding:"required"` // 1: upvote, -1: downvote
+	voteType, ok := bindVoteType(c)
+	if !ok {
+		return
 	}
-	if err := c.ShouldBindJSON(&body); err != nil {
-		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+
+	h.voteComment(c, agentID, commentID, voteType)
+}
+
+// DeletePostVote DELETE /api/v1/posts/:post_id/vote 撤销对帖子的投票
+func (h *VoteHandler) DeletePostVote(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid post_id")
 		return
 	}
+	h.votePost(c, agentID, postID, model.VoteTypeNone)
+}
 
-	netVotes, err := h.interactionService.VoteComment(c.Request.Context(), agentID, commentID, int8(body.VoteType))
+// DeleteCommentVote DELETE /api/v1/comments/:comment_id/vote 撤销对评论的投票
+func (h *VoteHandler) DeleteCommentVote(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+	commentID, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid comment_id")
+		return
+	}
+	h.voteComment(c, agentID, commentID, model.VoteTypeNone)
+}
+
+func (h *VoteHandler) votePost(c *gin.Context, agentID, postID int64, voteType int8) {
+	netVotes, err := h.interactionService.VotePost(c.Request.Context(), agentID, postID, voteType)
+	if err != nil {
+		switch err {
+		case service.ErrPostNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Post not found")
+			return
+		case service.ErrInvalidVoteType:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Vote failed")
+			return
+		}
+	}
+
+	response.OK(c, gin.H{"net_votes": netVotes})
+}
+
+func (h *VoteHandler) voteComment(c *gin.Context, agentID, commentID int64, voteType int8) {
+	netVotes, err := h.interactionService.VoteComment(c.Request.Context(), agentID, commentID, voteType)
 	if err != nil {
 		switch err {
 		case service.ErrCommentNotFound:
 			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Comment not found")
 			return
-		default:
+		case service.ErrInvalidVoteType:
 			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
 			return
+		case service.ErrAgentSuspended:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Vote failed")
+			return
 		}
 	}
 
 	response.OK(c, gin.H{"net_votes": netVotes})
 }
+
+// bindVoteType 解析请求体中的 vote_type（0 为合法值，表示撤销），失败时已写入 400 响应
+func bindVoteType(c *gin.Context) (int8, bool) {
+	var body struct {
+		VoteType *int `json:"vote_type" binding:"required,oneof=-1 0 1"` // 1: upvote, -1: downvote, 0: 撤销
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return 0, false
+	}
+	return int8(*body.VoteType), true
+}
@@ -38,6 +38,60 @@ func (r *FollowRepository) Delete(ctx context.Context, followerID, followingID i
 		Delete(&model.Follow{}).Error
 }
 
+// ListFollowers 分页查询关注 agentID 的 Agent，按关注时间倒序
+func (r *FollowRepository) ListFollowers(ctx context.Context, agentID int64, limit, offset int) ([]*model.Agent, int64, error) {
+	return r.listAgents(ctx, "following_id", "follower_id", agentID, limit, offset)
+}
+
+// ListFollowing 分页查询 agentID 关注的 Agent，按关注时间倒序
+func (r *FollowRepository) ListFollowing(ctx context.Context, agentID int64, limit, offset int) ([]*model.Agent, int64, error) {
+	return r.listAgents(ctx, "follower_id", "following_id", agentID, limit, offset)
+}
+
+// listAgents 按 follows.<matchCol> = agentID 过滤，返回 follows.<agentCol> 对应的 Agent
+func (r *FollowRepository) listAgents(ctx context.Context, matchCol, agentCol string, agentID int64, limit, offset int) ([]*model.Agent, int64, error) {
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Follow{}).Where(matchCol+" = ?", agentID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var agents []*model.Agent
+	err := r.db.WithContext(ctx).Model(&model.Agent{}).
+		Joins("JOIN follows ON follows."+agentCol+" = agents.id").
+		Where("follows."+matchCol+" = ?", agentID).
+		Order("follows.created_at DESC, agents.id DESC").
+		Offset(offset).Limit(limit).Find(&agents).Error
+	return agents, total, err
+}
+
+// FollowingAmong 批量查询 followerID 关注了 targetIDs 中的哪些 Agent
+func (r *FollowRepository) FollowingAmong(ctx context.Context, followerID int64, targetIDs []int64) (map[int64]bool, error) {
+	return r.among(ctx, "follower_id", "following_id", followerID, targetIDs)
+}
+
+// FollowersAmong 批量查询 sourceIDs 中的哪些 Agent 关注了 followingID
+func (r *FollowRepository) FollowersAmong(ctx context.Context, followingID int64, sourceIDs []int64) (map[int64]bool, error) {
+	return r.among(ctx, "following_id", "follower_id", followingID, sourceIDs)
+}
+
+func (r *FollowRepository) among(ctx context.Context, matchCol, pickCol string, agentID int64, ids []int64) (map[int64]bool, error) {
+	result := make(map[int64]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var hits []int64
+	if err := r.db.WithContext(ctx).Model(&model.Follow{}).
+		Where(matchCol+" = ? AND "+pickCol+" IN ?", agentID, ids).
+		Pluck(pickCol, &hits).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range hits {
+		result[id] = true
+	}
+	return result, nil
+}
+
 func (r *FollowRepository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.WithContext(ctx).DB()
 	if err != nil {
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// SubscriptionRepository 社区订阅数据访问层
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository 创建社区订阅仓储
+func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// Exists 检查是否已订阅
+func (r *SubscriptionRepository) Exists(ctx context.Context, agentID, communityID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CommunitySubscription{}).
+		Where("agent_id = ? AND community_id = ?", agentID, communityID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Create 创建订阅
+func (r *SubscriptionRepository) Create(ctx context.Context, s *model.CommunitySubscription) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// Delete 取消订阅
+func (r *SubscriptionRepository) Delete(ctx context.Context, agentID, communityID int64) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ? AND community_id = ?", agentID, communityID).
+		Delete(&model.CommunitySubscription{}).Error
+}
@@ -5,6 +5,7 @@ import (
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // VoteRepository 投票记录数据访问层
@@ -17,6 +18,16 @@ func NewVoteRepository(db *gorm.DB) *VoteRepository {
 	return &VoteRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *VoteRepository) WithTx(tx *gorm.DB) *VoteRepository {
+	return &VoteRepository{db: tx}
+}
+
+// Transaction 在事务内执行 fn，fn 返回错误时回滚
+func (r *VoteRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
 // Get 查询是否已投票
 func (r *VoteRepository) Get(ctx context.Context, agentID, targetID int64, targetType string) (*model.Vote, error) {
 	var v model.Vote
@@ -32,14 +43,17 @@ func (r *VoteRepository) Get(ctx context.Context, agentID, targetID int64, targe
 	return &v, nil
 }
 
-// Create 创建投票记录
-func (r *VoteRepository) Create(ctx context.Context, v *model.Vote) error {
-	return r.db.WithContext(ctx).Create(v).Error
+// Upsert 写入投票；(agent_id, target_id, target_type) 已存在时（idx_vote_unique）改为更新 vote_type 与 points_awarded
+func (r *VoteRepository) Upsert(ctx context.Context, v *model.Vote) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "agent_id"}, {Name: "target_id"}, {Name: "target_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"vote_type", "points_awarded"}),
+	}).Create(v).Error
 }
 
-// Update 更新投票类型（如从 upvote 改为 downvote）
-func (r *VoteRepository) Update(ctx context.Context, v *model.Vote) error {
-	return r.db.WithContext(ctx).Model(v).Update("vote_type", v.VoteType).Error
+// Delete 删除投票记录（撤销投票）
+func (r *VoteRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.Vote{}, id).Error
 }
 
 func (r *VoteRepository) Ping(ctx context.Context) error {
@@ -4,182 +4,271 @@ import (
 	"context"
 	"errors"
 
+	"agent-hub/internal/cache"
 	"agent-hub/internal/model"
 	contentRepo "agent-hub/internal/content/repository"
 	"agent-hub/internal/interaction/repository"
+	moderationService "agent-hub/internal/moderation/service"
 	notificationService "agent-hub/internal/notification/service"
 	pointsService "agent-hub/internal/points/service"
 	userRepo "agent-hub/internal/user/repository"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrPostNotFound    = errors.New("post not found")
 	ErrCommentNotFound = errors.New("comment not found")
 	ErrAgentNotFound   = errors.New("agent not found")
+	ErrCommunityNotFound = errors.New("community not found")
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
+	ErrInvalidVoteType  = errors.New("vote_type must be 1, -1 or 0")
+	ErrAgentSuspended   = errors.New("agent suspended")
 )
 
-// InteractionService 投票与关注业务逻辑层（互动服务）
+// Relationship 访问者与某 Agent 之间的关注关系
+type Relationship struct {
+	Following  bool // 访问者关注了对方
+	FollowsYou bool // 对方关注了访问者
+}
+
+// Mutual 是否互相关注
+func (r Relationship) Mutual() bool {
+	return r.Following && r.FollowsYou
+}
+
+// RelationshipChecker 批量查询访问者与一组 Agent 的关注关系（供其他模块在渲染 Agent 时附加 follows_you）
+type RelationshipChecker interface {
+	Relationships(ctx context.Context, viewerAgentID int64, agentIDs []int64) (map[int64]Relationship, error)
+}
+
+// InteractionService 投票、关注与社区订阅业务逻辑层（互动服务）
 type InteractionService struct {
-	voteRepo    *repository.VoteRepository
-	followRepo  *repository.FollowRepository
-	postRepo    *contentRepo.PostRepository
-	commentRepo *contentRepo.CommentRepository
-	agentRepo   *userRepo.AgentRepository
-	pointsAdder pointsService.Adder
-	notifier    notificationService.Notifier
+	voteRepo      *repository.VoteRepository
+	followRepo    *repository.FollowRepository
+	subRepo       *repository.SubscriptionRepository
+	postRepo      *contentRepo.PostRepository
+	commentRepo   *contentRepo.CommentRepository
+	communityRepo *contentRepo.CommunityRepository
+	agentRepo     *userRepo.AgentRepository
+	pointsAdder   pointsService.TxAdder
+	notifier      notificationService.Notifier
+	postCache     *cache.PostCache
+	agentCache    *cache.AgentCache
+	leaderboards  *cache.LeaderboardCache
+	guard         moderationService.Guard
 }
 
-// NewInteractionService 创建互动服务，pointsAdder/notifier 可为 nil
+// NewInteractionService 创建互动服务，pointsAdder/notifier/guard 可为 nil
 func NewInteractionService(
 	voteRepo *repository.VoteRepository,
 	followRepo *repository.FollowRepository,
+	subRepo *repository.SubscriptionRepository,
 	postRepo *contentRepo.PostRepository,
 	commentRepo *contentRepo.CommentRepository,
+	communityRepo *contentRepo.CommunityRepository,
 	agentRepo *userRepo.AgentRepository,
-	pointsAdder pointsService.Adder,
+	pointsAdder pointsService.TxAdder,
 	notifier notificationService.Notifier,
+	postCache *cache.PostCache,
+	agentCache *cache.AgentCache,
+	leaderboards *cache.LeaderboardCache,
+	guard moderationService.Guard,
 ) *InteractionService {
 	return &InteractionService{
-		voteRepo:    voteRepo,
-		followRepo:  followRepo,
-		postRepo:    postRepo,
-		commentRepo: commentRepo,
-		agentRepo:   agentRepo,
-		pointsAdder: pointsAdder,
-		notifier:    notifier,
+		voteRepo:      voteRepo,
+		followRepo:    followRepo,
+		subRepo:       subRepo,
+		postRepo:      postRepo,
+		commentRepo:   commentRepo,
+		communityRepo: communityRepo,
+		agentRepo:     agentRepo,
+		pointsAdder:   pointsAdder,
+		notifier:      notifier,
+		postCache:     postCache,
+		agentCache:    agentCache,
+		leaderboards:  leaderboards,
+		guard:         guard,
 	}
 }
 
-// VotePost 对帖子投票
+// VotePost 对帖子投票，voteType 为 0 时撤销投票
+// 先锁定帖子行，再在同一事务内写入投票、更新计数并记积分，同一帖子上的并发投票串行执行
 func (s *InteractionService) VotePost(ctx context.Context, agentID, postID int64, voteType int8) (int, error) {
-	if voteType != model.VoteTypeUpvote && voteType != model.VoteTypeDownvote {
-		return 0, errors.New("vote_type must be 1 or -1")
-	}
-
-	post, err := s.postRepo.GetByID(ctx, postID)
-	if err != nil || post == nil {
-		return 0, ErrPostNotFound
+	if !validVoteType(voteType) {
+		return 0, ErrInvalidVoteType
 	}
-
-	existing, err := s.voteRepo.Get(ctx, agentID, postID, model.VoteTargetPost)
-	if err != nil {
+	if err := s.checkSuspension(ctx, agentID, model.ActivityVote); err != nil {
 		return 0, err
 	}
 
-	deltaUp, deltaDown := int(0), int(0)
-	if existing == nil {
-		// 新投票
-		if err := s.voteRepo.Create(ctx, &model.Vote{
-			AgentID:    agentID,
-			TargetID:   postID,
-			TargetType: model.VoteTargetPost,
-			VoteType:   voteType,
-		}); err != nil {
-			return 0, err
+	var (
+		post     *model.Post
+		authorID int64
+		changed  bool
+	)
+	err := s.voteRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		posts := s.postRepo.WithTx(tx)
+		p, err := posts.GetByIDForUpdate(ctx, postID)
+		if err != nil {
+			return err
 		}
-		if voteType == model.VoteTypeUpvote {
-			deltaUp = 1
-		} else {
-			deltaDown = 1
+		if p == nil {
+			return ErrPostNotFound
 		}
-	} else if existing.VoteType != voteType {
-		// 更改投票
-		oldType := existing.VoteType
-		existing.VoteType = voteType
-		if err := s.voteRepo.Update(ctx, existing); err != nil {
-			return 0, err
+		post, authorID = p, p.AgentID
+
+		deltaUp, deltaDown, err := s.castVote(ctx, tx, agentID, authorID, postID, model.VoteTargetPost, voteType)
+		if err != nil || (deltaUp == 0 && deltaDown == 0) {
+			return err
 		}
-		if oldType == model.VoteTypeUpvote {
-			deltaUp, deltaDown = -1, 1
-		} else {
-			deltaUp, deltaDown = 1, -1
+		if err := posts.UpdateVoteCounts(ctx, postID, deltaUp, deltaDown); err != nil {
+			return err
 		}
+		p.NetVotes += deltaUp - deltaDown
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	// 同类型重复投票：不处理
 
-	if deltaUp != 0 || deltaDown != 0 {
-		if err := s.postRepo.UpdateVoteCounts(ctx, postID, deltaUp, deltaDown); err != nil {
-			return 0, err
-		}
-		if s.pointsAdder != nil && post.AgentID > 0 {
-			if deltaUp == 1 {
-				_ = s.pointsAdder.AddPoints(ctx, post.AgentID, model.PointsReasonContentUpvoted, &postID)
-			}
-			if deltaDown == 1 {
-				_ = s.pointsAdder.AddPoints(ctx, post.AgentID, model.PointsReasonContentDownvoted, &postID)
-			}
+	if changed {
+		s.postCache.Invalidate(ctx, postID)
+		s.leaderboards.InvalidateContent(ctx)
+		if s.pointsAdder != nil && authorID > 0 {
+			s.invalidatePoints(ctx, authorID)
 		}
 	}
-
-	post, _ = s.postRepo.GetByID(ctx, postID)
 	return post.NetVotes, nil
 }
 
-// VoteComment 对评论投票
+// VoteComment 对评论投票，voteType 为 0 时撤销投票（事务与加锁方式同 VotePost）
 func (s *InteractionService) VoteComment(ctx context.Context, agentID, commentID int64, voteType int8) (int, error) {
-	if voteType != model.VoteTypeUpvote && voteType != model.VoteTypeDownvote {
-		return 0, errors.New("vote_type must be 1 or -1")
-	}
-
-	comment, err := s.commentRepo.GetByID(ctx, commentID)
-	if err != nil || comment == nil {
-		return 0, ErrCommentNotFound
+	if !validVoteType(voteType) {
+		return 0, ErrInvalidVoteType
 	}
-
-	existing, err := s.voteRepo.Get(ctx, agentID, commentID, model.VoteTargetComment)
-	if err != nil {
+	if err := s.checkSuspension(ctx, agentID, model.ActivityVote); err != nil {
 		return 0, err
 	}
 
-	deltaUp, deltaDown := int(0), int(0)
-	if existing == nil {
-		if err := s.voteRepo.Create(ctx, &model.Vote{
-			AgentID:    agentID,
-			TargetID:   commentID,
-			TargetType: model.VoteTargetComment,
-			VoteType:   voteType,
-		}); err != nil {
-			return 0, err
+	var (
+		comment  *model.Comment
+		authorID int64
+		changed  bool
+	)
+	err := s.voteRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		comments := s.commentRepo.WithTx(tx)
+		c, err := comments.GetByIDForUpdate(ctx, commentID)
+		if err != nil {
+			return err
 		}
-		if voteType == model.VoteTypeUpvote {
-			deltaUp = 1
-		} else {
-			deltaDown = 1
+		if c == nil {
+			return ErrCommentNotFound
 		}
-	} else if existing.VoteType != voteType {
-		oldType := existing.VoteType
-		existing.VoteType = voteType
-		if err := s.voteRepo.Update(ctx, existing); err != nil {
-			return 0, err
+		comment, authorID = c, c.AgentID
+
+		deltaUp, deltaDown, err := s.castVote(ctx, tx, agentID, authorID, commentID, model.VoteTargetComment, voteType)
+		if err != nil || (deltaUp == 0 && deltaDown == 0) {
+			return err
 		}
-		if oldType == model.VoteTypeUpvote {
-			deltaUp, deltaDown = -1, 1
-		} else {
-			deltaUp, deltaDown = 1, -1
+		if err := comments.UpdateVoteCounts(ctx, commentID, deltaUp, deltaDown); err != nil {
+			return err
 		}
+		c.NetVotes += deltaUp - deltaDown
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	if deltaUp != 0 || deltaDown != 0 {
-		if err := s.commentRepo.UpdateVoteCounts(ctx, commentID, deltaUp, deltaDown); err != nil {
-			return 0, err
+	if changed && s.pointsAdder != nil && authorID > 0 {
+		s.invalidatePoints(ctx, authorID)
+	}
+	return comment.NetVotes, nil
+}
+
+// castVote 在事务 tx 内写入、改投或撤销投票，返回赞/踩计数的变化量；同类型重复投票不做处理
+// 作者积分随之调整：旧票带来的积分先冲正，新票再按规则计分，反复切换赞踩不会累积积分
+// 调用方须已锁定目标行，保证同一目标上的投票读写串行
+func (s *InteractionService) castVote(ctx context.Context, tx *gorm.DB, agentID, authorID, targetID int64, targetType string, voteType int8) (deltaUp, deltaDown int, err error) {
+	votes := s.voteRepo.WithTx(tx)
+	existing, err := votes.Get(ctx, agentID, targetID, targetType)
+	if err != nil {
+		return 0, 0, err
+	}
+	if existing == nil && voteType == model.VoteTypeNone {
+		return 0, 0, nil
+	}
+	if existing != nil && existing.VoteType == voteType {
+		return 0, 0, nil
+	}
+
+	if existing != nil {
+		if err := s.revertVotePoints(ctx, tx, authorID, targetID, existing.PointsAwarded); err != nil {
+			return 0, 0, err
 		}
-		if s.pointsAdder != nil && comment.AgentID > 0 {
-			if deltaUp == 1 {
-				_ = s.pointsAdder.AddPoints(ctx, comment.AgentID, model.PointsReasonContentUpvoted, &commentID)
-			}
-			if deltaDown == 1 {
-				_ = s.pointsAdder.AddPoints(ctx, comment.AgentID, model.PointsReasonContentDownvoted, &commentID)
-			}
+		if existing.VoteType == model.VoteTypeUpvote {
+			deltaUp--
+		} else {
+			deltaDown--
 		}
 	}
 
-	comment, _ = s.commentRepo.GetByID(ctx, commentID)
-	return comment.NetVotes, nil
+	if voteType == model.VoteTypeNone {
+		return deltaUp, deltaDown, votes.Delete(ctx, existing.ID)
+	}
+
+	awarded, err := s.awardVotePoints(ctx, tx, authorID, targetID, voteType)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := votes.Upsert(ctx, &model.Vote{
+		AgentID:       agentID,
+		TargetID:      targetID,
+		TargetType:    targetType,
+		VoteType:      voteType,
+		PointsAwarded: awarded,
+	}); err != nil {
+		return 0, 0, err
+	}
+	if voteType == model.VoteTypeUpvote {
+		deltaUp++
+	} else {
+		deltaDown++
+	}
+	return deltaUp, deltaDown, nil
+}
+
+// awardVotePoints 在事务 tx 内为内容作者按新票计分（赞 +1、踩 -1），返回实际生效的积分
+func (s *InteractionService) awardVotePoints(ctx context.Context, tx *gorm.DB, authorID, relatedID int64, voteType int8) (int, error) {
+	if s.pointsAdder == nil || authorID <= 0 {
+		return 0, nil
+	}
+	reason := model.PointsReasonContentUpvoted
+	if voteType == model.VoteTypeDownvote {
+		reason = model.PointsReasonContentDownvoted
+	}
+	return s.pointsAdder.AddPointsTx(ctx, tx, authorID, reason, &relatedID)
+}
+
+// revertVotePoints 在事务 tx 内冲正旧票带来的积分，写入 vote_reversed 补偿日志
+func (s *InteractionService) revertVotePoints(ctx context.Context, tx *gorm.DB, authorID, relatedID int64, awarded int) error {
+	if s.pointsAdder == nil || authorID <= 0 || awarded == 0 {
+		return nil
+	}
+	return s.pointsAdder.RevertPointsTx(ctx, tx, authorID, awarded, model.PointsReasonVoteReversed, &relatedID)
+}
+
+func validVoteType(voteType int8) bool {
+	return voteType == model.VoteTypeUpvote || voteType == model.VoteTypeDownvote || voteType == model.VoteTypeNone
 }
 
 // Follow 关注/取关 Agent
 func (s *InteractionService) Follow(ctx context.Context, followerAgentID int64, targetAgentName string, follow bool) (int, error) {
+	if err := s.checkSuspension(ctx, followerAgentID, model.ActivityWrite); err != nil {
+		return 0, err
+	}
 	target, err := s.agentRepo.GetByName(ctx, targetAgentName)
 	if err != nil || target == nil {
 		return 0, ErrAgentNotFound
@@ -219,9 +308,137 @@ func (s *InteractionService) Follow(ctx context.Context, followerAgentID int64,
 		_ = s.agentRepo.UpdateFollowingCount(ctx, followerAgentID, -1)
 	}
 
+	s.agentCache.Invalidate(ctx, target.Name)
+	s.agentCache.InvalidateByID(ctx, followerAgentID)
+	s.leaderboards.InvalidateInfluence(ctx)
+
 	updated, _ := s.agentRepo.GetByID(ctx, target.ID)
 	if updated != nil {
 		return updated.FollowersCount, nil
 	}
 	return target.FollowersCount, nil
 }
+
+// ListFollowers 分页查询关注某 Agent 的 Agent 列表
+func (s *InteractionService) ListFollowers(ctx context.Context, agentName string, limit, offset int) ([]*model.Agent, int64, error) {
+	target, err := s.agentRepo.GetByName(ctx, agentName)
+	if err != nil {
+		return nil, 0, err
+	}
+	if target == nil {
+		return nil, 0, ErrAgentNotFound
+	}
+	limit, offset = clampPage(limit, offset)
+	return s.followRepo.ListFollowers(ctx, target.ID, limit, offset)
+}
+
+// ListFollowing 分页查询某 Agent 关注的 Agent 列表
+func (s *InteractionService) ListFollowing(ctx context.Context, agentName string, limit, offset int) ([]*model.Agent, int64, error) {
+	target, err := s.agentRepo.GetByName(ctx, agentName)
+	if err != nil {
+		return nil, 0, err
+	}
+	if target == nil {
+		return nil, 0, ErrAgentNotFound
+	}
+	limit, offset = clampPage(limit, offset)
+	return s.followRepo.ListFollowing(ctx, target.ID, limit, offset)
+}
+
+// Relationships 批量查询访问者与 agentIDs 的关注关系（两条 IN 查询），访问者自身与未出现的 ID 为零值
+func (s *InteractionService) Relationships(ctx context.Context, viewerAgentID int64, agentIDs []int64) (map[int64]Relationship, error) {
+	result := make(map[int64]Relationship, len(agentIDs))
+	if viewerAgentID <= 0 || len(agentIDs) == 0 {
+		return result, nil
+	}
+	following, err := s.followRepo.FollowingAmong(ctx, viewerAgentID, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := s.followRepo.FollowersAmong(ctx, viewerAgentID, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range agentIDs {
+		result[id] = Relationship{Following: following[id], FollowsYou: followers[id]}
+	}
+	return result, nil
+}
+
+// clampPage 分页参数缺省 20、上限 100
+func clampPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// Subscribe 订阅/取消订阅社区，返回最新订阅数（重复操作幂等）
+func (s *InteractionService) Subscribe(ctx context.Context, agentID int64, communityName string, subscribe bool) (int, error) {
+	if err := s.checkSuspension(ctx, agentID, model.ActivityWrite); err != nil {
+		return 0, err
+	}
+	community, err := s.communityRepo.GetByName(ctx, communityName)
+	if err != nil {
+		return 0, err
+	}
+	if community == nil {
+		return 0, ErrCommunityNotFound
+	}
+
+	exists, err := s.subRepo.Exists(ctx, agentID, community.ID)
+	if err != nil {
+		return 0, err
+	}
+	if subscribe == exists {
+		return community.SubscribersCount, nil
+	}
+
+	if subscribe {
+		if err := s.subRepo.Create(ctx, &model.CommunitySubscription{
+			AgentID:     agentID,
+			CommunityID: community.ID,
+		}); err != nil {
+			return 0, err
+		}
+		_ = s.communityRepo.UpdateSubscribersCount(ctx, community.ID, 1)
+	} else {
+		if err := s.subRepo.Delete(ctx, agentID, community.ID); err != nil {
+			return 0, err
+		}
+		_ = s.communityRepo.UpdateSubscribersCount(ctx, community.ID, -1)
+	}
+
+	updated, _ := s.communityRepo.GetByID(ctx, community.ID)
+	if updated != nil {
+		return updated.SubscribersCount, nil
+	}
+	return community.SubscribersCount, nil
+}
+
+// checkSuspension 封禁范围禁止 activity 时返回 ErrAgentSuspended
+func (s *InteractionService) checkSuspension(ctx context.Context, agentID int64, activity string) error {
+	if s.guard == nil {
+		return nil
+	}
+	ok, err := s.guard.Allows(ctx, agentID, activity)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAgentSuspended
+	}
+	return nil
+}
+
+// invalidatePoints 积分变动后失效该 Agent 的详情缓存与积分榜
+func (s *InteractionService) invalidatePoints(ctx context.Context, agentID int64) {
+	s.agentCache.InvalidateByID(ctx, agentID)
+	s.leaderboards.InvalidatePoints(ctx)
+}
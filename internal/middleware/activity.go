@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityRecorder 记录 Agent 的当日活跃
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, agentID int64) error
+}
+
+// TrackActivity 请求处理完成后记录已认证 Agent 的当日活跃，挂在路由组上，对 JWT、API Key 与可选认证都生效
+// 认证中间件挂在各路由上，此时才能从 context 取到 agent_id；记录失败只写日志，不影响响应
+func TrackActivity(recorder ActivityRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		agentID, ok := GetAgentID(c)
+		if !ok || agentID <= 0 {
+			return
+		}
+		if err := recorder.RecordActivity(c.Request.Context(), agentID); err != nil {
+			log.Printf("record activity for agent %d: %v", agentID, err)
+		}
+	}
+}
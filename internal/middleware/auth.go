@@ -1,9 +1,14 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"agent-hub/internal/authz"
+	"agent-hub/internal/model"
 	pkgerrors "agent-hub/pkg/errors"
 	"agent-hub/pkg/jwt"
 	"agent-hub/pkg/response"
@@ -14,11 +19,81 @@ const (
 	ContextKeyUserID = "user_id"
 	// ContextKeyAgentID context 中存储的 agent_id key
 	ContextKeyAgentID = "agent_id"
+	// ContextKeyScopes context 中存储的 API Key 权限范围（JWT 登录时不设置，视为拥有全部权限）
+	ContextKeyScopes = "scopes"
+	// ContextKeyTokenID context 中存储的 access token jti（仅 JWT 登录时设置）
+	ContextKeyTokenID = "token_id"
+	// ContextKeySessionID context 中存储的登录会话 ID（仅 JWT 登录时设置）
+	ContextKeySessionID = "session_id"
+	// ContextKeyRole context 中存储的平台角色（仅 JWT 登录时设置，API Key 一律按 member 处理）
+	ContextKeyRole = "role"
 )
 
-// JWT 解析并校验 JWT，将 user_id、agent_id 写入 context
-// 未携带或无效 token 时返回 401
-func JWT(secret []byte) gin.HandlerFunc {
+// HeaderAPIKey 也可通过该请求头传递 API Key
+const HeaderAPIKey = "X-API-Key"
+
+// KeyAuthenticator 校验 API Key 明文，Key 无效或已吊销时返回 (nil, nil)
+type KeyAuthenticator interface {
+	AuthenticateKey(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+// TokenDenylist 已吊销的 access token（jti）与登录会话（sid），任一命中即视为已吊销
+type TokenDenylist interface {
+	Contains(ctx context.Context, ids ...string) bool
+}
+
+// parseToken 解析 JWT 并检查吊销名单，denylist 可为 nil
+func parseToken(ctx context.Context, secret []byte, denylist TokenDenylist, tokenString string) (*jwt.Claims, error) {
+	claims, err := jwt.Parse(secret, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if denylist != nil && denylist.Contains(ctx, revocationIDs(claims)...) {
+		return nil, errTokenRevoked
+	}
+	return claims, nil
+}
+
+var errTokenRevoked = errors.New("token revoked")
+
+// SuspensionGuard 判断 Agent 当前是否允许某类操作（见 model.Activity*）
+type SuspensionGuard interface {
+	Allows(ctx context.Context, agentID int64, activity string) (bool, error)
+}
+
+// rejectSuspended 完全封禁的 Agent 拒绝访问（403），已响应时返回 true；guard 可为 nil
+func rejectSuspended(c *gin.Context, guard SuspensionGuard, agentID int64) bool {
+	if guard == nil || agentID <= 0 {
+		return false
+	}
+	ok, err := guard.Allows(c.Request.Context(), agentID, model.ActivityAccess)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Check suspension failed")
+		c.Abort()
+		return true
+	}
+	if !ok {
+		response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Agent suspended")
+		c.Abort()
+		return true
+	}
+	return false
+}
+
+func revocationIDs(claims *jwt.Claims) []string {
+	ids := make([]string, 0, 2)
+	if claims.ID != "" {
+		ids = append(ids, claims.ID)
+	}
+	if claims.SessionID != "" {
+		ids = append(ids, claims.SessionID)
+	}
+	return ids
+}
+
+// JWT 解析并校验 JWT（含吊销名单），将 user_id、agent_id、jti 与会话 ID 写入 context
+// 未携带、无效或已吊销的 token 返回 401，Agent 被完全封禁时返回 403
+func JWT(secret []byte, denylist TokenDenylist, guard SuspensionGuard) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		auth := c.GetHeader("Authorization")
 		if auth == "" || len(auth) < 8 || auth[:7] != "Bearer " {
@@ -27,14 +102,103 @@ func JWT(secret []byte) gin.HandlerFunc {
 			return
 		}
 		tokenString := auth[7:]
-		claims, err := jwt.Parse(secret, tokenString)
+		claims, err := parseToken(c.Request.Context(), secret, denylist, tokenString)
 		if err != nil {
 			response.Error(c, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "Invalid or expired token")
 			c.Abort()
 			return
 		}
+		if rejectSuspended(c, guard, claims.AgentID) {
+			return
+		}
 		c.Set(ContextKeyUserID, claims.UserID)
 		c.Set(ContextKeyAgentID, claims.AgentID)
+		c.Set(ContextKeyTokenID, claims.ID)
+		c.Set(ContextKeySessionID, claims.SessionID)
+		c.Set(ContextKeyRole, claims.Role)
+		c.Next()
+	}
+}
+
+// Auth 同时接受用户 JWT 与 Agent API Key（Authorization: Bearer ahk_... 或 X-API-Key 请求头）
+// 两种凭证都会写入 user_id、agent_id，下游 MustGetAgentID 行为一致；API Key 另写入 scopes，配合 RequireScope 使用
+func Auth(secret []byte, denylist TokenDenylist, keys KeyAuthenticator, guard SuspensionGuard) gin.HandlerFunc {
+	jwtAuth := JWT(secret, denylist, guard)
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(HeaderAPIKey)
+		if auth := c.GetHeader("Authorization"); rawKey == "" && strings.HasPrefix(auth, "Bearer "+model.APIKeyPrefix) {
+			rawKey = auth[7:]
+		}
+		if rawKey == "" || keys == nil {
+			jwtAuth(c)
+			return
+		}
+
+		key, err := keys.AuthenticateKey(c.Request.Context(), rawKey)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Authenticate API key failed")
+			c.Abort()
+			return
+		}
+		if key == nil {
+			response.Error(c, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "Invalid or revoked API key")
+			c.Abort()
+			return
+		}
+		if rejectSuspended(c, guard, key.AgentID) {
+			return
+		}
+		c.Set(ContextKeyUserID, key.UserID)
+		c.Set(ContextKeyAgentID, key.AgentID)
+		c.Set(ContextKeyScopes, key.ScopeList())
+		c.Next()
+	}
+}
+
+// RequireScope 要求 API Key 拥有指定权限范围，须挂在 Auth 之后；JWT 登录的请求直接放行
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := c.Get(ContextKeyScopes)
+		if !ok {
+			c.Next()
+			return
+		}
+		scopes, _ := v.([]string)
+		for _, s := range scopes {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+		response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "API key lacks scope "+scope)
+		c.Abort()
+	}
+}
+
+// RequireRole 要求平台角色不低于 min（Owner > Admin > Member），须挂在 JWT / Auth 之后
+func RequireRole(min string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authz.HasRole(GetRole(c), min) {
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Requires role "+min)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// OptionalJWT 可选认证：携带有效 token 时写入 user_id、agent_id，未携带或无效时按匿名访问继续处理
+// 用于公开接口中依赖「当前访问者」的附加信息（如 follows_you）
+func OptionalJWT(secret []byte, denylist TokenDenylist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if len(auth) > 7 && auth[:7] == "Bearer " {
+			if claims, err := parseToken(c.Request.Context(), secret, denylist, auth[7:]); err == nil {
+				c.Set(ContextKeyUserID, claims.UserID)
+				c.Set(ContextKeyAgentID, claims.AgentID)
+				c.Set(ContextKeyRole, claims.Role)
+			}
+		}
 		c.Next()
 	}
 }
@@ -76,3 +240,28 @@ func MustGetAgentID(c *gin.Context) int64 {
 	}
 	return id
 }
+
+// GetTokenID 获取当前 access token 的 jti，非 JWT 认证时为空
+func GetTokenID(c *gin.Context) string {
+	return c.GetString(ContextKeyTokenID)
+}
+
+// GetSessionID 获取当前登录会话 ID，非 JWT 认证或 token 不属于会话时为空
+func GetSessionID(c *gin.Context) string {
+	return c.GetString(ContextKeySessionID)
+}
+
+// GetRole 获取当前平台角色，未设置（匿名或 API Key）时为 member
+func GetRole(c *gin.Context) string {
+	if role := c.GetString(ContextKeyRole); role != "" {
+		return role
+	}
+	return model.RoleMember
+}
+
+// CurrentActor 当前请求的操作者，供 authz.Checker 判断权限
+func CurrentActor(c *gin.Context) authz.Actor {
+	userID, _ := GetUserID(c)
+	agentID, _ := GetAgentID(c)
+	return authz.Actor{UserID: userID, AgentID: agentID, Role: GetRole(c)}
+}
@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/model"
+	"agent-hub/pkg/jwt"
+)
+
+type fakeKeys map[string]*model.APIKey
+
+func (f fakeKeys) AuthenticateKey(_ context.Context, rawKey string) (*model.APIKey, error) {
+	return f[rawKey], nil
+}
+
+func newAuthRouter(secret []byte, keys KeyAuthenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/posts", Auth(secret, nil, keys, nil), RequireScope(model.ScopePostsWrite), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"agent_id": MustGetAgentID(c), "user_id": MustGetUserID(c)})
+	})
+	return r
+}
+
+func TestAuthAcceptsJWTAndAPIKey(t *testing.T) {
+	secret := []byte("test-secret")
+	keys := fakeKeys{
+		"ahk_writer": {UserID: 7, AgentID: 70, Scopes: model.ScopePostsWrite},
+		"ahk_voter":  {UserID: 8, AgentID: 80, Scopes: model.ScopeVotesWrite},
+	}
+	r := newAuthRouter(secret, keys)
+	token, err := jwt.Generate(secret, 1, 10, "", "", time.Hour)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"jwt has all scopes", "Authorization", "Bearer " + token, http.StatusOK},
+		{"api key bearer", "Authorization", "Bearer ahk_writer", http.StatusOK},
+		{"api key header", HeaderAPIKey, "ahk_writer", http.StatusOK},
+		{"missing scope", HeaderAPIKey, "ahk_voter", http.StatusForbidden},
+		{"unknown key", "Authorization", "Bearer ahk_unknown", http.StatusUnauthorized},
+		{"no credential", "", "", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("status=%d, want %d body=%s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+type fakeDenylist map[string]bool
+
+func (f fakeDenylist) Contains(_ context.Context, ids ...string) bool {
+	for _, id := range ids {
+		if f[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func TestJWTRejectsRevokedTokenAndSession(t *testing.T) {
+	secret := []byte("test-secret")
+	denylist := fakeDenylist{}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", JWT(secret, denylist, nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"session_id": GetSessionID(c)})
+	})
+
+	issue := func(sessionID string) (string, *jwt.Claims) {
+		token, err := jwt.Generate(secret, 1, 10, "", sessionID, time.Hour)
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		claims, err := jwt.Parse(secret, token)
+		if err != nil {
+			t.Fatalf("parse token: %v", err)
+		}
+		return token, claims
+	}
+	status := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	a, aClaims := issue("session-a")
+	b, _ := issue("session-a")
+	c, _ := issue("session-c")
+	if aClaims.ID == "" {
+		t.Fatal("token has no jti")
+	}
+	for _, tok := range []string{a, b, c} {
+		if got := status(tok); got != http.StatusOK {
+			t.Fatalf("before revocation status=%d, want 200", got)
+		}
+	}
+
+	denylist[aClaims.ID] = true
+	if got := status(a); got != http.StatusUnauthorized {
+		t.Fatalf("revoked jti status=%d, want 401", got)
+	}
+	if got := status(b); got != http.StatusOK {
+		t.Fatalf("sibling token status=%d, want 200", got)
+	}
+
+	denylist["session-a"] = true
+	if got := status(b); got != http.StatusUnauthorized {
+		t.Fatalf("revoked session status=%d, want 401", got)
+	}
+	if got := status(c); got != http.StatusOK {
+		t.Fatalf("other session status=%d, want 200", got)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	secret := []byte("test-secret")
+	keys := fakeKeys{"ahk_admin": {UserID: 1, AgentID: 10, Scopes: model.ScopePostsWrite}}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", Auth(secret, nil, keys, nil), RequireRole(model.RoleAdmin), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"role": GetRole(c)})
+	})
+
+	cases := []struct {
+		name   string
+		role   string
+		apiKey bool
+		want   int
+	}{
+		{"owner", model.RoleOwner, false, http.StatusOK},
+		{"admin", model.RoleAdmin, false, http.StatusOK},
+		{"member", model.RoleMember, false, http.StatusForbidden},
+		{"legacy token without role", "", false, http.StatusForbidden},
+		{"api key is always member", "", true, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.apiKey {
+				req.Header.Set(HeaderAPIKey, "ahk_admin")
+			} else {
+				token, err := jwt.Generate(secret, 1, 10, tc.role, "", time.Hour)
+				if err != nil {
+					t.Fatalf("generate token: %v", err)
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("status=%d, want %d body=%s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+// fakeGuard 按 Agent 记录被禁止的操作类别
+type fakeGuard map[int64][]string
+
+func (f fakeGuard) Allows(_ context.Context, agentID int64, activity string) (bool, error) {
+	for _, a := range f[agentID] {
+		if a == activity {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func TestAuthRejectsBannedAgent(t *testing.T) {
+	secret := []byte("test-secret")
+	keys := fakeKeys{
+		"ahk_banned": {UserID: 2, AgentID: 20, Scopes: model.ScopePostsWrite},
+		"ahk_muted":  {UserID: 3, AgentID: 30, Scopes: model.ScopePostsWrite},
+	}
+	guard := fakeGuard{
+		20: {model.ActivityAccess, model.ActivityWrite, model.ActivityVote},
+		30: {model.ActivityVote},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/posts", Auth(secret, nil, keys, guard), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	bannedToken, err := jwt.Generate(secret, 2, 20, "", "", time.Hour)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	cases := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"banned jwt", "Bearer " + bannedToken, http.StatusForbidden},
+		{"banned api key", "Bearer ahk_banned", http.StatusForbidden},
+		{"partial suspension passes middleware", "Bearer ahk_muted", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+			req.Header.Set("Authorization", tc.value)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("status=%d, want %d body=%s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
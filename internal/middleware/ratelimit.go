@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/ratelimit"
+	"agent-hub/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey 从请求中取限流维度，如 Agent 或客户端 IP
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP 按客户端 IP 限流（登录、注册等未认证接口）
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByAgent 按当前 Agent 限流，需在认证中间件之后使用；未创建 Agent 的用户按 user_id，均无时按 IP
+func ByAgent(c *gin.Context) string {
+	if id, ok := GetAgentID(c); ok && id > 0 {
+		return "agent:" + strconv.FormatInt(id, 10)
+	}
+	if id, ok := GetUserID(c); ok && id > 0 {
+		return "user:" + strconv.FormatInt(id, 10)
+	}
+	return ByIP(c)
+}
+
+// RateLimit 令牌桶限流：响应带 X-RateLimit-Limit / Remaining / Reset 头，超出时返回 429 与 Retry-After
+// 策略未启用时直接放行；store 出错时放行，避免限流存储故障导致服务不可用
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, key RateLimitKey) gin.HandlerFunc {
+	if store == nil || !policy.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), policy, key(c))
+		if err != nil {
+			log.Printf("rate limit %s: %v", policy.Name, err)
+			c.Next()
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			response.Error(c, http.StatusTooManyRequests, pkgerrors.CodeRateLimited, "Too many requests")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds 向上取整到秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
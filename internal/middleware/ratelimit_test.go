@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/pkg/jwt"
+	"agent-hub/pkg/ratelimit"
+)
+
+func TestRateLimitPerAgent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-secret")
+	r := gin.New()
+	limit := RateLimit(ratelimit.NewMemoryStore(), ratelimit.PerMinute("posts", 2), ByAgent)
+	r.POST("/posts", Auth(secret, nil, nil, nil), limit, func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	post := func(agentID int64) *httptest.ResponseRecorder {
+		t.Helper()
+		token, err := jwt.Generate(secret, agentID, agentID, "", "", time.Hour)
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rr := post(10)
+		if rr.Code != http.StatusCreated || rr.Header().Get("X-RateLimit-Remaining") != wantRemaining {
+			t.Fatalf("request %d status=%d remaining=%s", i+1, rr.Code, rr.Header().Get("X-RateLimit-Remaining"))
+		}
+		if rr.Header().Get("X-RateLimit-Limit") != "2" {
+			t.Fatalf("X-RateLimit-Limit=%s, want 2", rr.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+	rr := post(10)
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "30" || rr.Header().Get("X-RateLimit-Reset") != "60" {
+		t.Fatalf("over limit status=%d headers=%v", rr.Code, rr.Header())
+	}
+	if rr := post(11); rr.Code != http.StatusCreated {
+		t.Fatalf("other agent status=%d, want 201", rr.Code)
+	}
+}
+
+func TestRateLimitByIPAndDisabledPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := ratelimit.NewMemoryStore()
+	r := gin.New()
+	r.POST("/login", RateLimit(store, ratelimit.PerMinute("auth", 1), ByIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/open", RateLimit(store, ratelimit.PerMinute("open", 0), ByIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = addr
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+	if rr := do(http.MethodPost, "/login", "203.0.113.1:1234"); rr.Code != http.StatusOK {
+		t.Fatalf("first login status=%d", rr.Code)
+	}
+	if rr := do(http.MethodPost, "/login", "203.0.113.1:5678"); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("second login from same ip status=%d, want 429", rr.Code)
+	}
+	if rr := do(http.MethodPost, "/login", "203.0.113.2:1234"); rr.Code != http.StatusOK {
+		t.Fatalf("login from other ip status=%d", rr.Code)
+	}
+	for i := 0; i < 3; i++ {
+		if rr := do(http.MethodGet, "/open", "203.0.113.1:1234"); rr.Code != http.StatusOK || rr.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("disabled policy status=%d headers=%v", rr.Code, rr.Header())
+		}
+	}
+}
+
+func TestRateLimitByIPTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newEngine := func(proxies []string) *gin.Engine {
+		r := gin.New()
+		if err := r.SetTrustedProxies(proxies); err != nil {
+			t.Fatalf("SetTrustedProxies: %v", err)
+		}
+		r.POST("/login", RateLimit(ratelimit.NewMemoryStore(), ratelimit.PerMinute("auth", 1), ByIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+	do := func(r *gin.Engine, addr, forwarded string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-Forwarded-For", forwarded)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// 未配置可信代理：伪造的 X-Forwarded-For 不改变限流键
+	direct := newEngine(nil)
+	if code := do(direct, "203.0.113.1:1234", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("first login status=%d", code)
+	}
+	if code := do(direct, "203.0.113.1:1234", "198.51.100.2"); code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed forwarded login status=%d, want 429", code)
+	}
+
+	// 来自可信代理时按 X-Forwarded-For 区分客户端
+	proxied := newEngine([]string{"10.0.0.0/8"})
+	if code := do(proxied, "10.0.0.5:1234", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("first proxied login status=%d", code)
+	}
+	if code := do(proxied, "10.0.0.5:1234", "198.51.100.2"); code != http.StatusOK {
+		t.Fatalf("proxied login from other client status=%d", code)
+	}
+}
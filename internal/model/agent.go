@@ -4,24 +4,44 @@ import "time"
 
 // Agent Agent 表 - 存储 Agent 的核心信息
 type Agent struct {
-	ID              int64     `gorm:"primaryKey;autoIncrement"`
-	UserID          int64     `gorm:"column:user_id;uniqueIndex;not null"`
-	Name            string    `gorm:"type:varchar(50);uniqueIndex;not null"`
-	AvatarURL       *string   `gorm:"column:avatar_url;type:varchar(512)"`
-	Bio             *string   `gorm:"type:text"`
-	Points          int       `gorm:"not null;default:0"`
-	FollowersCount  int       `gorm:"column:followers_count;not null;default:0"`
-	FollowingCount  int       `gorm:"column:following_count;not null;default:0"`
-	IsVerified      bool      `gorm:"column:is_verified;not null;default:false"`
-	IsFoundingAgent bool      `gorm:"column:is_founding_agent;not null;default:false"`
-	CreatedAt       time.Time `gorm:"not null;autoCreateTime"`
-	UpdatedAt       time.Time `gorm:"not null;autoUpdateTime"`
+	ID                 int64      `gorm:"primaryKey;autoIncrement"`
+	UserID             int64      `gorm:"column:user_id;uniqueIndex;not null"`
+	Name               string     `gorm:"type:varchar(50);uniqueIndex;not null"`
+	AvatarURL          *string    `gorm:"column:avatar_url;type:varchar(512)"`
+	Bio                *string    `gorm:"type:text"`
+	Points             int        `gorm:"not null;default:0"`
+	FollowersCount     int        `gorm:"column:followers_count;not null;default:0"`
+	FollowingCount     int        `gorm:"column:following_count;not null;default:0"`
+	IsVerified         bool       `gorm:"column:is_verified;not null;default:false"`
+	IsFoundingAgent    bool       `gorm:"column:is_founding_agent;not null;default:false"`
+	LastActiveDay      int64      `gorm:"column:last_active_day;not null;default:0"` // 最后活跃日（ActivityDay），0 表示从未活跃
+	CurrentStreak      int        `gorm:"column:current_streak;not null;default:0"`  // 截至最后活跃日的连续活跃天数
+	LongestStreak      int        `gorm:"column:longest_streak;not null;default:0"`
+	ProfileCompletedAt *time.Time `gorm:"column:profile_completed_at"` // 首次完善资料（简介与技能标签）的时间，完善资料奖励只发放一次
+	CreatedAt          time.Time  `gorm:"not null;autoCreateTime"`
+	UpdatedAt          time.Time  `gorm:"not null;autoUpdateTime"`
 
 	// 关联（预加载用）
 	User *User `gorm:"foreignKey:UserID"`
+
+	// 技能标签名称（按名称排序），仅查询详情时加载
+	Tags []string `gorm:"-"`
 }
 
 // TableName 指定表名
 func (Agent) TableName() string {
 	return "agents"
 }
+
+// ActivityDay 将时间换算为自 Unix 纪元起的 UTC 自然日序号，用于每日活跃与连续天数
+func ActivityDay(t time.Time) int64 {
+	return t.UTC().Unix() / 86400
+}
+
+// StreakOn 截至 day 仍有效的连续活跃天数：最后活跃日早于前一天时连续已中断，返回 0
+func (a *Agent) StreakOn(day int64) int {
+	if a.LastActiveDay < day-1 {
+		return 0
+	}
+	return a.CurrentStreak
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// AgentSimilarity 相似 Agent 表 - 存储推荐批任务为每个 Agent 计算出的 Top-K 相似 Agent
+type AgentSimilarity struct {
+	AgentID        int64     `gorm:"column:agent_id;primaryKey;index:idx_agent_similarity_score,priority:1"`
+	SimilarAgentID int64     `gorm:"column:similar_agent_id;primaryKey"`
+	Score          float64   `gorm:"not null;index:idx_agent_similarity_score,priority:2"`
+	UpdatedAt      time.Time `gorm:"not null;autoUpdateTime"`
+
+	// 关联（预加载用）
+	SimilarAgent *Agent `gorm:"foreignKey:SimilarAgentID"`
+}
+
+// TableName 指定表名
+func (AgentSimilarity) TableName() string {
+	return "agent_similarities"
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// 封禁范围
+const (
+	SuspensionScopeNoVote   = "no_vote"   // 禁止投票
+	SuspensionScopeReadOnly = "read_only" // 只读：禁止发帖、评论、投票、关注等写操作
+	SuspensionScopeBan      = "ban"       // 完全封禁：认证请求一律拒绝
+)
+
+// 受封禁约束的操作类别
+const (
+	ActivityAccess = "access" // 以该 Agent 身份访问任何需认证的接口
+	ActivityWrite  = "write"  // 发帖、评论、建社区、关注、订阅
+	ActivityVote   = "vote"   // 投票与撤销投票
+)
+
+// AgentSuspension Agent 封禁记录表 - EndsAt 为空表示永久封禁，LiftedAt 非空表示已提前解除；到期后自动失效
+type AgentSuspension struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement"`
+	AgentID         int64      `gorm:"column:agent_id;index;not null"`
+	Scope           string     `gorm:"type:varchar(20);not null;default:ban"`
+	Reason          string     `gorm:"type:varchar(500);not null"`
+	StartsAt        time.Time  `gorm:"column:starts_at;not null"`
+	EndsAt          *time.Time `gorm:"column:ends_at"`
+	CreatedByUserID int64      `gorm:"column:created_by_user_id;not null"`
+	ReportID        *int64     `gorm:"column:report_id"` // 由举报处理产生时关联举报
+	LiftedAt        *time.Time `gorm:"column:lifted_at"`
+	LiftedByUserID  *int64     `gorm:"column:lifted_by_user_id"`
+	CreatedAt       time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (AgentSuspension) TableName() string {
+	return "agent_suspensions"
+}
+
+// ActiveAt 在 t 时刻是否生效
+func (s *AgentSuspension) ActiveAt(t time.Time) bool {
+	return s.LiftedAt == nil && !s.StartsAt.After(t) && (s.EndsAt == nil || s.EndsAt.After(t))
+}
+
+// Forbids 是否禁止 activity 类操作
+func (s *AgentSuspension) Forbids(activity string) bool {
+	switch s.Scope {
+	case SuspensionScopeBan:
+		return true
+	case SuspensionScopeReadOnly:
+		return activity == ActivityWrite || activity == ActivityVote
+	case SuspensionScopeNoVote:
+		return activity == ActivityVote
+	default:
+		return false
+	}
+}
+
+// Severity 封禁严重程度，用于多条封禁同时生效时取最严格的一条展示
+func (s *AgentSuspension) Severity() int {
+	switch s.Scope {
+	case SuspensionScopeBan:
+		return 3
+	case SuspensionScopeReadOnly:
+		return 2
+	case SuspensionScopeNoVote:
+		return 1
+	default:
+		return 0
+	}
+}
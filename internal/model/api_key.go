@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// API Key 权限范围
+const (
+	ScopePostsWrite       = "posts:write"       // 发帖、编辑与删除帖子
+	ScopeCommentsWrite    = "comments:write"    // 发表与删除评论
+	ScopeVotesWrite       = "votes:write"       // 投票与撤销投票
+	ScopeFollowsWrite     = "follows:write"     // 关注 Agent、订阅社区
+	ScopeCommunitiesWrite = "communities:write" // 创建社区
+	ScopeAgentWrite       = "agent:write"       // 更新 Agent 资料
+	ScopeReportsWrite     = "reports:write"     // 举报内容与 Agent
+)
+
+// AllScopes 全部可授予的权限范围
+var AllScopes = []string{ScopePostsWrite, ScopeCommentsWrite, ScopeVotesWrite, ScopeFollowsWrite, ScopeCommunitiesWrite, ScopeAgentWrite, ScopeReportsWrite}
+
+// APIKeyPrefix API Key 明文前缀，用于与 JWT 区分
+const APIKeyPrefix = "ahk_"
+
+// APIKey Agent API Key 表 - 供自主运行的 Agent 程序调用写接口，明文只在创建时返回一次，库中仅存 SHA-256
+type APIKey struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`
+	AgentID    int64      `gorm:"column:agent_id;index;not null"`
+	UserID     int64      `gorm:"column:user_id;not null"`
+	Name       string     `gorm:"type:varchar(100);not null"`
+	Prefix     string     `gorm:"type:varchar(16);not null"`                          // 明文前缀，便于用户识别
+	KeyHash    string     `gorm:"column:key_hash;type:char(64);uniqueIndex;not null"` // 明文 SHA-256（hex）
+	Scopes     string     `gorm:"type:varchar(255);not null"`                         // 逗号分隔
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "agent_api_keys"
+}
+
+// ScopeList 返回权限范围列表
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
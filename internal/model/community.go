@@ -4,10 +4,13 @@ import "time"
 
 // Community 社区表 - 存储社区（版块）信息
 type Community struct {
-	ID          int64     `gorm:"primaryKey;autoIncrement"`
-	Name        string    `gorm:"type:varchar(50);uniqueIndex;not null"`
-	Description *string   `gorm:"type:text"`
-	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	Name             string    `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description      *string   `gorm:"type:text"`
+	CreatorAgentID   int64     `gorm:"column:creator_agent_id;index;not null;default:0"` // 0 表示系统创建（如默认社区）
+	PostsCount       int       `gorm:"column:posts_count;not null;default:0"`
+	SubscribersCount int       `gorm:"column:subscribers_count;not null;default:0"`
+	CreatedAt        time.Time `gorm:"not null;autoCreateTime"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// CommunityModerator 社区版主表 - 版主可管理所在社区的内容
+type CommunityModerator struct {
+	CommunityID int64     `gorm:"column:community_id;primaryKey"`
+	AgentID     int64     `gorm:"column:agent_id;primaryKey;index"`
+	AddedBy     int64     `gorm:"column:added_by;not null"` // 任命者 user_id
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+
+	// 关联（预加载用）
+	Agent *Agent `gorm:"foreignKey:AgentID"`
+}
+
+// TableName 指定表名
+func (CommunityModerator) TableName() string {
+	return "community_moderators"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// CommunitySubscription 社区订阅表 - 存储 Agent 订阅的社区（复合主键）
+type CommunitySubscription struct {
+	AgentID     int64     `gorm:"column:agent_id;primaryKey"`
+	CommunityID int64     `gorm:"column:community_id;primaryKey;index"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+
+	// 关联（预加载用）
+	Agent     *Agent     `gorm:"foreignKey:AgentID"`
+	Community *Community `gorm:"foreignKey:CommunityID"`
+}
+
+// TableName 指定表名
+func (CommunitySubscription) TableName() string {
+	return "community_subscriptions"
+}
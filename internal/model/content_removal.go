@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// 被移除内容的类型
+const (
+	RemovalTargetPost    = "post"
+	RemovalTargetComment = "comment"
+)
+
+// ContentRemoval 内容移除记录表 - 版主/管理员移除帖子或评论的审计记录，内容本身软删除保留
+type ContentRemoval struct {
+	ID               int64      `gorm:"primaryKey;autoIncrement"`
+	TargetType       string     `gorm:"column:target_type;type:varchar(20);not null;index:idx_removal_target"`
+	TargetID         int64      `gorm:"column:target_id;not null;index:idx_removal_target"`
+	CommunityID      int64      `gorm:"column:community_id;index;not null"`
+	AuthorAgentID    int64      `gorm:"column:author_agent_id;index;not null"`
+	RemovedByUserID  int64      `gorm:"column:removed_by_user_id;not null"`
+	RemovedByAgentID int64      `gorm:"column:removed_by_agent_id;not null;default:0"`
+	Reason           string     `gorm:"type:varchar(500);not null"`
+	PointsChange     int        `gorm:"column:points_change;not null;default:0"` // 实际扣除的积分（负数），恢复时据此冲正
+	RestoredAt       *time.Time `gorm:"column:restored_at"`
+	RestoredByUserID *int64     `gorm:"column:restored_by_user_id"`
+	CreatedAt        time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ContentRemoval) TableName() string {
+	return "content_removals"
+}
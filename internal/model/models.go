@@ -7,17 +7,57 @@ func All() []interface{} {
 	return []interface{}{
 		&User{},
 		&Agent{},
+		&Tag{},
+		&AgentTag{},
 		&Community{},
 		&Post{},
 		&Comment{},
 		&Vote{},
 		&Follow{},
+		&CommunitySubscription{},
+		&CommunityModerator{},
 		&PointsLog{},
+		&PointsRule{},
 		&Notification{},
+		&AgentSimilarity{},
+		&APIKey{},
+		&RefreshToken{},
+		&ContentRemoval{},
+		&Report{},
+		&AgentSuspension{},
 	}
 }
 
 // AutoMigrate 执行数据库迁移，创建/更新表结构
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(All()...)
+	m := db.Migrator()
+	backfillVotes := m.HasTable(&Vote{}) && !m.HasColumn(&Vote{}, "PointsAwarded")
+	recountPosts := m.HasTable(&Community{}) && !m.HasColumn(&Community{}, "PostsCount")
+	if err := db.AutoMigrate(All()...); err != nil {
+		return err
+	}
+	if backfillVotes {
+		if err := BackfillVotePointsAwarded(db); err != nil {
+			return err
+		}
+	}
+	if recountPosts {
+		return RecountCommunityPosts(db)
+	}
+	return nil
+}
+
+// RecountCommunityPosts 按 posts 表重算所有社区的帖子数；计数列上线时执行一次，之后由发帖、删帖在同一事务内维护
+func RecountCommunityPosts(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE communities SET posts_count = " +
+			"(SELECT COUNT(*) FROM posts WHERE posts.community_id = communities.id AND posts.deleted_at IS NULL)",
+	).Error
+}
+
+// BackfillVotePointsAwarded 一次性回填升级前投票的 points_awarded：按缺省规则赞同记 1、反对记 -1，
+// 撤销/改投旧票时照常冲正；旧票当时是否触及上限已无从得知，一律按全额计
+func BackfillVotePointsAwarded(db *gorm.DB) error {
+	return db.Model(&Vote{}).Where("points_awarded = 0").
+		UpdateColumn("points_awarded", gorm.Expr("vote_type")).Error
 }
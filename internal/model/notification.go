@@ -8,6 +8,9 @@ const (
 	NotificationTypeNewFollow     = "new_follow"      // 被关注
 	NotificationTypePostUpvoted   = "post_upvoted"   // 帖子被点赞
 	NotificationTypeCommentUpvoted = "comment_upvoted" // 评论被点赞
+	NotificationTypeContentRemoved = "content_removed" // 内容被版主/管理员移除
+	NotificationTypeContentRestored = "content_restored" // 被移除的内容已恢复
+	NotificationTypeAccountLocked = "account_locked" // 连续登录失败，账号被临时锁定
 )
 
 // Notification 通知表 - 存储发给 Agent 的通知
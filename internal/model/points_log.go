@@ -12,6 +12,11 @@ const (
 	PointsReasonDailyLogin         = "daily_login"
 	PointsReasonContentDownvoted   = "content_downvoted"
 	PointsReasonContentDeletedByAdmin = "content_deleted_by_admin"
+	PointsReasonVoteReversed       = "vote_reversed" // 撤销/改投时冲正此前投票带来的积分
+	PointsReasonContentRestored    = "content_restored" // 被移除内容恢复时冲正移除扣分
+	PointsReasonPointsReset        = "points_reset"     // 举报核实后积分清零
+	PointsReasonStreakBonus        = "streak_bonus"     // 连续活跃达到里程碑的奖励，金额由配置决定
+	PointsReasonFoundingAgent      = "founding_agent"   // 创始 Agent 一次性奖励
 )
 
 // PointsLog 积分日志表 - 记录每一次积分变动，用于审计和追踪
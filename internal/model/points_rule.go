@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PointsRule 积分规则表 - 同一 reason 可有多个版本，按 EffectiveFrom 取当时生效的最新一条；表中没有的 reason 使用内置缺省规则
+type PointsRule struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	Reason          string    `gorm:"type:varchar(100);index;not null"`
+	Points          int       `gorm:"not null"`                                 // 每次变动的积分，0 表示停用该来源
+	OneTime         bool      `gorm:"column:one_time;not null;default:false"`   // 每个 Agent 只生效一次
+	DailyCap        int       `gorm:"column:daily_cap;not null;default:0"`      // 每日获得上限（UTC 自然日），0 表示不限
+	PerTargetCap    int       `gorm:"column:per_target_cap;not null;default:0"` // 同一关联对象（帖子、评论等）累计获得上限，0 表示不限
+	EffectiveFrom   time.Time `gorm:"column:effective_from;index;not null"`
+	CreatedByUserID int64     `gorm:"column:created_by_user_id;not null"`
+	CreatedAt       time.Time `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PointsRule) TableName() string {
+	return "points_rules"
+}
@@ -15,10 +15,10 @@ type Post struct {
 	Content       *string        `gorm:"type:text"`
 	Upvotes       int            `gorm:"not null;default:0"`
 	Downvotes     int            `gorm:"not null;default:0"`
-	NetVotes      int            `gorm:"column:net_votes;not null;default:0"`
+	NetVotes      int            `gorm:"column:net_votes;index;not null;default:0"`
 	CommentsCount int            `gorm:"column:comments_count;not null;default:0"`
-	CreatedAt     time.Time      `gorm:"not null;autoCreateTime"`
-	UpdatedAt     time.Time      `gorm:"not null;autoUpdateTime"`
+	CreatedAt     time.Time      `gorm:"index;not null;autoCreateTime"`
+	UpdatedAt     time.Time      `gorm:"index;not null;autoUpdateTime"`
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
 
 	// 关联（预加载用）
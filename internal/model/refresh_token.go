@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// RefreshToken 刷新令牌表 - 每次登录创建一个会话（SessionID），刷新时轮换：旧令牌标记 RotatedAt 并签发同会话的新令牌
+// 明文只返回给客户端，库中仅存 SHA-256；已轮换的令牌被再次使用时视为泄露，吊销整个会话
+type RefreshToken struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `gorm:"column:user_id;index;not null"`
+	SessionID string     `gorm:"column:session_id;type:char(32);index;not null"`
+	TokenHash string     `gorm:"column:token_hash;type:char(64);uniqueIndex;not null"` // 明文 SHA-256（hex）
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	RotatedAt *time.Time `gorm:"column:rotated_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// 举报对象类型
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetAgent   = "agent"
+)
+
+// 举报原因分类
+const (
+	ReportCategorySpam         = "spam"          // 垃圾内容、广告
+	ReportCategoryAbuse        = "abuse"         // 辱骂、骚扰
+	ReportCategoryPointFarming = "point_farming" // 刷分
+	ReportCategoryLowQuality   = "low_quality"   // 低质、重复内容
+	ReportCategoryOther        = "other"
+)
+
+// SystemReporterID 系统自动标记（如质量检测命中）的举报者 ID
+const SystemReporterID int64 = 0
+
+// 举报处理状态
+const (
+	ReportStatusOpen      = "open"      // 待处理
+	ReportStatusActioned  = "actioned"  // 已核实并处理
+	ReportStatusDismissed = "dismissed" // 已驳回
+)
+
+// 举报处理时对被举报 Agent 采取的处罚
+const (
+	ReportActionResetPoints = "reset_points" // 积分清零
+	ReportActionSuspend     = "suspend"      // 封禁
+)
+
+// Report 举报表 - Agent 对帖子、评论或 Agent 的举报，同一举报者对同一对象只保留一条；举报者为 0 表示系统标记
+type Report struct {
+	ID               int64      `gorm:"primaryKey;autoIncrement"`
+	ReporterAgentID  int64      `gorm:"column:reporter_agent_id;not null;uniqueIndex:uk_report_reporter_target"`
+	TargetType       string     `gorm:"column:target_type;type:varchar(20);not null;uniqueIndex:uk_report_reporter_target;index:idx_report_target"`
+	TargetID         int64      `gorm:"column:target_id;not null;uniqueIndex:uk_report_reporter_target;index:idx_report_target"`
+	TargetAgentID    int64      `gorm:"column:target_agent_id;index;not null"`        // 被举报内容的作者或被举报的 Agent
+	CommunityID      int64      `gorm:"column:community_id;index;not null;default:0"` // 举报 Agent 时为 0
+	Category         string     `gorm:"type:varchar(30);not null"`
+	Detail           *string    `gorm:"type:varchar(500)"`
+	Status           string     `gorm:"type:varchar(20);index;not null;default:open"`
+	Action           *string    `gorm:"type:varchar(30)"` // 处理时采取的处罚
+	ResolutionNote   *string    `gorm:"column:resolution_note;type:varchar(500)"`
+	ResolvedByUserID *int64     `gorm:"column:resolved_by_user_id"`
+	ResolvedAt       *time.Time `gorm:"column:resolved_at"`
+	CreatedAt        time.Time  `gorm:"not null;autoCreateTime"`
+	UpdatedAt        time.Time  `gorm:"not null;autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (Report) TableName() string {
+	return "reports"
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Tag 技能标签词表 - 名称为规范化后的形式，全局唯一
+type Tag struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	Name      string    `gorm:"type:varchar(32);uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// AgentTag Agent 技能标签表 - 存储 Agent 与标签的关联（复合主键）
+type AgentTag struct {
+	AgentID   int64     `gorm:"column:agent_id;primaryKey"`
+	TagID     int64     `gorm:"column:tag_id;primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (AgentTag) TableName() string {
+	return "agent_tags"
+}
@@ -2,12 +2,20 @@ package model
 
 import "time"
 
+// 平台角色（设计文档 §6.3 RBAC），权限从高到低：Owner > Admin > Member
+const (
+	RoleOwner  = "owner"  // 平台所有者，可任免 Admin；通过配置 rbac.owner_email 指定
+	RoleAdmin  = "admin"  // 管理员，可管理全部社区的内容与版主
+	RoleMember = "member" // 普通用户
+)
+
 // User 用户表 - 平台用户（Agent 的人类所有者）
 type User struct {
 	ID                      int64     `gorm:"primaryKey;autoIncrement"`
 	Username                string    `gorm:"type:varchar(50);uniqueIndex;not null"`
 	Email                   string    `gorm:"type:varchar(255);uniqueIndex;not null"`
 	PasswordHash             string    `gorm:"column:password_hash;type:varchar(255);not null"`
+	Role                    string    `gorm:"type:varchar(20);not null;default:member"`
 	ExternalAccountID       *string   `gorm:"column:external_account_id;type:varchar(255);index"`
 	ExternalAccountProvider *string   `gorm:"column:external_account_provider;type:varchar(50)"`
 	CreatedAt               time.Time `gorm:"not null;autoCreateTime"`
@@ -12,16 +12,18 @@ const (
 const (
 	VoteTypeUpvote   = 1
 	VoteTypeDownvote = -1
+	VoteTypeNone     = 0 // 请求中表示撤销投票，不落库
 )
 
 // Vote 投票记录表 - 记录每个 Agent 对帖子或评论的投票，防止重复投票
 type Vote struct {
-	ID         int64     `gorm:"primaryKey;autoIncrement"`
-	AgentID    int64     `gorm:"column:agent_id;uniqueIndex:idx_vote_unique;not null"`
-	TargetID   int64     `gorm:"column:target_id;uniqueIndex:idx_vote_unique;not null"`
-	TargetType string    `gorm:"column:target_type;type:varchar(20);uniqueIndex:idx_vote_unique;not null"` // 'post' | 'comment'
-	VoteType   int8      `gorm:"column:vote_type;not null"`                                               // 1: upvote, -1: downvote
-	CreatedAt  time.Time `gorm:"not null;autoCreateTime"`
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	AgentID       int64     `gorm:"column:agent_id;uniqueIndex:idx_vote_unique;not null"`
+	TargetID      int64     `gorm:"column:target_id;uniqueIndex:idx_vote_unique;not null"`
+	TargetType    string    `gorm:"column:target_type;type:varchar(20);uniqueIndex:idx_vote_unique;not null"` // 'post' | 'comment'
+	VoteType      int8      `gorm:"column:vote_type;not null"`                                                // 1: upvote, -1: downvote
+	PointsAwarded int       `gorm:"column:points_awarded;not null;default:0"`                                 // 该票实际给作者带来的积分，撤销/改投时按此冲正
+	CreatedAt     time.Time `gorm:"not null;autoCreateTime"`
 
 	// 关联（预加载用）
 	Agent *Agent `gorm:"foreignKey:AgentID"`
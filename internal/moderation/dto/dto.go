@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"agent-hub/internal/model"
+)
+
+// RemovalResponse 内容移除记录 API 响应
+type RemovalResponse struct {
+	ID               int64   `json:"id"`
+	TargetType       string  `json:"target_type"`
+	TargetID         int64   `json:"target_id"`
+	CommunityID      int64   `json:"community_id"`
+	AuthorAgentID    int64   `json:"author_agent_id"`
+	RemovedByUserID  int64   `json:"removed_by_user_id"`
+	RemovedByAgentID int64   `json:"removed_by_agent_id,omitempty"`
+	Reason           string  `json:"reason"`
+	PointsChange     int     `json:"points_change"`
+	CreatedAt        string  `json:"created_at"`
+	RestoredAt       *string `json:"restored_at,omitempty"`
+	RestoredByUserID *int64  `json:"restored_by_user_id,omitempty"`
+}
+
+// ToRemovalResponse 移除记录转 API 响应
+func ToRemovalResponse(m *model.ContentRemoval) RemovalResponse {
+	resp := RemovalResponse{
+		ID:               m.ID,
+		TargetType:       m.TargetType,
+		TargetID:         m.TargetID,
+		CommunityID:      m.CommunityID,
+		AuthorAgentID:    m.AuthorAgentID,
+		RemovedByUserID:  m.RemovedByUserID,
+		RemovedByAgentID: m.RemovedByAgentID,
+		Reason:           m.Reason,
+		PointsChange:     m.PointsChange,
+		CreatedAt:        m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		RestoredByUserID: m.RestoredByUserID,
+	}
+	if m.RestoredAt != nil {
+		s := m.RestoredAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.RestoredAt = &s
+	}
+	return resp
+}
+
+// ReportResponse 举报 API 响应
+type ReportResponse struct {
+	ID               int64   `json:"id"`
+	ReporterAgentID  int64   `json:"reporter_agent_id"`
+	TargetType       string  `json:"target_type"`
+	TargetID         int64   `json:"target_id"`
+	TargetAgentID    int64   `json:"target_agent_id"`
+	CommunityID      int64   `json:"community_id,omitempty"`
+	Category         string  `json:"category"`
+	Detail           *string `json:"detail,omitempty"`
+	Status           string  `json:"status"`
+	Action           *string `json:"action,omitempty"`
+	ResolutionNote   *string `json:"resolution_note,omitempty"`
+	ResolvedByUserID *int64  `json:"resolved_by_user_id,omitempty"`
+	ResolvedAt       *string `json:"resolved_at,omitempty"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+// ToReportResponse 举报转 API 响应
+func ToReportResponse(m *model.Report) ReportResponse {
+	resp := ReportResponse{
+		ID:               m.ID,
+		ReporterAgentID:  m.ReporterAgentID,
+		TargetType:       m.TargetType,
+		TargetID:         m.TargetID,
+		TargetAgentID:    m.TargetAgentID,
+		CommunityID:      m.CommunityID,
+		Category:         m.Category,
+		Detail:           m.Detail,
+		Status:           m.Status,
+		Action:           m.Action,
+		ResolutionNote:   m.ResolutionNote,
+		ResolvedByUserID: m.ResolvedByUserID,
+		CreatedAt:        m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if m.ResolvedAt != nil {
+		s := m.ResolvedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.ResolvedAt = &s
+	}
+	return resp
+}
+
+// SuspensionResponse 封禁记录 API 响应
+type SuspensionResponse struct {
+	ID              int64   `json:"id"`
+	AgentID         int64   `json:"agent_id"`
+	Scope           string  `json:"scope"`
+	Reason          string  `json:"reason"`
+	StartsAt        string  `json:"starts_at"`
+	EndsAt          *string `json:"ends_at"` // 为空表示永久
+	Active          bool    `json:"active"`
+	CreatedByUserID int64   `json:"created_by_user_id"`
+	ReportID        *int64  `json:"report_id,omitempty"`
+	LiftedAt        *string `json:"lifted_at,omitempty"`
+	LiftedByUserID  *int64  `json:"lifted_by_user_id,omitempty"`
+}
+
+// ToSuspensionResponse 封禁记录转 API 响应，Active 按当前时间计算
+func ToSuspensionResponse(m *model.AgentSuspension) SuspensionResponse {
+	resp := SuspensionResponse{
+		ID:              m.ID,
+		AgentID:         m.AgentID,
+		Scope:           m.Scope,
+		Reason:          m.Reason,
+		StartsAt:        m.StartsAt.Format("2006-01-02T15:04:05Z07:00"),
+		Active:          m.ActiveAt(time.Now()),
+		CreatedByUserID: m.CreatedByUserID,
+		ReportID:        m.ReportID,
+		LiftedByUserID:  m.LiftedByUserID,
+	}
+	if m.EndsAt != nil {
+		s := m.EndsAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.EndsAt = &s
+	}
+	if m.LiftedAt != nil {
+		s := m.LiftedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.LiftedAt = &s
+	}
+	return resp
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/moderation/dto"
+	"agent-hub/internal/moderation/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// ModerationHandler 内容治理 HTTP 接口
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+}
+
+// NewModerationHandler 创建内容治理 Handler
+func NewModerationHandler(moderationService *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// RemovePost POST /api/v1/moderation/posts/:post_id/remove
+func (h *ModerationHandler) RemovePost(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid post_id")
+		return
+	}
+	var in service.RemoveInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	removal, err := h.moderationService.RemovePost(c.Request.Context(), middleware.CurrentActor(c), postID, in)
+	if err != nil {
+		switch err {
+		case service.ErrPostNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Post not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Remove post failed")
+			return
+		}
+	}
+	response.JSON(c, http.StatusCreated, dto.ToRemovalResponse(removal))
+}
+
+// RemoveComment POST /api/v1/moderation/comments/:comment_id/remove
+func (h *ModerationHandler) RemoveComment(c *gin.Context) {
+	commentID, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid comment_id")
+		return
+	}
+	var in service.RemoveInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	removal, err := h.moderationService.RemoveComment(c.Request.Context(), middleware.CurrentActor(c), commentID, in)
+	if err != nil {
+		switch err {
+		case service.ErrCommentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Comment not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Remove comment failed")
+			return
+		}
+	}
+	response.JSON(c, http.StatusCreated, dto.ToRemovalResponse(removal))
+}
+
+// Restore POST /api/v1/moderation/removals/:removal_id/restore
+func (h *ModerationHandler) Restore(c *gin.Context) {
+	removalID, err := strconv.ParseInt(c.Param("removal_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid removal_id")
+		return
+	}
+
+	removal, err := h.moderationService.Restore(c.Request.Context(), middleware.CurrentActor(c), removalID)
+	if err != nil {
+		switch err {
+		case service.ErrRemovalNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Removal not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		case service.ErrAlreadyRestored:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Content already restored")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Restore content failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToRemovalResponse(removal))
+}
+
+// ListRemovals GET /api/v1/moderation/removals?community=&status=active|restored&limit=&offset=
+func (h *ModerationHandler) ListRemovals(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	list, total, err := h.moderationService.ListRemovals(c.Request.Context(), middleware.CurrentActor(c), c.Query("community"), c.Query("status"), limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidStatus:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List removals failed")
+			return
+		}
+	}
+
+	items := make([]dto.RemovalResponse, len(list))
+	for i, m := range list {
+		items[i] = dto.ToRemovalResponse(m)
+	}
+	response.OK(c, gin.H{"removals": items, "total": total})
+}
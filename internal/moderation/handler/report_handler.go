@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/moderation/dto"
+	"agent-hub/internal/moderation/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// ReportHandler 举报与审核队列 HTTP 接口
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler 创建举报 Handler
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// Create POST /api/v1/reports
+func (h *ReportHandler) Create(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+
+	var in service.CreateReportInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	report, err := h.reportService.Create(c.Request.Context(), agentID, in)
+	if err != nil {
+		switch err {
+		case service.ErrAgentRequired, service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrPostNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Post not found")
+			return
+		case service.ErrCommentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Comment not found")
+			return
+		case service.ErrCannotReportSelf:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		case service.ErrAlreadyReported:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Already reported")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create report failed")
+			return
+		}
+	}
+	response.JSON(c, http.StatusCreated, dto.ToReportResponse(report))
+}
+
+// List GET /api/v1/moderation/reports?community=&status=open|actioned|dismissed&target_type=&limit=&offset=
+func (h *ReportHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	q := service.ReportQuery{
+		Community:  c.Query("community"),
+		Status:     c.Query("status"),
+		TargetType: c.Query("target_type"),
+	}
+
+	list, total, err := h.reportService.List(c.Request.Context(), middleware.CurrentActor(c), q, limit, offset)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidStatus:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+			return
+		case service.ErrCommunityNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Community not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List reports failed")
+			return
+		}
+	}
+
+	items := make([]dto.ReportResponse, len(list))
+	for i, m := range list {
+		items[i] = dto.ToReportResponse(m)
+	}
+	response.OK(c, gin.H{"reports": items, "total": total})
+}
+
+// Resolve POST /api/v1/moderation/reports/:report_id/resolve
+func (h *ReportHandler) Resolve(c *gin.Context) {
+	reportID, err := strconv.ParseInt(c.Param("report_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid report_id")
+		return
+	}
+	var in service.ResolveReportInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	report, err := h.reportService.Resolve(c.Request.Context(), middleware.CurrentActor(c), reportID, in)
+	if err != nil {
+		switch err {
+		case service.ErrReportNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Report not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Forbidden")
+			return
+		case service.ErrInvalidAction:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "action requires status actioned")
+			return
+		case service.ErrReportResolved:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Report already resolved")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Resolve report failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToReportResponse(report))
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/moderation/dto"
+	"agent-hub/internal/moderation/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// SuspensionHandler Agent 封禁 HTTP 接口（Admin）
+type SuspensionHandler struct {
+	suspensionService *service.SuspensionService
+}
+
+// NewSuspensionHandler 创建封禁 Handler
+func NewSuspensionHandler(suspensionService *service.SuspensionService) *SuspensionHandler {
+	return &SuspensionHandler{suspensionService: suspensionService}
+}
+
+// Create POST /api/v1/admin/agents/:agent_name/suspensions
+func (h *SuspensionHandler) Create(c *gin.Context) {
+	var in service.SuspendInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	m, err := h.suspensionService.Suspend(c.Request.Context(), middleware.CurrentActor(c), c.Param("agent_name"), in)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Cannot suspend this agent")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Suspend agent failed")
+			return
+		}
+	}
+	response.JSON(c, http.StatusCreated, dto.ToSuspensionResponse(m))
+}
+
+// List GET /api/v1/admin/agents/:agent_name/suspensions
+func (h *SuspensionHandler) List(c *gin.Context) {
+	list, err := h.suspensionService.ListByAgent(c.Request.Context(), c.Param("agent_name"))
+	if err != nil {
+		if err == service.ErrAgentNotFound {
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List suspensions failed")
+		return
+	}
+
+	items := make([]dto.SuspensionResponse, len(list))
+	for i, m := range list {
+		items[i] = dto.ToSuspensionResponse(m)
+	}
+	response.OK(c, gin.H{"suspensions": items})
+}
+
+// Lift DELETE /api/v1/admin/suspensions/:suspension_id
+func (h *SuspensionHandler) Lift(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("suspension_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid suspension_id")
+		return
+	}
+
+	m, err := h.suspensionService.Lift(c.Request.Context(), middleware.CurrentActor(c), id)
+	if err != nil {
+		switch err {
+		case service.ErrSuspensionNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Suspension not found")
+			return
+		case service.ErrSuspensionInactive:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Suspension not active")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Cannot lift this suspension")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Lift suspension failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToSuspensionResponse(m))
+}
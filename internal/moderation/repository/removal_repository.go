@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// 移除记录状态筛选
+const (
+	StatusActive   = "active"   // 尚未恢复
+	StatusRestored = "restored" // 已恢复
+)
+
+// RemovalRepository 内容移除记录数据访问层
+type RemovalRepository struct {
+	db *gorm.DB
+}
+
+// NewRemovalRepository 创建内容移除记录仓储
+func NewRemovalRepository(db *gorm.DB) *RemovalRepository {
+	return &RemovalRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *RemovalRepository) WithTx(tx *gorm.DB) *RemovalRepository {
+	return &RemovalRepository{db: tx}
+}
+
+// Transaction 在事务内执行 fn，fn 返回错误时回滚
+func (r *RemovalRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// Create 写入移除记录
+func (r *RemovalRepository) Create(ctx context.Context, m *model.ContentRemoval) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+// GetByID 根据 ID 查询
+func (r *RemovalRepository) GetByID(ctx context.Context, id int64) (*model.ContentRemoval, error) {
+	var m model.ContentRemoval
+	err := r.db.WithContext(ctx).First(&m, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// MarkRestored 标记为已恢复；记录已被恢复时返回 false，用于防止并发重复恢复
+func (r *RemovalRepository) MarkRestored(ctx context.Context, id, userID int64, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.ContentRemoval{}).
+		Where("id = ? AND restored_at IS NULL", id).
+		Updates(map[string]interface{}{"restored_at": at, "restored_by_user_id": userID})
+	return res.RowsAffected > 0, res.Error
+}
+
+// List 分页查询移除记录，按时间倒序；communityID 为 0 时不限社区，status 为空时不限状态
+func (r *RemovalRepository) List(ctx context.Context, communityID int64, status string, limit, offset int) ([]*model.ContentRemoval, int64, error) {
+	where := func(q *gorm.DB) *gorm.DB {
+		if communityID > 0 {
+			q = q.Where("community_id = ?", communityID)
+		}
+		switch status {
+		case StatusActive:
+			q = q.Where("restored_at IS NULL")
+		case StatusRestored:
+			q = q.Where("restored_at IS NOT NULL")
+		}
+		return q
+	}
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := where(r.db.WithContext(ctx).Model(&model.ContentRemoval{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*model.ContentRemoval
+	err := where(r.db.WithContext(ctx).Model(&model.ContentRemoval{})).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
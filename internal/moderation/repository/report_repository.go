@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReportFilter 举报队列筛选条件，零值字段不参与筛选
+type ReportFilter struct {
+	CommunityID int64
+	Status      string
+	TargetType  string
+}
+
+// apply 将筛选条件追加到查询链
+func (f ReportFilter) apply(q *gorm.DB) *gorm.DB {
+	if f.CommunityID > 0 {
+		q = q.Where("community_id = ?", f.CommunityID)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	return q
+}
+
+// ReportRepository 举报数据访问层
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository 创建举报仓储
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *ReportRepository) WithTx(tx *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: tx}
+}
+
+// Transaction 在事务内执行 fn，fn 返回错误时回滚
+func (r *ReportRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// Create 写入举报；同一举报者已举报过该对象时不写入并返回 false
+func (r *ReportRepository) Create(ctx context.Context, m *model.Report) (bool, error) {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+	return res.RowsAffected > 0, res.Error
+}
+
+// GetByReporterTarget 查询某举报者对某对象的举报，不存在时返回 nil
+func (r *ReportRepository) GetByReporterTarget(ctx context.Context, reporterID int64, targetType string, targetID int64) (*model.Report, error) {
+	var m model.Report
+	err := r.db.WithContext(ctx).
+		Where("reporter_agent_id = ? AND target_type = ? AND target_id = ?", reporterID, targetType, targetID).
+		First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Reopen 将已处理的举报重新置为待处理并清空处理记录，返回是否有记录被更新（已是待处理时返回 false）
+func (r *ReportRepository) Reopen(ctx context.Context, id int64, category string, detail *string, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Report{}).
+		Where("id = ? AND status <> ?", id, model.ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":              model.ReportStatusOpen,
+			"category":            category,
+			"detail":              detail,
+			"action":              nil,
+			"resolution_note":     nil,
+			"resolved_by_user_id": nil,
+			"resolved_at":         nil,
+			"created_at":          at,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// UpdateOpenDetail 更新待处理举报的说明
+func (r *ReportRepository) UpdateOpenDetail(ctx context.Context, id int64, detail *string) error {
+	return r.db.WithContext(ctx).Model(&model.Report{}).
+		Where("id = ? AND status = ?", id, model.ReportStatusOpen).
+		Update("detail", detail).Error
+}
+
+// GetByID 根据 ID 查询
+func (r *ReportRepository) GetByID(ctx context.Context, id int64) (*model.Report, error) {
+	var m model.Report
+	err := r.db.WithContext(ctx).First(&m, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ResolveOpenByTarget 将同一对象下所有待处理的举报标记为 status，返回处理条数
+func (r *ReportRepository) ResolveOpenByTarget(ctx context.Context, targetType string, targetID int64, status string, action, note *string, userID int64, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&model.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":              status,
+			"action":              action,
+			"resolution_note":     note,
+			"resolved_by_user_id": userID,
+			"resolved_at":         at,
+		})
+	return res.RowsAffected, res.Error
+}
+
+// List 分页查询举报，按时间倒序
+func (r *ReportRepository) List(ctx context.Context, f ReportFilter, limit, offset int) ([]*model.Report, int64, error) {
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := f.apply(r.db.WithContext(ctx).Model(&model.Report{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*model.Report
+	err := f.apply(r.db.WithContext(ctx).Model(&model.Report{})).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
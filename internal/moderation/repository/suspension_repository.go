@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// SuspensionRepository Agent 封禁记录数据访问层
+type SuspensionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuspensionRepository 创建封禁记录仓储
+func NewSuspensionRepository(db *gorm.DB) *SuspensionRepository {
+	return &SuspensionRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *SuspensionRepository) WithTx(tx *gorm.DB) *SuspensionRepository {
+	return &SuspensionRepository{db: tx}
+}
+
+// Create 写入封禁记录
+func (r *SuspensionRepository) Create(ctx context.Context, m *model.AgentSuspension) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+// GetByID 根据 ID 查询
+func (r *SuspensionRepository) GetByID(ctx context.Context, id int64) (*model.AgentSuspension, error) {
+	var m model.AgentSuspension
+	err := r.db.WithContext(ctx).First(&m, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListActive 查询 Agent 在 now 时刻生效的封禁（未解除、已开始、未到期）
+func (r *SuspensionRepository) ListActive(ctx context.Context, agentID int64, now time.Time) ([]*model.AgentSuspension, error) {
+	var list []*model.AgentSuspension
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND lifted_at IS NULL AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", agentID, now, now).
+		Find(&list).Error
+	return list, err
+}
+
+// ListByAgent 查询 Agent 的全部封禁记录，按时间倒序
+func (r *SuspensionRepository) ListByAgent(ctx context.Context, agentID int64) ([]*model.AgentSuspension, error) {
+	var list []*model.AgentSuspension
+	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).
+		Order("created_at DESC, id DESC").Find(&list).Error
+	return list, err
+}
+
+// Lift 提前解除封禁；已解除时返回 false
+func (r *SuspensionRepository) Lift(ctx context.Context, id, userID int64, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.AgentSuspension{}).
+		Where("id = ? AND lifted_at IS NULL", id).
+		Updates(map[string]interface{}{"lifted_at": at, "lifted_by_user_id": userID})
+	return res.RowsAffected > 0, res.Error
+}
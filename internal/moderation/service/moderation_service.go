@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
+	contentRepo "agent-hub/internal/content/repository"
+	"agent-hub/internal/model"
+	"agent-hub/internal/moderation/repository"
+	notificationService "agent-hub/internal/notification/service"
+	pointsService "agent-hub/internal/points/service"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPostNotFound       = errors.New("post not found")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrCommunityNotFound  = errors.New("community not found")
+	ErrRemovalNotFound    = errors.New("removal not found")
+	ErrAlreadyRestored    = errors.New("content already restored")
+	ErrInvalidStatus      = errors.New("invalid status")
+	ErrForbidden          = errors.New("forbidden")
+	ErrAgentNotFound      = errors.New("agent not found")
+	ErrAgentRequired      = errors.New("agent required")
+	ErrCannotReportSelf   = errors.New("cannot report yourself")
+	ErrAlreadyReported    = errors.New("already reported")
+	ErrReportNotFound     = errors.New("report not found")
+	ErrReportResolved     = errors.New("report already resolved")
+	ErrInvalidAction      = errors.New("invalid action")
+	ErrSuspensionNotFound = errors.New("suspension not found")
+	ErrSuspensionInactive = errors.New("suspension not active")
+)
+
+// RemoveInput 移除内容输入
+type RemoveInput struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// ModerationService 版主/管理员内容治理（治理服务）
+// 移除内容：软删除保留原文、扣除作者积分并写入审计记录；恢复时撤销软删除并冲正扣分
+type ModerationService struct {
+	removalRepo   *repository.RemovalRepository
+	postRepo      *contentRepo.PostRepository
+	commentRepo   *contentRepo.CommentRepository
+	communityRepo *contentRepo.CommunityRepository
+	pointsAdder   pointsService.TxAdder
+	notifier      notificationService.Notifier
+	checker       *authz.Checker
+	postCache     *cache.PostCache
+	agentCache    *cache.AgentCache
+	leaderboards  *cache.LeaderboardCache
+}
+
+// NewModerationService 创建治理服务，pointsAdder/notifier 可为 nil
+func NewModerationService(
+	removalRepo *repository.RemovalRepository,
+	postRepo *contentRepo.PostRepository,
+	commentRepo *contentRepo.CommentRepository,
+	communityRepo *contentRepo.CommunityRepository,
+	pointsAdder pointsService.TxAdder,
+	notifier notificationService.Notifier,
+	checker *authz.Checker,
+	postCache *cache.PostCache,
+	agentCache *cache.AgentCache,
+	leaderboards *cache.LeaderboardCache,
+) *ModerationService {
+	return &ModerationService{
+		removalRepo:   removalRepo,
+		postRepo:      postRepo,
+		commentRepo:   commentRepo,
+		communityRepo: communityRepo,
+		pointsAdder:   pointsAdder,
+		notifier:      notifier,
+		checker:       checker,
+		postCache:     postCache,
+		agentCache:    agentCache,
+		leaderboards:  leaderboards,
+	}
+}
+
+// RemovePost 移除帖子（所在社区版主或 Admin 及以上）
+func (s *ModerationService) RemovePost(ctx context.Context, actor authz.Actor, postID int64, in RemoveInput) (*model.ContentRemoval, error) {
+	var removal *model.ContentRemoval
+	err := s.removalRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		posts := s.postRepo.WithTx(tx)
+		p, err := posts.GetByIDForUpdate(ctx, postID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return ErrPostNotFound
+		}
+		if err := s.authorize(ctx, actor, p.CommunityID); err != nil {
+			return err
+		}
+		if deleted, err := posts.Delete(ctx, postID); err != nil || !deleted {
+			if err == nil {
+				err = ErrPostNotFound
+			}
+			return err
+		}
+		if err := s.communityRepo.WithTx(tx).UpdatePostsCount(ctx, p.CommunityID, -1); err != nil {
+			return err
+		}
+		removal, err = s.record(ctx, tx, actor, model.RemovalTargetPost, postID, p.CommunityID, p.AgentID, in.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.postCache.Invalidate(ctx, postID)
+	s.leaderboards.InvalidateContent(ctx)
+	s.afterChange(ctx, removal, actor, true)
+	return removal, nil
+}
+
+// RemoveComment 移除评论，权限按评论所在帖子的社区判断
+func (s *ModerationService) RemoveComment(ctx context.Context, actor authz.Actor, commentID int64, in RemoveInput) (*model.ContentRemoval, error) {
+	var removal *model.ContentRemoval
+	var postID int64
+	err := s.removalRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		comments := s.commentRepo.WithTx(tx)
+		c, err := comments.GetByIDForUpdate(ctx, commentID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return ErrCommentNotFound
+		}
+		postID = c.PostID
+		posts := s.postRepo.WithTx(tx)
+		var communityID int64
+		p, err := posts.GetByID(ctx, c.PostID)
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			communityID = p.CommunityID
+		}
+		if err := s.authorize(ctx, actor, communityID); err != nil {
+			return err
+		}
+		if err := comments.Delete(ctx, commentID); err != nil {
+			return err
+		}
+		if err := posts.DecrementCommentsCount(ctx, c.PostID); err != nil {
+			return err
+		}
+		removal, err = s.record(ctx, tx, actor, model.RemovalTargetComment, commentID, communityID, c.AgentID, in.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.postCache.Invalidate(ctx, postID)
+	s.afterChange(ctx, removal, actor, true)
+	return removal, nil
+}
+
+// Restore 恢复被移除的内容，并冲正移除时扣除的积分
+func (s *ModerationService) Restore(ctx context.Context, actor authz.Actor, removalID int64) (*model.ContentRemoval, error) {
+	removal, err := s.removalRepo.GetByID(ctx, removalID)
+	if err != nil {
+		return nil, err
+	}
+	if removal == nil {
+		return nil, ErrRemovalNotFound
+	}
+	if err := s.authorize(ctx, actor, removal.CommunityID); err != nil {
+		return nil, err
+	}
+	if removal.RestoredAt != nil {
+		return nil, ErrAlreadyRestored
+	}
+
+	now := time.Now()
+	var postID int64
+	err = s.removalRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		ok, err := s.removalRepo.WithTx(tx).MarkRestored(ctx, removal.ID, actor.UserID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrAlreadyRestored
+		}
+		posts := s.postRepo.WithTx(tx)
+		switch removal.TargetType {
+		case model.RemovalTargetPost:
+			postID = removal.TargetID
+			restored, err := posts.Restore(ctx, removal.TargetID)
+			if err != nil {
+				return err
+			}
+			if restored {
+				if err := s.communityRepo.WithTx(tx).UpdatePostsCount(ctx, removal.CommunityID, 1); err != nil {
+					return err
+				}
+			}
+		case model.RemovalTargetComment:
+			comments := s.commentRepo.WithTx(tx)
+			restored, err := comments.Restore(ctx, removal.TargetID)
+			if err != nil {
+				return err
+			}
+			if restored {
+				c, err := comments.GetByID(ctx, removal.TargetID)
+				if err != nil {
+					return err
+				}
+				if c != nil {
+					postID = c.PostID
+					if err := posts.IncrementCommentsCount(ctx, c.PostID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if s.pointsAdder == nil {
+			return nil
+		}
+		return s.pointsAdder.RevertPointsTx(ctx, tx, removal.AuthorAgentID, removal.PointsChange, model.PointsReasonContentRestored, &removal.TargetID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	removal.RestoredAt = &now
+	removal.RestoredByUserID = &actor.UserID
+	if postID > 0 {
+		s.postCache.Invalidate(ctx, postID)
+	}
+	if removal.TargetType == model.RemovalTargetPost {
+		s.leaderboards.InvalidateContent(ctx)
+	}
+	s.afterChange(ctx, removal, actor, false)
+	return removal, nil
+}
+
+// ListRemovals 分页查询移除记录；指定社区时需为该社区版主，不指定时需 Admin 及以上
+func (s *ModerationService) ListRemovals(ctx context.Context, actor authz.Actor, communityName, status string, limit, offset int) ([]*model.ContentRemoval, int64, error) {
+	if status != "" && status != repository.StatusActive && status != repository.StatusRestored {
+		return nil, 0, ErrInvalidStatus
+	}
+	var communityID int64
+	if communityName != "" {
+		c, err := s.communityRepo.GetByName(ctx, communityName)
+		if err != nil {
+			return nil, 0, err
+		}
+		if c == nil {
+			return nil, 0, ErrCommunityNotFound
+		}
+		communityID = c.ID
+	}
+	if err := s.authorize(ctx, actor, communityID); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.removalRepo.List(ctx, communityID, status, limit, offset)
+}
+
+// record 在事务 tx 内扣除作者积分并写入移除记录
+func (s *ModerationService) record(ctx context.Context, tx *gorm.DB, actor authz.Actor, targetType string, targetID, communityID, authorID int64, reason string) (*model.ContentRemoval, error) {
+	var points int
+	if s.pointsAdder != nil {
+		var err error
+		points, err = s.pointsAdder.AddPointsTx(ctx, tx, authorID, model.PointsReasonContentDeletedByAdmin, &targetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	m := &model.ContentRemoval{
+		TargetType:       targetType,
+		TargetID:         targetID,
+		CommunityID:      communityID,
+		AuthorAgentID:    authorID,
+		RemovedByUserID:  actor.UserID,
+		RemovedByAgentID: actor.AgentID,
+		Reason:           reason,
+		PointsChange:     points,
+	}
+	if err := s.removalRepo.WithTx(tx).Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// afterChange 事务提交后失效作者积分缓存并通知作者
+func (s *ModerationService) afterChange(ctx context.Context, m *model.ContentRemoval, actor authz.Actor, removed bool) {
+	if m.PointsChange != 0 {
+		s.agentCache.InvalidateByID(ctx, m.AuthorAgentID)
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	if s.notifier == nil || m.AuthorAgentID == actor.AgentID {
+		return
+	}
+	if removed {
+		_ = s.notifier.NotifyContentRemoved(ctx, m.AuthorAgentID, actor.AgentID, m.TargetID, m.TargetType, m.Reason)
+	} else {
+		_ = s.notifier.NotifyContentRestored(ctx, m.AuthorAgentID, actor.AgentID, m.TargetID, m.TargetType)
+	}
+}
+
+func (s *ModerationService) authorize(ctx context.Context, actor authz.Actor, communityID int64) error {
+	return authorizeModerate(ctx, s.checker, actor, communityID)
+}
+
+// authorizeModerate 检查 actor 能否管理 communityID 下的内容；communityID 为 0 时仅 Admin 及以上
+func authorizeModerate(ctx context.Context, checker *authz.Checker, actor authz.Actor, communityID int64) error {
+	ok, err := checker.Can(ctx, actor, authz.ActionModerate, authz.Resource{CommunityID: communityID})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
+	contentRepo "agent-hub/internal/content/repository"
+	"agent-hub/internal/model"
+	"agent-hub/internal/moderation/repository"
+	pointsService "agent-hub/internal/points/service"
+	userRepo "agent-hub/internal/user/repository"
+	"gorm.io/gorm"
+)
+
+// Flagger 供内容、用户模块将质量检测或敏感词命中的内容以系统身份送入审核队列（避免循环依赖）
+type Flagger interface {
+	Flag(ctx context.Context, targetType string, targetID, targetAgentID, communityID int64, category, reason string) error
+}
+
+// SystemFlagger 以系统身份写入举报，只依赖举报仓储，可在其他服务之前创建
+type SystemFlagger struct {
+	reportRepo *repository.ReportRepository
+}
+
+// NewSystemFlagger 创建系统举报器
+func NewSystemFlagger(reportRepo *repository.ReportRepository) *SystemFlagger {
+	return &SystemFlagger{reportRepo: reportRepo}
+}
+
+// Flag 以系统身份举报；每个对象只有一条系统举报：
+// 已处理的重新置为待处理（内容编辑后再次命中仍需复核），待处理的把新的原因追加到说明中
+func (f *SystemFlagger) Flag(ctx context.Context, targetType string, targetID, targetAgentID, communityID int64, category, reason string) error {
+	reason = truncateDetail(reason)
+	created, err := f.reportRepo.Create(ctx, &model.Report{
+		ReporterAgentID: model.SystemReporterID,
+		TargetType:      targetType,
+		TargetID:        targetID,
+		TargetAgentID:   targetAgentID,
+		CommunityID:     communityID,
+		Category:        category,
+		Detail:          &reason,
+		Status:          model.ReportStatusOpen,
+	})
+	if err != nil || created {
+		return err
+	}
+	existing, err := f.reportRepo.GetByReporterTarget(ctx, model.SystemReporterID, targetType, targetID)
+	if err != nil || existing == nil {
+		return err
+	}
+	if existing.Status != model.ReportStatusOpen {
+		_, err = f.reportRepo.Reopen(ctx, existing.ID, category, &reason, time.Now())
+		return err
+	}
+	if existing.Detail == nil || *existing.Detail == "" {
+		return f.reportRepo.UpdateOpenDetail(ctx, existing.ID, &reason)
+	}
+	if strings.Contains(*existing.Detail, reason) {
+		return nil
+	}
+	detail := truncateDetail(*existing.Detail + "; " + reason)
+	return f.reportRepo.UpdateOpenDetail(ctx, existing.ID, &detail)
+}
+
+// truncateDetail 举报说明超出长度时截断
+func truncateDetail(s string) string {
+	if r := []rune(s); len(r) > 500 {
+		return string(r[:500])
+	}
+	return s
+}
+
+// CreateReportInput 举报输入
+type CreateReportInput struct {
+	TargetType string  `json:"target_type" binding:"required,oneof=post comment agent"`
+	TargetID   int64   `json:"target_id" binding:"required"`
+	Category   string  `json:"category" binding:"required,oneof=spam abuse point_farming low_quality other"`
+	Detail     *string `json:"detail" binding:"omitempty,max=500"`
+}
+
+// ResolveReportInput 处理举报输入；Action 为空表示不处罚
+// 封禁时 SuspendScope 缺省为完全封禁，SuspendHours 为 0 表示永久封禁，最长 8760 小时（一年）
+type ResolveReportInput struct {
+	Status       string  `json:"status" binding:"required,oneof=actioned dismissed"`
+	Action       string  `json:"action" binding:"omitempty,oneof=reset_points suspend"`
+	Note         *string `json:"note" binding:"omitempty,max=500"`
+	SuspendScope string  `json:"suspend_scope" binding:"omitempty,oneof=read_only no_vote ban"`
+	SuspendHours int     `json:"suspend_hours" binding:"min=0,max=8760"`
+}
+
+// ReportQuery 举报队列查询条件
+type ReportQuery struct {
+	Community  string
+	Status     string
+	TargetType string
+}
+
+// ReportService 举报与审核队列（举报服务）
+// 举报落在被举报内容所在社区的队列中，由该社区版主或 Admin 处理；举报 Agent 只有 Admin 可见
+// 积分清零与封禁影响全站，仅 Admin 及以上可执行
+type ReportService struct {
+	reportRepo    *repository.ReportRepository
+	suspensions   *SuspensionService
+	postRepo      *contentRepo.PostRepository
+	commentRepo   *contentRepo.CommentRepository
+	communityRepo *contentRepo.CommunityRepository
+	agentRepo     *userRepo.AgentRepository
+	pointsAdder   pointsService.TxAdder
+	checker       *authz.Checker
+	agentCache    *cache.AgentCache
+	leaderboards  *cache.LeaderboardCache
+}
+
+// NewReportService 创建举报服务，pointsAdder 可为 nil（此时不支持积分清零）
+func NewReportService(
+	reportRepo *repository.ReportRepository,
+	suspensions *SuspensionService,
+	postRepo *contentRepo.PostRepository,
+	commentRepo *contentRepo.CommentRepository,
+	communityRepo *contentRepo.CommunityRepository,
+	agentRepo *userRepo.AgentRepository,
+	pointsAdder pointsService.TxAdder,
+	checker *authz.Checker,
+	agentCache *cache.AgentCache,
+	leaderboards *cache.LeaderboardCache,
+) *ReportService {
+	return &ReportService{
+		reportRepo:    reportRepo,
+		suspensions:   suspensions,
+		postRepo:      postRepo,
+		commentRepo:   commentRepo,
+		communityRepo: communityRepo,
+		agentRepo:     agentRepo,
+		pointsAdder:   pointsAdder,
+		checker:       checker,
+		agentCache:    agentCache,
+		leaderboards:  leaderboards,
+	}
+}
+
+// Create 举报帖子、评论或 Agent；同一对象重复举报返回 ErrAlreadyReported
+func (s *ReportService) Create(ctx context.Context, reporterAgentID int64, in CreateReportInput) (*model.Report, error) {
+	if reporterAgentID <= 0 {
+		return nil, ErrAgentRequired
+	}
+	authorID, communityID, err := s.resolveTarget(ctx, in.TargetType, in.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == reporterAgentID {
+		return nil, ErrCannotReportSelf
+	}
+
+	m := &model.Report{
+		ReporterAgentID: reporterAgentID,
+		TargetType:      in.TargetType,
+		TargetID:        in.TargetID,
+		TargetAgentID:   authorID,
+		CommunityID:     communityID,
+		Category:        in.Category,
+		Detail:          in.Detail,
+		Status:          model.ReportStatusOpen,
+	}
+	created, err := s.reportRepo.Create(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyReported
+	}
+	return m, nil
+}
+
+// List 查询审核队列；指定社区时需为该社区版主，不指定时需 Admin 及以上
+func (s *ReportService) List(ctx context.Context, actor authz.Actor, q ReportQuery, limit, offset int) ([]*model.Report, int64, error) {
+	switch q.Status {
+	case "", model.ReportStatusOpen, model.ReportStatusActioned, model.ReportStatusDismissed:
+	default:
+		return nil, 0, ErrInvalidStatus
+	}
+	f := repository.ReportFilter{Status: q.Status, TargetType: q.TargetType}
+	if q.Community != "" {
+		c, err := s.communityRepo.GetByName(ctx, q.Community)
+		if err != nil {
+			return nil, 0, err
+		}
+		if c == nil {
+			return nil, 0, ErrCommunityNotFound
+		}
+		f.CommunityID = c.ID
+	}
+	if err := authorizeModerate(ctx, s.checker, actor, f.CommunityID); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.reportRepo.List(ctx, f, limit, offset)
+}
+
+// Resolve 处理举报：同一对象下其余待处理的举报一并结案，并按 Action 对被举报 Agent 执行处罚
+func (s *ReportService) Resolve(ctx context.Context, actor authz.Actor, reportID int64, in ResolveReportInput) (*model.Report, error) {
+	r, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ErrReportNotFound
+	}
+	if err := authorizeModerate(ctx, s.checker, actor, r.CommunityID); err != nil {
+		return nil, err
+	}
+	if r.Status != model.ReportStatusOpen {
+		return nil, ErrReportResolved
+	}
+	var action *string
+	if in.Action != "" {
+		if in.Status != model.ReportStatusActioned {
+			return nil, ErrInvalidAction
+		}
+		if !authz.HasRole(actor.Role, model.RoleAdmin) {
+			return nil, ErrForbidden
+		}
+		if in.Action == model.ReportActionResetPoints && s.pointsAdder == nil {
+			return nil, ErrInvalidAction
+		}
+		if in.Action == model.ReportActionSuspend {
+			a, err := s.agentRepo.GetByIDWithUser(ctx, r.TargetAgentID)
+			if err != nil {
+				return nil, err
+			}
+			if a == nil {
+				return nil, ErrAgentNotFound
+			}
+			if err := checkOutranks(actor, a); err != nil {
+				return nil, err
+			}
+		}
+		action = &in.Action
+	}
+
+	now := time.Now()
+	var cleared int
+	err = s.reportRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		n, err := s.reportRepo.WithTx(tx).ResolveOpenByTarget(ctx, r.TargetType, r.TargetID, in.Status, action, in.Note, actor.UserID, now)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrReportResolved
+		}
+		switch in.Action {
+		case model.ReportActionResetPoints:
+			cleared, err = s.pointsAdder.ResetPointsTx(ctx, tx, r.TargetAgentID, model.PointsReasonPointsReset, &r.ID)
+			return err
+		case model.ReportActionSuspend:
+			sus := SuspendInput{Scope: in.SuspendScope, Reason: r.Category, Hours: in.SuspendHours}
+			if sus.Scope == "" {
+				sus.Scope = model.SuspensionScopeBan
+			}
+			if in.Note != nil && *in.Note != "" {
+				sus.Reason = *in.Note
+			}
+			_, err := s.suspensions.suspend(ctx, s.suspensions.repo.WithTx(tx), actor, r.TargetAgentID, sus, &r.ID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cleared > 0 {
+		s.agentCache.InvalidateByID(ctx, r.TargetAgentID)
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	r.Status = in.Status
+	r.Action = action
+	r.ResolutionNote = in.Note
+	r.ResolvedByUserID = &actor.UserID
+	r.ResolvedAt = &now
+	return r, nil
+}
+
+// resolveTarget 返回被举报对象的作者 Agent 与所属社区（举报 Agent 时社区为 0）
+func (s *ReportService) resolveTarget(ctx context.Context, targetType string, targetID int64) (authorID, communityID int64, err error) {
+	switch targetType {
+	case model.ReportTargetPost:
+		p, err := s.postRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if p == nil {
+			return 0, 0, ErrPostNotFound
+		}
+		return p.AgentID, p.CommunityID, nil
+	case model.ReportTargetComment:
+		c, err := s.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if c == nil {
+			return 0, 0, ErrCommentNotFound
+		}
+		p, err := s.postRepo.GetByID(ctx, c.PostID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if p != nil {
+			communityID = p.CommunityID
+		}
+		return c.AgentID, communityID, nil
+	default:
+		a, err := s.agentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if a == nil {
+			return 0, 0, ErrAgentNotFound
+		}
+		return a.ID, 0, nil
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/authz"
+	"agent-hub/internal/model"
+	"agent-hub/internal/moderation/repository"
+	userRepo "agent-hub/internal/user/repository"
+)
+
+// Guard 供其他模块调用的封禁检查接口（避免循环依赖）
+type Guard interface {
+	// Allows agentID 当前是否允许 activity 类操作（model.ActivityAccess / ActivityWrite / ActivityVote）
+	Allows(ctx context.Context, agentID int64, activity string) (bool, error)
+}
+
+// SuspensionLookup 查询 Agent 当前生效的封禁，供展示使用
+type SuspensionLookup interface {
+	Active(ctx context.Context, agentID int64) (*model.AgentSuspension, error)
+}
+
+// SuspendInput 封禁输入；Hours 为 0 表示永久封禁，最长 8760 小时（一年）
+type SuspendInput struct {
+	Scope  string `json:"scope" binding:"required,oneof=read_only no_vote ban"`
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+	Hours  int    `json:"hours" binding:"min=0,max=8760"`
+}
+
+// SuspensionService Agent 封禁（封禁服务）
+// 封禁按时间判断是否生效，到期后无需任何操作即自动失效；同一 Agent 可同时有多条封禁，按最严格的范围约束
+type SuspensionService struct {
+	repo      *repository.SuspensionRepository
+	agentRepo *userRepo.AgentRepository
+	now       func() time.Time
+}
+
+// NewSuspensionService 创建封禁服务
+func NewSuspensionService(repo *repository.SuspensionRepository, agentRepo *userRepo.AgentRepository) *SuspensionService {
+	return &SuspensionService{repo: repo, agentRepo: agentRepo, now: time.Now}
+}
+
+// Allows 实现 Guard
+func (s *SuspensionService) Allows(ctx context.Context, agentID int64, activity string) (bool, error) {
+	if agentID <= 0 {
+		return true, nil
+	}
+	list, err := s.repo.ListActive(ctx, agentID, s.now())
+	if err != nil {
+		return false, err
+	}
+	for _, m := range list {
+		if m.Forbids(activity) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Active 返回 Agent 当前生效的最严格封禁，未被封禁时返回 nil
+func (s *SuspensionService) Active(ctx context.Context, agentID int64) (*model.AgentSuspension, error) {
+	list, err := s.repo.ListActive(ctx, agentID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	var active *model.AgentSuspension
+	for _, m := range list {
+		if active == nil || m.Severity() > active.Severity() ||
+			(m.Severity() == active.Severity() && endsLater(m, active)) {
+			active = m
+		}
+	}
+	return active, nil
+}
+
+// Suspend 封禁 Agent（Admin 及以上，且只能封禁角色低于自己的用户的 Agent）
+func (s *SuspensionService) Suspend(ctx context.Context, actor authz.Actor, agentName string, in SuspendInput) (*model.AgentSuspension, error) {
+	a, err := s.agentRepo.GetByNameWithUser(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAgentNotFound
+	}
+	if err := checkOutranks(actor, a); err != nil {
+		return nil, err
+	}
+	return s.suspend(ctx, s.repo, actor, a.ID, in, nil)
+}
+
+// ListByAgent 查询 Agent 的封禁记录
+func (s *SuspensionService) ListByAgent(ctx context.Context, agentName string) ([]*model.AgentSuspension, error) {
+	a, err := s.agentRepo.GetByName(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAgentNotFound
+	}
+	return s.repo.ListByAgent(ctx, a.ID)
+}
+
+// Lift 提前解除封禁，与封禁相同只能解除角色低于自己的用户的 Agent；已解除或已到期的封禁返回 ErrSuspensionInactive
+func (s *SuspensionService) Lift(ctx context.Context, actor authz.Actor, id int64) (*model.AgentSuspension, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrSuspensionNotFound
+	}
+	a, err := s.agentRepo.GetByIDWithUser(ctx, m.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		if err := checkOutranks(actor, a); err != nil {
+			return nil, err
+		}
+	}
+	now := s.now()
+	if m.LiftedAt != nil || (m.EndsAt != nil && !m.EndsAt.After(now)) {
+		return nil, ErrSuspensionInactive
+	}
+	ok, err := s.repo.Lift(ctx, id, actor.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSuspensionInactive
+	}
+	m.LiftedAt = &now
+	m.LiftedByUserID = &actor.UserID
+	return m, nil
+}
+
+// suspend 通过 repo（可绑定事务）写入封禁记录
+func (s *SuspensionService) suspend(ctx context.Context, repo *repository.SuspensionRepository, actor authz.Actor, agentID int64, in SuspendInput, reportID *int64) (*model.AgentSuspension, error) {
+	now := s.now()
+	m := &model.AgentSuspension{
+		AgentID:         agentID,
+		Scope:           in.Scope,
+		Reason:          in.Reason,
+		StartsAt:        now,
+		CreatedByUserID: actor.UserID,
+		ReportID:        reportID,
+	}
+	if in.Hours > 0 {
+		end := now.Add(time.Duration(in.Hours) * time.Hour)
+		m.EndsAt = &end
+	}
+	if err := repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// checkOutranks 只能封禁角色低于自己的用户的 Agent（同时排除封禁自己）
+func checkOutranks(actor authz.Actor, a *model.Agent) error {
+	role := model.RoleMember
+	if a.User != nil {
+		role = a.User.Role
+	}
+	if a.ID == actor.AgentID || authz.HasRole(role, actor.Role) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// endsLater a 是否比 b 更晚到期（永久封禁视为最晚）
+func endsLater(a, b *model.AgentSuspension) bool {
+	if a.EndsAt == nil {
+		return b.EndsAt != nil
+	}
+	return b.EndsAt != nil && a.EndsAt.After(*b.EndsAt)
+}
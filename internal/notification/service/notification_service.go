@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"agent-hub/internal/model"
 	"agent-hub/internal/notification/repository"
@@ -24,6 +25,9 @@ func NewNotificationService(repo *repository.NotificationRepository) *Notificati
 type Notifier interface {
 	NotifyCommentOnPost(ctx context.Context, postAuthorAgentID, actorAgentID, postID, commentID int64, commentSummary string) error
 	NotifyNewFollow(ctx context.Context, followedAgentID, followerAgentID int64) error
+	NotifyContentRemoved(ctx context.Context, authorAgentID, actorAgentID, targetID int64, targetType, reason string) error
+	NotifyContentRestored(ctx context.Context, authorAgentID, actorAgentID, targetID int64, targetType string) error
+	NotifyAccountLocked(ctx context.Context, agentID int64, until time.Time) error
 }
 
 // NotifyCommentOnPost 帖子被评论时通知帖子作者
@@ -55,8 +59,51 @@ func (s *NotificationService) NotifyNewFollow(ctx context.Context, followedAgent
 	})
 }
 
+// NotifyContentRemoved 内容被移除时通知作者，附带移除原因；actorAgentID 为 0 表示操作者没有 Agent
+func (s *NotificationService) NotifyContentRemoved(ctx context.Context, authorAgentID, actorAgentID, targetID int64, targetType, reason string) error {
+	return s.repo.Create(ctx, &model.Notification{
+		AgentID:           authorAgentID,
+		Type:              model.NotificationTypeContentRemoved,
+		Title:             "内容被移除",
+		Content:           &reason,
+		RelatedEntityID:   &targetID,
+		RelatedEntityType: &targetType,
+		ActorAgentID:      agentPtr(actorAgentID),
+	})
+}
+
+// NotifyContentRestored 被移除的内容恢复时通知作者
+func (s *NotificationService) NotifyContentRestored(ctx context.Context, authorAgentID, actorAgentID, targetID int64, targetType string) error {
+	return s.repo.Create(ctx, &model.Notification{
+		AgentID:           authorAgentID,
+		Type:              model.NotificationTypeContentRestored,
+		Title:             "内容已恢复",
+		RelatedEntityID:   &targetID,
+		RelatedEntityType: &targetType,
+		ActorAgentID:      agentPtr(actorAgentID),
+	})
+}
+
+// NotifyAccountLocked 账号因连续登录失败被锁定时通知账号所有者，提示可能有人在尝试猜测密码
+func (s *NotificationService) NotifyAccountLocked(ctx context.Context, agentID int64, until time.Time) error {
+	content := "检测到多次登录失败，账号已临时锁定至 " + until.Format(time.RFC3339) + "。如非本人操作，建议尽快修改密码"
+	return s.repo.Create(ctx, &model.Notification{
+		AgentID: agentID,
+		Type:    model.NotificationTypeAccountLocked,
+		Title:   "账号已临时锁定",
+		Content: &content,
+	})
+}
+
 func strPtr(s string) *string { return &s }
 
+func agentPtr(id int64) *int64 {
+	if id <= 0 {
+		return nil
+	}
+	return &id
+}
+
 // List 获取当前 Agent 的通知列表
 func (s *NotificationService) List(ctx context.Context, agentID int64, limit, offset int) ([]*model.Notification, int64, error) {
 	if limit <= 0 {
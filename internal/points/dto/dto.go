@@ -0,0 +1,63 @@
+package dto
+
+// PointsLogResponse 积分变动明细
+type PointsLogResponse struct {
+	ID              int64  `json:"id"`
+	PointsChange    int    `json:"points_change"`
+	Reason          string `json:"reason"`
+	RelatedEntityID *int64 `json:"related_entity_id,omitempty"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// TodayEarningResponse 当日某来源的积分，daily_cap 为 0 表示没有每日上限
+type TodayEarningResponse struct {
+	Reason    string `json:"reason"`
+	Points    int    `json:"points"`
+	DailyCap  int    `json:"daily_cap"`
+	Remaining *int   `json:"remaining,omitempty"` // 距每日上限还可获得的积分，无上限时省略
+}
+
+// ReasonTotalResponse 某来源的累计积分
+type ReasonTotalResponse struct {
+	Reason string `json:"reason"`
+	Points int    `json:"points"`
+}
+
+// PointsSummaryResponse 积分摘要
+type PointsSummaryResponse struct {
+	Balance  int                    `json:"balance"`
+	Today    []TodayEarningResponse `json:"today"`
+	ResetsAt string                 `json:"resets_at"`
+	Lifetime []ReasonTotalResponse  `json:"lifetime"`
+}
+
+// PointsRuleResponse 积分规则版本，id 为 0 表示内置缺省规则
+type PointsRuleResponse struct {
+	ID            int64  `json:"id"`
+	Reason        string `json:"reason"`
+	Points        int    `json:"points"`
+	OneTime       bool   `json:"one_time"`
+	DailyCap      int    `json:"daily_cap"`
+	PerTargetCap  int    `json:"per_target_cap"`
+	EffectiveFrom string `json:"effective_from,omitempty"` // 内置缺省规则为空
+	Active        bool   `json:"active"`                   // 当前是否为该 reason 生效的版本
+}
+
+// AgentReplayResponse 单个 Agent 的试算结果
+type AgentReplayResponse struct {
+	AgentID  int64 `json:"agent_id"`
+	Balance  int   `json:"balance"`
+	Baseline int   `json:"baseline"`
+	Proposed int   `json:"proposed"`
+	Delta    int   `json:"delta"`
+}
+
+// DryRunResponse 规则试算结果
+type DryRunResponse struct {
+	AgentsScanned int                   `json:"agents_scanned"`
+	AgentsChanged int                   `json:"agents_changed"`
+	LogsReplayed  int                   `json:"logs_replayed"`
+	TotalBaseline int                   `json:"total_baseline"`
+	TotalProposed int                   `json:"total_proposed"`
+	Agents        []AgentReplayResponse `json:"agents"`
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/points/dto"
+	"agent-hub/internal/points/repository"
+	"agent-hub/internal/points/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+const timeLayout = "2006-01-02T15:04:05Z07:00"
+
+// PointsHandler 积分明细与摘要 HTTP 接口
+type PointsHandler struct {
+	pointsService *service.PointsService
+}
+
+// NewPointsHandler 创建积分 Handler
+func NewPointsHandler(pointsService *service.PointsService) *PointsHandler {
+	return &PointsHandler{pointsService: pointsService}
+}
+
+// History GET /api/v1/me/points/history?reason=&from=&to=&limit=&offset=
+// from / to 接受 RFC3339 时间或 YYYY-MM-DD 日期（UTC），日期形式的 to 包含当天
+func (h *PointsHandler) History(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := repository.LogFilter{Reason: c.Query("reason")}
+	var ok bool
+	if filter.From, ok = parseBound(c.Query("from"), false); !ok {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "from must be RFC3339 or YYYY-MM-DD")
+		return
+	}
+	if filter.To, ok = parseBound(c.Query("to"), true); !ok {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "to must be RFC3339 or YYYY-MM-DD")
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "from must be before to")
+		return
+	}
+
+	list, total, err := h.pointsService.History(c.Request.Context(), agentID, filter, limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List points history failed")
+		return
+	}
+
+	items := make([]dto.PointsLogResponse, len(list))
+	for i, l := range list {
+		items[i] = dto.PointsLogResponse{
+			ID:              l.ID,
+			PointsChange:    l.PointsChange,
+			Reason:          l.Reason,
+			RelatedEntityID: l.RelatedEntityID,
+			CreatedAt:       l.CreatedAt.Format(timeLayout),
+		}
+	}
+	response.OK(c, gin.H{"history": items, "total": total})
+}
+
+// Summary GET /api/v1/me/points/summary
+func (h *PointsHandler) Summary(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+
+	s, err := h.pointsService.Summary(c.Request.Context(), agentID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get points summary failed")
+		return
+	}
+
+	out := dto.PointsSummaryResponse{
+		Balance:  s.Balance,
+		Today:    make([]dto.TodayEarningResponse, len(s.Today)),
+		ResetsAt: s.ResetsAt.Format(timeLayout),
+		Lifetime: make([]dto.ReasonTotalResponse, len(s.Lifetime)),
+	}
+	for i, t := range s.Today {
+		out.Today[i] = dto.TodayEarningResponse{Reason: t.Reason, Points: t.Points, DailyCap: t.DailyCap}
+		if t.DailyCap > 0 {
+			remaining := t.Remaining
+			out.Today[i].Remaining = &remaining
+		}
+	}
+	for i, r := range s.Lifetime {
+		out.Lifetime[i] = dto.ReasonTotalResponse{Reason: r.Reason, Points: r.Points}
+	}
+	response.OK(c, out)
+}
+
+// parseBound 解析时间范围参数，空串返回零值；endOfDay 为 true 时日期形式取次日零点，使范围包含当天
+func parseBound(v string, endOfDay bool) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.Add(24 * time.Hour)
+	}
+	return t, true
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/points/dto"
+	"agent-hub/internal/points/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// RuleHandler 积分规则管理 HTTP 接口（Admin）
+type RuleHandler struct {
+	pointsService *service.PointsService
+}
+
+// NewRuleHandler 创建积分规则 Handler
+func NewRuleHandler(pointsService *service.PointsService) *RuleHandler {
+	return &RuleHandler{pointsService: pointsService}
+}
+
+// List GET /api/v1/admin/points/rules
+func (h *RuleHandler) List(c *gin.Context) {
+	rs := h.pointsService.Rules()
+	now := time.Now()
+	active := make(map[string]service.Rule)
+	for _, r := range rs.Active(now) {
+		active[r.Reason] = r
+	}
+
+	all := rs.Rules()
+	items := make([]dto.PointsRuleResponse, len(all))
+	for i, r := range all {
+		items[i] = toRuleResponse(r)
+		// 内置缺省规则 ID 为 0，每个 reason 只有一条，按 ID 即可区分版本
+		if a, ok := active[r.Reason]; ok {
+			items[i].Active = a.ID == r.ID
+		}
+	}
+	response.OK(c, gin.H{"rules": items})
+}
+
+// Create POST /api/v1/admin/points/rules
+func (h *RuleHandler) Create(c *gin.Context) {
+	var in service.RuleInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	m, err := h.pointsService.CreateRule(c.Request.Context(), middleware.MustGetUserID(c), in)
+	if err != nil {
+		if err == service.ErrUnknownReason {
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Unknown points reason")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create points rule failed")
+		return
+	}
+	rule := service.RuleFromModel(m)
+	resp := toRuleResponse(rule)
+	if current, ok := h.pointsService.Rules().Lookup(rule.Reason, time.Now()); ok {
+		resp.Active = current.ID == rule.ID
+	}
+	response.JSON(c, http.StatusCreated, resp)
+}
+
+// DryRun POST /api/v1/admin/points/rules/dry-run
+func (h *RuleHandler) DryRun(c *gin.Context) {
+	var in service.DryRunInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	res, err := h.pointsService.DryRun(c.Request.Context(), in)
+	if err != nil {
+		if err == service.ErrUnknownReason {
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Unknown points reason")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Dry run failed")
+		return
+	}
+
+	out := dto.DryRunResponse{
+		AgentsScanned: res.AgentsScanned,
+		AgentsChanged: res.AgentsChanged,
+		LogsReplayed:  res.LogsReplayed,
+		TotalBaseline: res.TotalBaseline,
+		TotalProposed: res.TotalProposed,
+		Agents:        make([]dto.AgentReplayResponse, len(res.Agents)),
+	}
+	for i, a := range res.Agents {
+		out.Agents[i] = dto.AgentReplayResponse{
+			AgentID:  a.AgentID,
+			Balance:  a.Balance,
+			Baseline: a.Baseline,
+			Proposed: a.Proposed,
+			Delta:    a.Delta,
+		}
+	}
+	response.OK(c, out)
+}
+
+func toRuleResponse(r service.Rule) dto.PointsRuleResponse {
+	resp := dto.PointsRuleResponse{
+		ID:           r.ID,
+		Reason:       r.Reason,
+		Points:       r.Points,
+		OneTime:      r.OneTime,
+		DailyCap:     r.DailyCap,
+		PerTargetCap: r.PerTargetCap,
+	}
+	if !r.EffectiveFrom.IsZero() {
+		resp.EffectiveFrom = r.EffectiveFrom.Format(timeLayout)
+	}
+	return resp
+}
@@ -6,6 +6,7 @@ import (
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PointsRepository 积分与积分日志数据访问层
@@ -18,22 +19,147 @@ func NewPointsRepository(db *gorm.DB) *PointsRepository {
 	return &PointsRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *PointsRepository) WithTx(tx *gorm.DB) *PointsRepository {
+	return &PointsRepository{db: tx}
+}
+
+// Transaction 在事务中执行 fn
+func (r *PointsRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
 // CreateLog 写入积分日志
 func (r *PointsRepository) CreateLog(ctx context.Context, log *model.PointsLog) error {
 	return r.db.WithContext(ctx).Create(log).Error
 }
 
-// SumTodayPointsByAgentAndReason 统计当日某 Agent 某 reason 的积分总和（用于每日上限）
-func (r *PointsRepository) SumTodayPointsByAgentAndReason(ctx context.Context, agentID int64, reason string) (int, error) {
-	start := time.Now().UTC().Truncate(24 * time.Hour)
+// TodayStart 每日上限的统计起点（UTC 零点）
+func TodayStart() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// SumTodayPointsByAgentAndReason 统计当日某 Agent 某 reason 的积分总和（用于每日上限）；
+// reversal 非空时一并计入该 reason 的负向冲正日志，使撤销后的积分退回当日额度
+func (r *PointsRepository) SumTodayPointsByAgentAndReason(ctx context.Context, agentID int64, reason, reversal string) (int, error) {
+	start := TodayStart()
+	query := r.db.WithContext(ctx).Model(&model.PointsLog{}).
+		Select("COALESCE(SUM(points_change), 0)").
+		Where("agent_id = ? AND created_at >= ?", agentID, start)
+	if reversal != "" {
+		query = query.Where("(reason = ? OR (reason = ? AND points_change < 0))", reason, reversal)
+	} else {
+		query = query.Where("reason = ?", reason)
+	}
+	var sum int
+	err := query.Scan(&sum).Error
+	return sum, err
+}
+
+// LogFilter 积分日志查询条件，零值字段不过滤；From 含、To 不含
+type LogFilter struct {
+	Reason string
+	From   time.Time
+	To     time.Time
+}
+
+// apply 将 Agent 与过滤条件追加到查询链
+func (f LogFilter) apply(q *gorm.DB, agentID int64) *gorm.DB {
+	q = q.Where("agent_id = ?", agentID)
+	if f.Reason != "" {
+		q = q.Where("reason = ?", f.Reason)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+	return q
+}
+
+// ListLogs 分页查询某 Agent 的积分日志，按时间倒序
+func (r *PointsRepository) ListLogs(ctx context.Context, agentID int64, filter LogFilter, limit, offset int) ([]*model.PointsLog, int64, error) {
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := filter.apply(r.db.WithContext(ctx).Model(&model.PointsLog{}), agentID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*model.PointsLog
+	err := filter.apply(r.db.WithContext(ctx).Model(&model.PointsLog{}), agentID).
+		Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// ReasonSum 某 reason 的积分合计
+type ReasonSum struct {
+	Reason string
+	Points int
+}
+
+// SumByReason 按 reason 汇总某 Agent since 之后的积分变动，since 为零值时汇总全部日志
+func (r *PointsRepository) SumByReason(ctx context.Context, agentID int64, since time.Time) ([]ReasonSum, error) {
+	query := r.db.WithContext(ctx).Model(&model.PointsLog{}).
+		Select("reason, COALESCE(SUM(points_change), 0) AS points").
+		Where("agent_id = ?", agentID)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	var sums []ReasonSum
+	err := query.Group("reason").Order("reason").Scan(&sums).Error
+	return sums, err
+}
+
+// GetAgentPoints 读取 Agent 当前积分，Agent 不存在时返回 0
+func (r *PointsRepository) GetAgentPoints(ctx context.Context, agentID int64) (int, error) {
+	var a model.Agent
+	err := r.db.WithContext(ctx).Select("id", "points").First(&a, agentID).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return a.Points, err
+}
+
+// SumPointsByAgentReasonAndEntity 统计某 Agent 在同一关联对象上某 reason 的积分总和（用于单对象上限）
+func (r *PointsRepository) SumPointsByAgentReasonAndEntity(ctx context.Context, agentID int64, reason string, entityID int64) (int, error) {
 	var sum int
 	err := r.db.WithContext(ctx).Model(&model.PointsLog{}).
 		Select("COALESCE(SUM(points_change), 0)").
-		Where("agent_id = ? AND reason = ? AND created_at >= ?", agentID, reason, start).
+		Where("agent_id = ? AND reason = ? AND related_entity_id = ?", agentID, reason, entityID).
 		Scan(&sum).Error
 	return sum, err
 }
 
+// ListAgentIDsWithLogs 按 ID 升序返回 afterID 之后有积分日志的 Agent，用于分批遍历
+func (r *PointsRepository) ListAgentIDsWithLogs(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&model.PointsLog{}).
+		Distinct("agent_id").Where("agent_id > ?", afterID).
+		Order("agent_id").Limit(limit).Pluck("agent_id", &ids).Error
+	return ids, err
+}
+
+// ListLogsByAgents 查询多个 Agent 的全部积分日志，按 Agent、时间正序
+func (r *PointsRepository) ListLogsByAgents(ctx context.Context, agentIDs []int64) ([]*model.PointsLog, error) {
+	var list []*model.PointsLog
+	err := r.db.WithContext(ctx).Where("agent_id IN ?", agentIDs).
+		Order("agent_id, created_at, id").Find(&list).Error
+	return list, err
+}
+
+// GetAgentPointsByIDs 批量读取 Agent 当前积分，不存在的 Agent 不出现在结果中
+func (r *PointsRepository) GetAgentPointsByIDs(ctx context.Context, agentIDs []int64) (map[int64]int, error) {
+	var agents []model.Agent
+	if err := r.db.WithContext(ctx).Select("id", "points").Where("id IN ?", agentIDs).Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]int, len(agents))
+	for _, a := range agents {
+		out[a.ID] = a.Points
+	}
+	return out, nil
+}
+
 // HasReasonOnce 是否已有过该 reason 记录（一次性奖励用）
 func (r *PointsRepository) HasReasonOnce(ctx context.Context, agentID int64, reason string) (bool, error) {
 	var count int64
@@ -52,6 +178,30 @@ func (r *PointsRepository) AddAgentPoints(ctx context.Context, agentID int64, de
 		).Error
 }
 
+// ApplyAgentPoints 加行锁后按 delta 调整 Agent 积分（积分不低于 0），返回实际生效的变动（须在事务内调用）
+func (r *PointsRepository) ApplyAgentPoints(ctx context.Context, agentID int64, delta int) (int, error) {
+	current, err := r.GetAgentPointsForUpdate(ctx, agentID)
+	if err != nil {
+		return 0, err
+	}
+	applied := max(delta, -current)
+	if applied == 0 {
+		return 0, nil
+	}
+	return applied, r.AddAgentPoints(ctx, agentID, applied)
+}
+
+// GetAgentPointsForUpdate 加行锁读取 Agent 当前积分（须在事务内调用）
+func (r *PointsRepository) GetAgentPointsForUpdate(ctx context.Context, agentID int64) (int, error) {
+	var a model.Agent
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "points").First(&a, agentID).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return a.Points, err
+}
+
 func (r *PointsRepository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.WithContext(ctx).DB()
 	if err != nil {
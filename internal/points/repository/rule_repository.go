@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// RuleRepository 积分规则数据访问层
+type RuleRepository struct {
+	db *gorm.DB
+}
+
+// NewRuleRepository 创建积分规则仓储
+func NewRuleRepository(db *gorm.DB) *RuleRepository {
+	return &RuleRepository{db: db}
+}
+
+// Create 新增一个规则版本
+func (r *RuleRepository) Create(ctx context.Context, rule *model.PointsRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// ListAll 查询全部规则版本，按 reason、生效时间、ID 排序
+func (r *RuleRepository) ListAll(ctx context.Context) ([]*model.PointsRule, error) {
+	var list []*model.PointsRule
+	err := r.db.WithContext(ctx).Order("reason, effective_from, id").Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"agent-hub/internal/model"
+)
+
+// ErrUnknownReason 规则的 reason 不是可配置的积分来源
+var ErrUnknownReason = errors.New("unknown points reason")
+
+// dryRunBatch 重放时每批加载日志的 Agent 数
+const dryRunBatch = 200
+
+// RuleInput 积分规则输入；创建规则时 EffectiveFrom 为空表示立即生效，试算时为空表示对全部历史生效
+type RuleInput struct {
+	Reason        string     `json:"reason" binding:"required,min=1,max=100"`
+	Points        int        `json:"points"`
+	OneTime       bool       `json:"one_time"`
+	DailyCap      int        `json:"daily_cap" binding:"min=0"`
+	PerTargetCap  int        `json:"per_target_cap" binding:"min=0"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// DryRunInput 规则试算输入：AgentIDs 为空时重放全部有积分日志的 Agent，Limit 为返回的 Agent 数上限
+type DryRunInput struct {
+	Rules    []RuleInput `json:"rules" binding:"required,min=1,dive"`
+	AgentIDs []int64     `json:"agent_ids"`
+	Limit    int         `json:"limit"`
+}
+
+// AgentReplay 单个 Agent 的试算结果：Baseline 为按现行规则重放的余额，Proposed 为按提议规则重放的余额
+type AgentReplay struct {
+	AgentID  int64
+	Balance  int // 当前实际积分
+	Baseline int
+	Proposed int
+	Delta    int // Proposed - Baseline
+}
+
+// DryRunResult 规则试算结果，Agents 只包含余额有变化的 Agent，按变化幅度降序
+type DryRunResult struct {
+	AgentsScanned int
+	AgentsChanged int
+	LogsReplayed  int
+	TotalBaseline int
+	TotalProposed int
+	Agents        []AgentReplay
+}
+
+// configurable 可配置规则的积分来源；冲正、清零类日志金额取决于原始记录，不由规则决定
+func configurable(reason string) bool {
+	for _, r := range DefaultRules() {
+		if r.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateRule 新增一个规则版本并立即重新加载
+func (s *PointsService) CreateRule(ctx context.Context, userID int64, in RuleInput) (*model.PointsRule, error) {
+	if !configurable(in.Reason) {
+		return nil, ErrUnknownReason
+	}
+	m := &model.PointsRule{
+		Reason:          in.Reason,
+		Points:          in.Points,
+		OneTime:         in.OneTime,
+		DailyCap:        in.DailyCap,
+		PerTargetCap:    in.PerTargetCap,
+		EffectiveFrom:   time.Now(),
+		CreatedByUserID: userID,
+	}
+	if in.EffectiveFrom != nil {
+		m.EffectiveFrom = *in.EffectiveFrom
+	}
+	if err := s.ruleRepo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, s.Reload(ctx)
+}
+
+// DryRun 按提议规则重放历史积分日志，与按现行规则重放的结果对比；不修改任何数据
+// 提议规则作为新版本叠加在现行规则之上。日志只记录实际生效的变动，当时因上限未获得积分的行为不会被重放
+func (s *PointsService) DryRun(ctx context.Context, in DryRunInput) (*DryRunResult, error) {
+	current := s.Rules()
+	rules := current.Rules()
+	for _, r := range in.Rules {
+		if !configurable(r.Reason) {
+			return nil, ErrUnknownReason
+		}
+		rule := Rule{Reason: r.Reason, Points: r.Points, OneTime: r.OneTime, DailyCap: r.DailyCap, PerTargetCap: r.PerTargetCap}
+		if r.EffectiveFrom != nil {
+			rule.EffectiveFrom = *r.EffectiveFrom
+		}
+		rules = append(rules, rule)
+	}
+	proposed := NewRuleset(rules)
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	out := &DryRunResult{}
+	replayBatch := func(ids []int64) error {
+		logs, err := s.repo.ListLogsByAgents(ctx, ids)
+		if err != nil {
+			return err
+		}
+		balances, err := s.repo.GetAgentPointsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		byAgent := make(map[int64][]*model.PointsLog, len(ids))
+		for _, l := range logs {
+			byAgent[l.AgentID] = append(byAgent[l.AgentID], l)
+		}
+		for _, id := range ids {
+			agentLogs, ok := byAgent[id]
+			if !ok {
+				continue
+			}
+			r := AgentReplay{
+				AgentID:  id,
+				Balance:  balances[id],
+				Baseline: replay(current, agentLogs),
+				Proposed: replay(proposed, agentLogs),
+			}
+			r.Delta = r.Proposed - r.Baseline
+			out.AgentsScanned++
+			out.LogsReplayed += len(agentLogs)
+			out.TotalBaseline += r.Baseline
+			out.TotalProposed += r.Proposed
+			if r.Delta != 0 {
+				out.AgentsChanged++
+				out.Agents = append(out.Agents, r)
+			}
+		}
+		return nil
+	}
+
+	if len(in.AgentIDs) > 0 {
+		for start := 0; start < len(in.AgentIDs); start += dryRunBatch {
+			if err := replayBatch(in.AgentIDs[start:min(start+dryRunBatch, len(in.AgentIDs))]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		var after int64
+		for {
+			ids, err := s.repo.ListAgentIDsWithLogs(ctx, after, dryRunBatch)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			if err := replayBatch(ids); err != nil {
+				return nil, err
+			}
+			after = ids[len(ids)-1]
+		}
+	}
+
+	sort.Slice(out.Agents, func(i, j int) bool {
+		di, dj := abs(out.Agents[i].Delta), abs(out.Agents[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return out.Agents[i].AgentID < out.Agents[j].AgentID
+	})
+	if len(out.Agents) > limit {
+		out.Agents = out.Agents[:limit]
+	}
+	return out, nil
+}
+
+// replay 按规则集重放一个 Agent 按时间正序的积分日志，返回重放后的余额
+// 规则管辖的来源按规则重新计算（含一次性、每日与单对象上限）；其余日志沿用原始变动，清零日志将余额归零
+func replay(rs *Ruleset, logs []*model.PointsLog) int {
+	balance := 0
+	seen := make(map[string]bool)
+	daily := make(map[string]int)
+	perTarget := make(map[string]int)
+	for _, l := range logs {
+		change := l.PointsChange
+		if l.Reason == model.PointsReasonPointsReset {
+			change = -balance
+		} else if rule, ok := rs.Lookup(l.Reason, l.CreatedAt); ok {
+			dayKey := l.Reason + "@" + l.CreatedAt.UTC().Format("2006-01-02")
+			// 没有关联对象的日志各自视为独立对象，与实时计分一致
+			targetKey := ""
+			if l.RelatedEntityID != nil {
+				targetKey = l.Reason + "#" + strconv.FormatInt(*l.RelatedEntityID, 10)
+			}
+			change = rule.Earned(seen[l.Reason], max(daily[dayKey], 0), perTarget[targetKey])
+			seen[l.Reason] = true
+			if change > 0 {
+				daily[dayKey] += change
+				if targetKey != "" {
+					perTarget[targetKey] += change
+				}
+			}
+		} else if change < 0 {
+			// 撤销冲正退回对应来源当日额度，与实时计分一致
+			for reason, reversal := range capReversals {
+				if l.Reason == reversal {
+					daily[reason+"@"+l.CreatedAt.UTC().Format("2006-01-02")] += change
+				}
+			}
+		}
+		balance = max(balance+change, 0)
+	}
+	return balance
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
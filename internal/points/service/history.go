@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/points/repository"
+)
+
+// TodayEarning 当日某来源的积分；DailyCap 为 0 表示没有每日上限，此时 Remaining 无意义
+type TodayEarning struct {
+	Reason    string
+	Points    int
+	DailyCap  int
+	Remaining int
+}
+
+// Summary 积分摘要：当前余额、当日各来源获得情况（对照每日上限）与历史累计
+// Balance 为 Agent 当前积分，扣分不会低于 0，因此可能与 Lifetime 之和不同
+type Summary struct {
+	Balance  int
+	Today    []TodayEarning
+	ResetsAt time.Time // 每日上限重新计算的时间
+	Lifetime []repository.ReasonSum
+}
+
+// History 分页查询 Agent 的积分变动明细
+func (s *PointsService) History(ctx context.Context, agentID int64, filter repository.LogFilter, limit, offset int) ([]*model.PointsLog, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.ListLogs(ctx, agentID, filter, limit, offset)
+}
+
+// Summary 汇总 Agent 的积分来源
+func (s *PointsService) Summary(ctx context.Context, agentID int64) (*Summary, error) {
+	balance, err := s.repo.GetAgentPoints(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	start := repository.TodayStart()
+	today, err := s.repo.SumByReason(ctx, agentID, start)
+	if err != nil {
+		return nil, err
+	}
+	lifetime, err := s.repo.SumByReason(ctx, agentID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	earned := make(map[string]int, len(today))
+	for _, r := range today {
+		earned[r.Reason] = r.Points
+	}
+	out := &Summary{Balance: balance, ResetsAt: start.Add(24 * time.Hour), Lifetime: lifetime}
+	// 当前有每日上限的来源即使当日未获得也列出
+	for _, rule := range s.Rules().Active(time.Now()) {
+		if rule.OneTime || rule.DailyCap <= 0 || rule.Points <= 0 {
+			continue
+		}
+		used := earned[rule.Reason]
+		if reversal, ok := capReversals[rule.Reason]; ok {
+			// 与实时计分一致，撤销冲正退回的积分计入剩余额度
+			if used, err = s.repo.SumTodayPointsByAgentAndReason(ctx, agentID, rule.Reason, reversal); err != nil {
+				return nil, err
+			}
+		}
+		out.Today = append(out.Today, TodayEarning{
+			Reason:    rule.Reason,
+			Points:    earned[rule.Reason],
+			DailyCap:  rule.DailyCap,
+			Remaining: max(rule.DailyCap-max(used, 0), 0),
+		})
+		delete(earned, rule.Reason)
+	}
+	// 当日其余来源（一次性奖励、扣分、冲正等）按查询顺序附在后面
+	for _, r := range today {
+		if _, ok := earned[r.Reason]; ok {
+			out.Today = append(out.Today, TodayEarning{Reason: r.Reason, Points: r.Points})
+		}
+	}
+	return out, nil
+}
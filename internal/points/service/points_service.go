@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"log"
+	"sync"
+	"time"
 
 	"agent-hub/internal/model"
 	"agent-hub/internal/points/repository"
+	"gorm.io/gorm"
 )
 
 // Adder 供其他模块调用的积分增加接口（避免循环依赖）
@@ -12,7 +16,20 @@ type Adder interface {
 	AddPoints(ctx context.Context, agentID int64, reason string, relatedEntityID *int64) error
 }
 
-// 每日上限：0 表示按「一次性」处理
+// TxAdder 支持在调用方事务内记积分的 Adder，积分变动与业务写入一同提交或回滚
+type TxAdder interface {
+	Adder
+	// AddPointsTx 返回实际生效的积分变动（受每日上限、一次性规则影响，可能为 0）
+	AddPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, reason string, relatedEntityID *int64) (int, error)
+	// RevertPointsTx 冲正此前生效的 points 积分，写入一条 reason 的反向日志
+	RevertPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, points int, reason string, relatedEntityID *int64) error
+	// GrantPointsTx 按指定金额记积分，不经积分规则（金额由调用方的配置决定，如连续活跃奖励）
+	GrantPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, points int, reason string, relatedEntityID *int64) error
+	// ResetPointsTx 将积分清零，返回被清除的积分
+	ResetPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, reason string, relatedEntityID *int64) (int, error)
+}
+
+// 内置缺省规则的每日上限
 const (
 	DailyCapPostCreated    = 50  // 50 分/日
 	DailyCapCommentCreated = 50
@@ -20,81 +37,160 @@ const (
 	DailyCapDailyLogin     = 5
 )
 
+// capReversals 计入来源每日上限的冲正 reason：撤销赞同的负向冲正退回作者当日获赞额度，
+// 反复赞同/撤销不会耗尽作者当日上限
+var capReversals = map[string]string{
+	model.PointsReasonContentUpvoted: model.PointsReasonVoteReversed,
+}
+
 // PointsService 积分计算与管理（积分服务）
+// 积分规则由内置缺省规则与积分规则表合并而成，可在运行时重新加载
 type PointsService struct {
-	repo *repository.PointsRepository
+	repo     *repository.PointsRepository
+	ruleRepo *repository.RuleRepository
+	mu       sync.RWMutex
+	ruleset  *Ruleset
 }
 
-// NewPointsService 创建积分服务
-func NewPointsService(repo *repository.PointsRepository) *PointsService {
-	return &PointsService{repo: repo}
+// NewPointsService 创建积分服务，初始使用内置缺省规则，调用 Reload 后合并规则表；ruleRepo 为 nil 时只使用缺省规则
+func NewPointsService(repo *repository.PointsRepository, ruleRepo *repository.RuleRepository) *PointsService {
+	return &PointsService{repo: repo, ruleRepo: ruleRepo, ruleset: NewRuleset(DefaultRules())}
 }
 
-// AddPoints 根据原因增加/扣减积分，并写日志；内部做每日上限与一次性校验
-func (s *PointsService) AddPoints(ctx context.Context, agentID int64, reason string, relatedEntityID *int64) error {
-	points, oneTime, dailyCap := s.rule(reason)
-	if points == 0 {
+// Rules 当前使用的规则集
+func (s *PointsService) Rules() *Ruleset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ruleset
+}
+
+// Reload 从规则表重新加载规则，失败时沿用当前规则
+func (s *PointsService) Reload(ctx context.Context) error {
+	if s.ruleRepo == nil {
 		return nil
 	}
+	list, err := s.ruleRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	rules := DefaultRules()
+	for _, m := range list {
+		rules = append(rules, RuleFromModel(m))
+	}
+	rs := NewRuleset(rules)
+	s.mu.Lock()
+	s.ruleset = rs
+	s.mu.Unlock()
+	return nil
+}
 
-	if oneTime {
-		has, err := s.repo.HasReasonOnce(ctx, agentID, reason)
-		if err != nil {
-			return err
-		}
-		if has {
-			return nil
-		}
-	} else if dailyCap > 0 && points > 0 {
-		sum, err := s.repo.SumTodayPointsByAgentAndReason(ctx, agentID, reason)
-		if err != nil {
-			return err
-		}
-		if sum >= dailyCap {
-			return nil
+// Run 每隔 interval 重新加载规则（多实例部署时同步其他实例写入的规则），直到 ctx 取消；interval <= 0 时直接返回
+func (s *PointsService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				log.Printf("points rules reload: %v", err)
+			}
 		}
-		if sum+points > dailyCap {
-			points = dailyCap - sum
+	}
+}
+
+// AddPoints 根据原因增加/扣减积分，并写日志；内部做每日上限与一次性校验
+func (s *PointsService) AddPoints(ctx context.Context, agentID int64, reason string, relatedEntityID *int64) error {
+	return s.repo.Transaction(ctx, func(tx *gorm.DB) error {
+		_, err := s.addPoints(ctx, s.repo.WithTx(tx), agentID, reason, relatedEntityID)
+		return err
+	})
+}
+
+// AddPointsTx 同 AddPoints，但在调用方事务 tx 内执行，并返回实际生效的积分变动
+func (s *PointsService) AddPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, reason string, relatedEntityID *int64) (int, error) {
+	return s.addPoints(ctx, s.repo.WithTx(tx), agentID, reason, relatedEntityID)
+}
+
+// RevertPointsTx 在调用方事务 tx 内冲正此前生效的 points 积分（如撤销投票），不受每日上限约束
+func (s *PointsService) RevertPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, points int, reason string, relatedEntityID *int64) error {
+	return s.GrantPointsTx(ctx, tx, agentID, -points, reason, relatedEntityID)
+}
+
+// GrantPointsTx 在调用方事务 tx 内按指定金额记积分并写日志，不经积分规则
+func (s *PointsService) GrantPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, points int, reason string, relatedEntityID *int64) error {
+	_, err := s.applyPoints(ctx, s.repo.WithTx(tx), agentID, points, reason, relatedEntityID)
+	return err
+}
+
+// ResetPointsTx 在调用方事务 tx 内将 Agent 积分清零（如举报核实后处罚），写入一条等额扣减日志
+func (s *PointsService) ResetPointsTx(ctx context.Context, tx *gorm.DB, agentID int64, reason string, relatedEntityID *int64) (int, error) {
+	repo := s.repo.WithTx(tx)
+	points, err := repo.GetAgentPointsForUpdate(ctx, agentID)
+	if err != nil || points <= 0 {
+		return 0, err
+	}
+	if err := s.RevertPointsTx(ctx, tx, agentID, points, reason, relatedEntityID); err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+func (s *PointsService) addPoints(ctx context.Context, repo *repository.PointsRepository, agentID int64, reason string, relatedEntityID *int64) (int, error) {
+	rule, ok := s.Rules().Lookup(reason, time.Now())
+	if !ok || rule.Points == 0 {
+		return 0, nil
+	}
+
+	var (
+		seen                        bool
+		earnedToday, earnedOnTarget int
+		err                         error
+	)
+	if rule.Points > 0 {
+		if rule.OneTime {
+			if seen, err = repo.HasReasonOnce(ctx, agentID, reason); err != nil {
+				return 0, err
+			}
+		} else {
+			if rule.DailyCap > 0 {
+				if earnedToday, err = repo.SumTodayPointsByAgentAndReason(ctx, agentID, reason, capReversals[reason]); err != nil {
+					return 0, err
+				}
+				earnedToday = max(earnedToday, 0)
+			}
+			if rule.PerTargetCap > 0 && relatedEntityID != nil {
+				if earnedOnTarget, err = repo.SumPointsByAgentReasonAndEntity(ctx, agentID, reason, *relatedEntityID); err != nil {
+					return 0, err
+				}
+			}
 		}
 	}
 
+	return s.applyPoints(ctx, repo, agentID, rule.Earned(seen, earnedToday, earnedOnTarget), reason, relatedEntityID)
+}
+
+// applyPoints 调整积分并按实际生效的变动写日志：扣减受余额下限截断时只记录截断后的金额，
+// 调用方据此冲正（撤销投票、恢复内容）不会返还多于实际扣除的积分
+func (s *PointsService) applyPoints(ctx context.Context, repo *repository.PointsRepository, agentID int64, points int, reason string, relatedEntityID *int64) (int, error) {
 	if points == 0 {
-		return nil
+		return 0, nil
 	}
-
-	if err := s.repo.AddAgentPoints(ctx, agentID, points); err != nil {
-		return err
+	applied, err := repo.ApplyAgentPoints(ctx, agentID, points)
+	if err != nil || applied == 0 {
+		return 0, err
 	}
-	return s.repo.CreateLog(ctx, &model.PointsLog{
+	err = repo.CreateLog(ctx, &model.PointsLog{
 		AgentID:         agentID,
-		PointsChange:    points,
+		PointsChange:    applied,
 		Reason:          reason,
 		RelatedEntityID: relatedEntityID,
 	})
-}
-
-// rule 返回 (积分变动, 是否一次性, 每日上限，正数才检查)
-func (s *PointsService) rule(reason string) (points int, oneTime bool, dailyCap int) {
-	switch reason {
-	case model.PointsReasonAgentRegistered:
-		return 100, true, 0
-	case model.PointsReasonProfileCompleted:
-		return 50, true, 0
-	case model.PointsReasonPostCreated:
-		return 10, false, DailyCapPostCreated
-	case model.PointsReasonCommentCreated:
-		return 5, false, DailyCapCommentCreated
-	case model.PointsReasonContentUpvoted:
-		return 1, false, DailyCapContentUpvoted
-	case model.PointsReasonDailyLogin:
-		return 5, false, DailyCapDailyLogin
-	case model.PointsReasonContentDownvoted:
-		return -1, false, 0
-	case model.PointsReasonContentDeletedByAdmin:
-		return -20, false, 0
-	default:
-		return 0, false, 0
-	}
+	return applied, err
 }
 
 func (s *PointsService) Health(ctx context.Context) error {
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"agent-hub/internal/model"
+)
+
+// Rule 积分规则：Points 为每次变动的积分；DailyCap、PerTargetCap 只约束正向积分，0 表示不限
+type Rule struct {
+	ID            int64 // 0 表示内置缺省规则
+	Reason        string
+	Points        int
+	OneTime       bool
+	DailyCap      int
+	PerTargetCap  int
+	EffectiveFrom time.Time // 零值表示一直有效
+}
+
+// DefaultRules 内置缺省规则，规则表中没有对应版本时使用
+func DefaultRules() []Rule {
+	return []Rule{
+		{Reason: model.PointsReasonAgentRegistered, Points: 100, OneTime: true},
+		{Reason: model.PointsReasonProfileCompleted, Points: 50, OneTime: true},
+		{Reason: model.PointsReasonFoundingAgent, Points: 100, OneTime: true},
+		{Reason: model.PointsReasonPostCreated, Points: 10, DailyCap: DailyCapPostCreated},
+		{Reason: model.PointsReasonCommentCreated, Points: 5, DailyCap: DailyCapCommentCreated},
+		{Reason: model.PointsReasonContentUpvoted, Points: 1, DailyCap: DailyCapContentUpvoted},
+		{Reason: model.PointsReasonDailyLogin, Points: 5, DailyCap: DailyCapDailyLogin},
+		{Reason: model.PointsReasonContentDownvoted, Points: -1},
+		{Reason: model.PointsReasonContentDeletedByAdmin, Points: -20},
+	}
+}
+
+// RuleFromModel 将规则表记录转换为 Rule
+func RuleFromModel(m *model.PointsRule) Rule {
+	return Rule{
+		ID:            m.ID,
+		Reason:        m.Reason,
+		Points:        m.Points,
+		OneTime:       m.OneTime,
+		DailyCap:      m.DailyCap,
+		PerTargetCap:  m.PerTargetCap,
+		EffectiveFrom: m.EffectiveFrom,
+	}
+}
+
+// Ruleset 按 reason 分组、按生效时间排序的规则集；创建后只读，可在多个 goroutine 间共享
+type Ruleset struct {
+	byReason map[string][]Rule
+}
+
+// NewRuleset 创建规则集；同一 reason 生效时间相同的多条规则，后传入的优先
+func NewRuleset(rules []Rule) *Ruleset {
+	rs := &Ruleset{byReason: make(map[string][]Rule)}
+	for _, r := range rules {
+		rs.byReason[r.Reason] = append(rs.byReason[r.Reason], r)
+	}
+	for _, versions := range rs.byReason {
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].EffectiveFrom.Before(versions[j].EffectiveFrom)
+		})
+	}
+	return rs
+}
+
+// Lookup 返回 at 时刻 reason 生效的规则
+func (rs *Ruleset) Lookup(reason string, at time.Time) (Rule, bool) {
+	var found Rule
+	ok := false
+	for _, r := range rs.byReason[reason] {
+		if r.EffectiveFrom.After(at) {
+			break
+		}
+		found, ok = r, true
+	}
+	return found, ok
+}
+
+// Rules 返回全部规则版本，按 reason、生效时间排序
+func (rs *Ruleset) Rules() []Rule {
+	reasons := rs.reasons()
+	var out []Rule
+	for _, reason := range reasons {
+		out = append(out, rs.byReason[reason]...)
+	}
+	return out
+}
+
+// Active 返回 at 时刻各 reason 生效的规则，按 reason 排序
+func (rs *Ruleset) Active(at time.Time) []Rule {
+	var out []Rule
+	for _, reason := range rs.reasons() {
+		if r, ok := rs.Lookup(reason, at); ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (rs *Ruleset) reasons() []string {
+	reasons := make([]string, 0, len(rs.byReason))
+	for reason := range rs.byReason {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// Earned 按规则计算本次实际生效的积分：seen 为此前是否已获得过（一次性规则用），
+// earnedToday、earnedOnTarget 为当日与同一关联对象上已获得的积分
+func (r Rule) Earned(seen bool, earnedToday, earnedOnTarget int) int {
+	points := r.Points
+	if points <= 0 {
+		return points
+	}
+	if r.OneTime {
+		if seen {
+			return 0
+		}
+		return points
+	}
+	if r.DailyCap > 0 {
+		points = min(points, r.DailyCap-earnedToday)
+	}
+	if r.PerTargetCap > 0 {
+		points = min(points, r.PerTargetCap-earnedOnTarget)
+	}
+	return max(points, 0)
+}
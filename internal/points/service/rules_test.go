@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+)
+
+func TestRulesetLookupPicksLatestEffectiveVersion(t *testing.T) {
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	rs := NewRuleset(append(DefaultRules(),
+		Rule{ID: 2, Reason: model.PointsReasonPostCreated, Points: 30, EffectiveFrom: feb},
+		Rule{ID: 1, Reason: model.PointsReasonPostCreated, Points: 20, EffectiveFrom: jan},
+		Rule{ID: 3, Reason: model.PointsReasonPostCreated, Points: 25, EffectiveFrom: feb},
+	))
+
+	cases := []struct {
+		at     time.Time
+		wantID int64
+		points int
+	}{
+		{jan.Add(-time.Hour), 0, 10}, // 缺省规则
+		{jan, 1, 20},
+		{feb.Add(time.Hour), 3, 25}, // 生效时间相同，后加入的优先
+	}
+	for _, c := range cases {
+		r, ok := rs.Lookup(model.PointsReasonPostCreated, c.at)
+		if !ok || r.ID != c.wantID || r.Points != c.points {
+			t.Fatalf("lookup at %v = %+v, want id %d points %d", c.at, r, c.wantID, c.points)
+		}
+	}
+	if _, ok := rs.Lookup("unknown", feb); ok {
+		t.Fatal("unknown reason should have no rule")
+	}
+	if active := rs.Active(feb); len(active) != len(DefaultRules()) {
+		t.Fatalf("active rules = %d, want one per reason", len(active))
+	}
+}
+
+func TestRuleEarnedAppliesCaps(t *testing.T) {
+	cases := []struct {
+		name                        string
+		rule                        Rule
+		seen                        bool
+		earnedToday, earnedOnTarget int
+		want                        int
+	}{
+		{"one time first", Rule{Points: 100, OneTime: true}, false, 0, 0, 100},
+		{"one time again", Rule{Points: 100, OneTime: true}, true, 0, 0, 0},
+		{"under daily cap", Rule{Points: 10, DailyCap: 50}, true, 30, 0, 10},
+		{"partial daily cap", Rule{Points: 10, DailyCap: 50}, true, 45, 0, 5},
+		{"daily cap reached", Rule{Points: 10, DailyCap: 50}, true, 50, 0, 0},
+		{"per target cap", Rule{Points: 3, DailyCap: 100, PerTargetCap: 5}, true, 0, 4, 1},
+		{"negative ignores caps", Rule{Points: -1, DailyCap: 1}, true, 10, 10, -1},
+	}
+	for _, c := range cases {
+		if got := c.rule.Earned(c.seen, c.earnedToday, c.earnedOnTarget); got != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestReplayRecomputesConfigurableReasons(t *testing.T) {
+	day := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	post := int64(7)
+	logs := []*model.PointsLog{
+		{Reason: model.PointsReasonAgentRegistered, PointsChange: 100, CreatedAt: day},
+		{Reason: model.PointsReasonPostCreated, PointsChange: 10, CreatedAt: day.Add(time.Minute)},
+		{Reason: model.PointsReasonContentUpvoted, PointsChange: 1, RelatedEntityID: &post, CreatedAt: day.Add(2 * time.Minute)},
+		{Reason: model.PointsReasonContentUpvoted, PointsChange: 1, RelatedEntityID: &post, CreatedAt: day.Add(3 * time.Minute)},
+		{Reason: model.PointsReasonVoteReversed, PointsChange: -1, RelatedEntityID: &post, CreatedAt: day.Add(4 * time.Minute)},
+		{Reason: model.PointsReasonPostCreated, PointsChange: 10, CreatedAt: day.Add(24 * time.Hour)},
+	}
+
+	current := NewRuleset(DefaultRules())
+	if got := replay(current, logs); got != 121 {
+		t.Fatalf("baseline replay = %d, want 121", got)
+	}
+
+	// 发帖 20 分但每日上限 20；同一帖子被赞最多 1 分；冲正日志沿用原始金额
+	proposed := NewRuleset(append(DefaultRules(),
+		Rule{Reason: model.PointsReasonPostCreated, Points: 20, DailyCap: 20},
+		Rule{Reason: model.PointsReasonContentUpvoted, Points: 1, PerTargetCap: 1},
+	))
+	if got := replay(proposed, logs); got != 140 {
+		t.Fatalf("proposed replay = %d, want 140", got)
+	}
+
+	// 清零日志将余额归零，之后的变动继续累加
+	reset := append(logs[:2:2], &model.PointsLog{Reason: model.PointsReasonPointsReset, PointsChange: -110, CreatedAt: day.Add(time.Hour)},
+		&model.PointsLog{Reason: model.PointsReasonPostCreated, PointsChange: 10, CreatedAt: day.Add(2 * time.Hour)})
+	if got := replay(proposed, reset); got != 0 {
+		t.Fatalf("replay after reset = %d, want 0 (daily cap used up before reset)", got)
+	}
+	if got := replay(current, reset); got != 10 {
+		t.Fatalf("baseline replay after reset = %d, want 10", got)
+	}
+}
+
+func TestReplayVoteReversalRestoresDailyCap(t *testing.T) {
+	day := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	post := int64(7)
+	// 同一投票者反复赞同/撤销后，另一投票者的赞同仍计分
+	var logs []*model.PointsLog
+	for i := 0; i < 3; i++ {
+		logs = append(logs,
+			&model.PointsLog{Reason: model.PointsReasonContentUpvoted, PointsChange: 1, RelatedEntityID: &post, CreatedAt: day.Add(time.Duration(2*i) * time.Minute)},
+			&model.PointsLog{Reason: model.PointsReasonVoteReversed, PointsChange: -1, RelatedEntityID: &post, CreatedAt: day.Add(time.Duration(2*i+1) * time.Minute)},
+		)
+	}
+	logs = append(logs, &model.PointsLog{Reason: model.PointsReasonContentUpvoted, PointsChange: 1, RelatedEntityID: &post, CreatedAt: day.Add(time.Hour)})
+
+	capped := NewRuleset(append(DefaultRules(), Rule{Reason: model.PointsReasonContentUpvoted, Points: 1, DailyCap: 2}))
+	if got := replay(capped, logs); got != 1 {
+		t.Fatalf("replay = %d, want 1", got)
+	}
+}
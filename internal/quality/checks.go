@@ -0,0 +1,121 @@
+package quality
+
+import "context"
+
+// 检测阈值缺省值
+const (
+	defaultMinRunes         = 20  // 归一化后不足该长度的内容不做重复片段与信息熵检测
+	defaultMinDistinctRatio = 0.5 // 不同片段占比低于该值视为大量重复
+	defaultRepetitionWindow = 200 // 重复片段按固定长度窗口统计，占比不随全文长度下降
+	defaultMinEntropy       = 2.5 // 比特/字符，正常中英文远高于该值
+	defaultDupMinRunes      = 10  // 归一化后不足该长度的内容不做查重
+	defaultDupMaxDistance   = 6   // SimHash 汉明距离不超过该值视为近似重复（64 位指纹，无关文本平均相差约 32 位）
+)
+
+// RepetitionCheck 重复片段检测：如「哈哈哈哈」「好帖好帖好帖」，命中时不发放积分
+type RepetitionCheck struct {
+	MinRunes         int
+	MinDistinctRatio float64
+	Window           int
+}
+
+// NewRepetitionCheck 使用缺省阈值创建重复片段检测
+func NewRepetitionCheck() *RepetitionCheck {
+	return &RepetitionCheck{MinRunes: defaultMinRunes, MinDistinctRatio: defaultMinDistinctRatio, Window: defaultRepetitionWindow}
+}
+
+// Check 将文本切成定长窗口（末尾窗口向前对齐补满），取各窗口不同片段占比的平均值
+func (c *RepetitionCheck) Check(_ context.Context, s Sample) (Verdict, error) {
+	runes := normalize(s.Text)
+	if len(runes) < c.MinRunes {
+		return Verdict{}, nil
+	}
+	window := c.Window
+	if window <= 0 || window > len(runes) {
+		window = len(runes)
+	}
+	var sum float64
+	var windows int
+	for start := 0; start < len(runes); start += window {
+		if start+window > len(runes) {
+			start = len(runes) - window
+		}
+		sum += distinctRatio(runes[start : start+window])
+		windows++
+	}
+	if sum/float64(windows) < c.MinDistinctRatio {
+		return Verdict{Action: ActionWithhold, Reason: ReasonRepetitive}, nil
+	}
+	return Verdict{}, nil
+}
+
+// distinctRatio 不同片段占全部片段的比例
+func distinctRatio(runes []rune) float64 {
+	all := shingles(runes)
+	distinct := make(map[string]struct{}, len(all))
+	for _, sh := range all {
+		distinct[sh] = struct{}{}
+	}
+	return float64(len(distinct)) / float64(len(all))
+}
+
+// EntropyCheck 信息熵检测：字符种类极少的内容（如乱敲的键盘字符）视为无意义，命中时不发放积分
+type EntropyCheck struct {
+	MinRunes   int
+	MinEntropy float64
+}
+
+// NewEntropyCheck 使用缺省阈值创建信息熵检测
+func NewEntropyCheck() *EntropyCheck {
+	return &EntropyCheck{MinRunes: defaultMinRunes, MinEntropy: defaultMinEntropy}
+}
+
+// Check 计算归一化文本的字符熵
+func (c *EntropyCheck) Check(_ context.Context, s Sample) (Verdict, error) {
+	runes := normalize(s.Text)
+	if len(runes) < c.MinRunes {
+		return Verdict{}, nil
+	}
+	if entropy(runes) < c.MinEntropy {
+		return Verdict{Action: ActionWithhold, Reason: ReasonLowEntropy}, nil
+	}
+	return Verdict{}, nil
+}
+
+// History 查询 Agent 近期发布的内容文本，excludePostID 大于 0 时不含该帖子
+type History interface {
+	RecentTexts(ctx context.Context, agentID, excludePostID int64) ([]string, error)
+}
+
+// DuplicateCheck 近似重复检测：与作者近期帖子、评论的 SimHash 指纹比较，命中时不发放积分并送审
+type DuplicateCheck struct {
+	History     History
+	MinRunes    int
+	MaxDistance int
+}
+
+// NewDuplicateCheck 使用缺省阈值创建近似重复检测
+func NewDuplicateCheck(history History) *DuplicateCheck {
+	return &DuplicateCheck{History: history, MinRunes: defaultDupMinRunes, MaxDistance: defaultDupMaxDistance}
+}
+
+// Check 逐条比较近期内容的指纹
+func (c *DuplicateCheck) Check(ctx context.Context, s Sample) (Verdict, error) {
+	if len(normalize(s.Text)) < c.MinRunes {
+		return Verdict{}, nil
+	}
+	texts, err := c.History.RecentTexts(ctx, s.AgentID, s.PostID)
+	if err != nil {
+		return Verdict{}, err
+	}
+	fp := Simhash(s.Text)
+	for _, t := range texts {
+		if len(normalize(t)) < c.MinRunes {
+			continue
+		}
+		if HammingDistance(fp, Simhash(t)) <= c.MaxDistance {
+			return Verdict{Action: ActionFlag, Reason: ReasonDuplicate}, nil
+		}
+	}
+	return Verdict{}, nil
+}
@@ -0,0 +1,83 @@
+package quality
+
+import "context"
+
+// Action 检测结论对应的处理方式，数值越大越严重
+type Action int
+
+const (
+	ActionAward    Action = iota // 正常发放积分
+	ActionWithhold               // 内容保留，不发放积分
+	ActionFlag                   // 不发放积分，并送入审核队列
+)
+
+// 命中原因
+const (
+	ReasonDuplicate  = "duplicate"   // 与近期内容重复或近似重复
+	ReasonRepetitive = "repetitive"  // 大量重复片段
+	ReasonLowEntropy = "low_entropy" // 字符种类过少，无实际意义
+)
+
+// Sample 待检测的内容：AgentID 为作者，Text 为帖子标题与正文或评论内容；
+// 编辑帖子时 PostID 为该帖子，查重时排除其自身
+type Sample struct {
+	AgentID int64
+	PostID  int64
+	Text    string
+}
+
+// Verdict 检测结论，零值表示通过
+type Verdict struct {
+	Action Action
+	Reason string
+}
+
+// Awards 是否发放积分
+func (v Verdict) Awards() bool {
+	return v.Action == ActionAward
+}
+
+// Check 单项质量检测
+type Check interface {
+	Check(ctx context.Context, s Sample) (Verdict, error)
+}
+
+// Pipeline 依次执行多项检测，取最严重的结论；本身也是一个 Check，可嵌套组合
+type Pipeline struct {
+	checks []Check
+}
+
+// NewPipeline 由若干检测组成流水线，nil 检测会被忽略
+func NewPipeline(checks ...Check) *Pipeline {
+	p := &Pipeline{}
+	for _, c := range checks {
+		if c != nil {
+			p.checks = append(p.checks, c)
+		}
+	}
+	return p
+}
+
+// NewDefaultPipeline 默认流水线：重复片段、信息熵与近似重复检测，history 为 nil 时不做查重
+func NewDefaultPipeline(history History) *Pipeline {
+	p := NewPipeline(NewRepetitionCheck(), NewEntropyCheck())
+	if history != nil {
+		p.checks = append(p.checks, NewDuplicateCheck(history))
+	}
+	return p
+}
+
+// Check 执行全部检测；任一检测出错时返回错误
+func (p *Pipeline) Check(ctx context.Context, s Sample) (Verdict, error) {
+	var worst Verdict
+	for _, c := range p.checks {
+		v, err := c.Check(ctx, s)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if v.Action > worst.Action {
+			worst = v
+		}
+	}
+	return worst, nil
+}
@@ -0,0 +1,132 @@
+package quality
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeHistory agentID -> 近期内容
+type fakeHistory map[int64][]string
+
+func (f fakeHistory) RecentTexts(_ context.Context, agentID, _ int64) ([]string, error) {
+	return f[agentID], nil
+}
+
+type failingHistory struct{}
+
+func (failingHistory) RecentTexts(context.Context, int64, int64) ([]string, error) {
+	return nil, errors.New("db down")
+}
+
+const original = "Ranking agents by karma alone rewards volume over insight. " +
+	"I think we should weigh recent upvotes more heavily and decay old points over a few weeks."
+
+func TestSimhashNearDuplicates(t *testing.T) {
+	edited := "ranking agents by karma alone rewards volume over insight!! " +
+		"I think we should weigh recent upvotes more heavily and decay old points over a few months."
+	unrelated := "Has anyone benchmarked the new vector index? Queries got slower after the upgrade for me."
+
+	if d := HammingDistance(Simhash(original), Simhash(original+"  ")); d != 0 {
+		t.Fatalf("whitespace-only change distance=%d, want 0", d)
+	}
+	if d := HammingDistance(Simhash(original), Simhash(edited)); d > defaultDupMaxDistance {
+		t.Fatalf("near-duplicate distance=%d, want <= %d", d, defaultDupMaxDistance)
+	}
+	if d := HammingDistance(Simhash(original), Simhash(unrelated)); d <= defaultDupMaxDistance {
+		t.Fatalf("unrelated distance=%d, want > %d", d, defaultDupMaxDistance)
+	}
+}
+
+func TestDefaultPipeline(t *testing.T) {
+	p := NewDefaultPipeline(fakeHistory{1: {original}})
+	cases := []struct {
+		name   string
+		sample Sample
+		want   Verdict
+	}{
+		{"normal post", Sample{AgentID: 2, Text: original}, Verdict{}},
+		{"short text is not judged", Sample{AgentID: 1, Text: "hahahaha"}, Verdict{}},
+		{"chinese text passes", Sample{AgentID: 1, Text: "我觉得积分规则应该更看重最近获得的点赞，旧积分可以在几周内逐步衰减。"}, Verdict{}},
+		{"repeated phrase", Sample{AgentID: 1, Text: "great post great post great post great post"}, Verdict{ActionWithhold, ReasonRepetitive}},
+		{"repeated chinese", Sample{AgentID: 1, Text: "哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈哈"}, Verdict{ActionWithhold, ReasonRepetitive}},
+		{"keyboard mash", Sample{AgentID: 1, Text: "asdf sdfa dfas fasd adsf sadf fdsa afds"}, Verdict{ActionWithhold, ReasonLowEntropy}},
+		{"duplicate of own post", Sample{AgentID: 1, Text: "RANKING agents by karma alone rewards volume over insight. " +
+			"I think we should weigh recent upvotes more heavily and decay old points over a few weeks!"}, Verdict{ActionFlag, ReasonDuplicate}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.Check(context.Background(), tc.sample)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("verdict=%+v, want %+v", got, tc.want)
+			}
+			if got.Awards() != (tc.want.Action == ActionAward) {
+				t.Fatalf("Awards()=%v for %+v", got.Awards(), got)
+			}
+		})
+	}
+}
+
+func TestRepetitionCheckLongPost(t *testing.T) {
+	long := strings.Join([]string{
+		"Ranking agents by karma alone rewards volume over insight, so the board fills with whoever posts most often.",
+		"Weighing recent upvotes more heavily would let newcomers with good ideas climb without years of history behind them.",
+		"Old points could decay over a few weeks, which also discourages farming a single viral thread for months.",
+		"Moderators have asked for a way to pin discussions, and the current sort makes pinned threads sink quickly.",
+		"We measured the hot algorithm against real traffic and found that comment counts matter less than expected.",
+		"Another idea is to separate reputation earned from comments and from posts, since the effort involved differs.",
+		"Search relevance also suffers when titles are vague, so a short guide on writing titles might help everyone.",
+		"Finally, the API could expose the decay schedule so that agents can plan when to publish longer write-ups.",
+		"Some of you pointed out that daily activity bonuses already favour agents that run on a fixed schedule.",
+		"That is fair, although removing them entirely would hurt smaller communities where every visit counts.",
+		"A middle ground is capping the bonus per week and spending the difference on reviewer rewards instead.",
+		"Reviewers currently get nothing for clearing the report queue, which explains why it grows every weekend.",
+		"If we pay them in points, we must also audit dismissals so nobody clears reports without reading them.",
+		"Spam detection deserves its own thread, but near-duplicate checks caught most copy-paste rings last month.",
+		"Longer essays like this one should never be penalised for length, otherwise thoughtful writing disappears.",
+		"Quiet weeks skew every metric, so compare at least a month of data rather than a single snapshot.",
+		"I will collect the replies into a table and propose concrete weights once enough communities respond.",
+		"Before anyone asks, yes, the proposal keeps existing badges; only the leaderboard formula would change.",
+		"Badges track milestones while the board tracks momentum, and mixing those two goals caused the confusion.",
+		"Several agents wondered whether downvotes should decay at the same speed, which I honestly doubt.",
+		"Harsh votes usually reflect a factual error, and factual errors do not become correct after a fortnight.",
+		"On the technical side, recomputing scores hourly is cheap because only recent posts change position.",
+		"Archived threads keep their final score, so the nightly job can skip them and finish within minutes.",
+		"Caching the top hundred entries per community covers nearly every request the frontend makes today.",
+		"Rare deep pages can fall back to the database, accepting a slower response for unusual queries.",
+		"Privacy matters too: exposing per-agent vote history would let people reverse engineer private grudges.",
+		"Aggregates per day are enough for analysis and reveal nothing about who voted on which comment.",
+		"Translation quality varies widely, so multilingual communities may need a separate quality threshold.",
+		"Chinese posts pack more meaning per character, which makes length-based heuristics especially unfair.",
+		"Accessibility reviewers mentioned that screen readers stumble over long lists of emoji reactions.",
+		"Replacing reactions with a compact summary line would help them and shrink the payload as a bonus.",
+		"Mobile clients often retry uploads on flaky networks, producing accidental double posts now and then.",
+		"Idempotency keys on the create endpoint would fix that without requiring any change from moderators.",
+		"Please reply with numbers from your own communities so we can compare before changing any defaults.",
+	}, " ")
+	if n := len(normalize(long)); n < 2500 {
+		t.Fatalf("sample has %d letters, want a post over 2500", n)
+	}
+	c := NewRepetitionCheck()
+	if got, _ := c.Check(context.Background(), Sample{Text: long}); got != (Verdict{}) {
+		t.Fatalf("long post verdict=%+v, want pass", got)
+	}
+	spam := ""
+	for i := 0; i < 200; i++ {
+		spam += "great post "
+	}
+	if got, _ := c.Check(context.Background(), Sample{Text: spam}); got.Reason != ReasonRepetitive {
+		t.Fatalf("long spam verdict=%+v, want repetitive", got)
+	}
+}
+
+func TestPipelineHistoryError(t *testing.T) {
+	p := NewDefaultPipeline(failingHistory{})
+	if _, err := p.Check(context.Background(), Sample{AgentID: 1, Text: original}); err == nil {
+		t.Fatal("expected history error")
+	}
+}
@@ -0,0 +1,85 @@
+package quality
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// shingleSize 切片长度（按字符），中文无分词也适用
+const shingleSize = 3
+
+// normalize 转小写并只保留字母与数字，忽略空白、标点与大小写差异
+func normalize(text string) []rune {
+	out := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// shingles 长度为 shingleSize 的滑动窗口片段；不足一个窗口时整体作为一个片段
+func shingles(runes []rune) []string {
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) < shingleSize {
+		return []string{string(runes)}
+	}
+	out := make([]string, 0, len(runes)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		out = append(out, string(runes[i:i+shingleSize]))
+	}
+	return out
+}
+
+// Simhash 64 位 SimHash 指纹，特征为归一化文本的字符切片；相似文本的指纹汉明距离小
+func Simhash(text string) uint64 {
+	var weights [64]int
+	for _, s := range shingles(normalize(text)) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(s))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	var fp uint64
+	for i, w := range weights {
+		if w > 0 {
+			fp |= 1 << uint(i)
+		}
+	}
+	return fp
+}
+
+// HammingDistance 两个指纹不同的位数
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// entropy 字符分布的香农熵（比特/字符）
+func entropy(runes []rune) float64 {
+	if len(runes) == 0 {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range runes {
+		counts[r]++
+	}
+	n := float64(len(runes))
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	contentDto "agent-hub/internal/content/dto"
+	"agent-hub/internal/ranking/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// HotHandler 热搜榜 HTTP 接口
+type HotHandler struct {
+	rankingService *service.RankingService
+}
+
+// NewHotHandler 创建热搜榜 Handler
+func NewHotHandler(rankingService *service.RankingService) *HotHandler {
+	return &HotHandler{rankingService: rankingService}
+}
+
+// List GET /api/v1/hot?time_range=hour|day|week|month|year|all&limit=&offset=
+// 数据来自排名服务定时计算的 Reddit 热度榜单，不直接扫描 posts 表
+func (h *HotHandler) List(c *gin.Context) {
+	timeRange := c.DefaultQuery("time_range", "all")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	posts, total, err := h.rankingService.GetHotPosts(c.Request.Context(), timeRange, limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get hot list failed")
+		return
+	}
+
+	items := make([]contentDto.PostResponse, len(posts))
+	for i, p := range posts {
+		items[i] = contentDto.ToPostResponse(p)
+	}
+	response.OK(c, gin.H{"posts": items, "total": total})
+}
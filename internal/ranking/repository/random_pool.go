@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RandomPool 随机信息流样本池（设计文档 5.1.3）：定时任务写入一批近期活跃帖子 ID，
+// 随机信息流从池中抽取，避免每次请求对 posts 表执行 ORDER BY RAND()
+type RandomPool interface {
+	// Replace 整体替换样本池
+	Replace(ctx context.Context, ids []int64) error
+	// Members 返回池中全部帖子 ID（顺序不保证）
+	Members(ctx context.Context) ([]int64, error)
+	// Sample 随机抽取至多 n 个不重复的帖子 ID
+	Sample(ctx context.Context, n int) ([]int64, error)
+	// Size 样本池大小
+	Size(ctx context.Context) (int64, error)
+}
+
+// randomPoolKey Redis 中样本池 Set 的 key
+const randomPoolKey = "ranking:random_pool"
+
+// RedisRandomPool 基于 Redis Set 的样本池，多实例共享
+type RedisRandomPool struct {
+	client *redis.Client
+}
+
+// NewRedisRandomPool 创建 Redis 样本池
+func NewRedisRandomPool(client *redis.Client) *RedisRandomPool {
+	return &RedisRandomPool{client: client}
+}
+
+// Replace 先写入临时 key 再 RENAME，读方不会看到半成品
+func (p *RedisRandomPool) Replace(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return p.client.Del(ctx, randomPoolKey).Err()
+	}
+	tmpKey := randomPoolKey + ":tmp"
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+	_, err := p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tmpKey)
+		pipe.SAdd(ctx, tmpKey, members...)
+		pipe.Rename(ctx, tmpKey, randomPoolKey)
+		return nil
+	})
+	return err
+}
+
+// Members 返回池中全部帖子 ID
+func (p *RedisRandomPool) Members(ctx context.Context) ([]int64, error) {
+	vals, err := p.client.SMembers(ctx, randomPoolKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseIDs(vals), nil
+}
+
+// Sample 随机抽取至多 n 个不重复的帖子 ID（SRANDMEMBER 正数 count 保证不重复）
+func (p *RedisRandomPool) Sample(ctx context.Context, n int) ([]int64, error) {
+	vals, err := p.client.SRandMemberN(ctx, randomPoolKey, int64(n)).Result()
+	if err == redis.Nil {
+		return []int64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseIDs(vals), nil
+}
+
+// Size 样本池大小
+func (p *RedisRandomPool) Size(ctx context.Context) (int64, error) {
+	return p.client.SCard(ctx, randomPoolKey).Result()
+}
+
+func parseIDs(vals []string) []int64 {
+	ids := make([]int64, 0, len(vals))
+	for _, v := range vals {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// MemoryRandomPool 进程内样本池（未配置 Redis 时使用）
+type MemoryRandomPool struct {
+	mu  sync.RWMutex
+	ids []int64
+}
+
+// NewMemoryRandomPool 创建进程内样本池
+func NewMemoryRandomPool() *MemoryRandomPool {
+	return &MemoryRandomPool{}
+}
+
+// Replace 整体替换样本池
+func (p *MemoryRandomPool) Replace(ctx context.Context, ids []int64) error {
+	pool := make([]int64, len(ids))
+	copy(pool, ids)
+	p.mu.Lock()
+	p.ids = pool
+	p.mu.Unlock()
+	return nil
+}
+
+// Members 返回池中全部帖子 ID 的副本
+func (p *MemoryRandomPool) Members(ctx context.Context) ([]int64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]int64, len(p.ids))
+	copy(out, p.ids)
+	return out, nil
+}
+
+// Sample 随机抽取至多 n 个不重复的帖子 ID（部分 Fisher-Yates 洗牌）
+func (p *MemoryRandomPool) Sample(ctx context.Context, n int) ([]int64, error) {
+	ids, _ := p.Members(ctx)
+	if n > len(ids) {
+		n = len(ids)
+	}
+	for i := 0; i < n; i++ {
+		j := i + rand.Intn(len(ids)-i)
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return ids[:n], nil
+}
+
+// Size 样本池大小
+func (p *MemoryRandomPool) Size(ctx context.Context) (int64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return int64(len(p.ids)), nil
+}
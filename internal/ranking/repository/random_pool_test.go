@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func sorted(ids []int64) []int64 {
+	out := append([]int64(nil), ids...)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func equalIDs(a, b []int64) bool {
+	a, b = sorted(a), sorted(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testRandomPool(t *testing.T, p RandomPool) {
+	t.Helper()
+	ctx := context.Background()
+
+	if n, err := p.Size(ctx); err != nil || n != 0 {
+		t.Fatalf("empty pool size=%d err=%v", n, err)
+	}
+	if ids, err := p.Sample(ctx, 5); err != nil || len(ids) != 0 {
+		t.Fatalf("empty pool sample=%v err=%v", ids, err)
+	}
+
+	if err := p.Replace(ctx, []int64{1, 2, 3, 4, 5}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if members, _ := p.Members(ctx); !equalIDs(members, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("members=%v", members)
+	}
+	sample, err := p.Sample(ctx, 3)
+	if err != nil || len(sample) != 3 {
+		t.Fatalf("sample=%v err=%v", sample, err)
+	}
+	seen := make(map[int64]bool)
+	for _, id := range sample {
+		if seen[id] || id < 1 || id > 5 {
+			t.Fatalf("sample %v has duplicates or foreign ids", sample)
+		}
+		seen[id] = true
+	}
+	if all, _ := p.Sample(ctx, 10); !equalIDs(all, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("oversized sample=%v, want whole pool", all)
+	}
+
+	// 重建整体替换，旧成员不残留
+	if err := p.Replace(ctx, []int64{7, 8}); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if members, _ := p.Members(ctx); !equalIDs(members, []int64{7, 8}) {
+		t.Fatalf("members after rebuild=%v", members)
+	}
+	if err := p.Replace(ctx, nil); err != nil {
+		t.Fatalf("replace empty: %v", err)
+	}
+	if n, _ := p.Size(ctx); n != 0 {
+		t.Fatalf("size after empty replace=%d", n)
+	}
+}
+
+func TestMemoryRandomPool(t *testing.T) {
+	testRandomPool(t, NewMemoryRandomPool())
+}
+
+func TestRedisRandomPool(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	testRandomPool(t, NewRedisRandomPool(client))
+
+	// 重建通过临时 key 完成，结束后不残留
+	if err := NewRedisRandomPool(client).Replace(context.Background(), []int64{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(randomPoolKey+":tmp") || !mr.Exists(randomPoolKey) {
+		t.Fatalf("keys after rebuild: %v", mr.Keys())
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
@@ -57,6 +59,89 @@ func (r *RankingRepository) TopPostsByNetVotes(ctx context.Context, limit int) (
 	return list, err
 }
 
+// ScoringPost 计算 Top/热搜分数所需的帖子字段
+type ScoringPost struct {
+	ID          int64
+	CommunityID int64
+	Upvotes     int
+	Downvotes   int
+	NetVotes    int
+	CreatedAt   time.Time
+}
+
+// ListPostsForScoring 查询 since 之后发布的帖子（since 为零值时查询全部），仅取计算分数所需字段
+func (r *RankingRepository) ListPostsForScoring(ctx context.Context, since time.Time) ([]ScoringPost, error) {
+	q := r.db.WithContext(ctx).Model(&model.Post{}).
+		Select("id", "community_id", "upvotes", "downvotes", "net_votes", "created_at")
+	if !since.IsZero() {
+		q = q.Where("created_at >= ?", since)
+	}
+	var list []ScoringPost
+	err := q.Find(&list).Error
+	return list, err
+}
+
+// ListTopPostsForScoring 按净票数降序取前 limit 个帖子（走 net_votes 索引），作为全时段榜单的候选
+func (r *RankingRepository) ListTopPostsForScoring(ctx context.Context, limit int) ([]ScoringPost, error) {
+	var list []ScoringPost
+	err := r.db.WithContext(ctx).Model(&model.Post{}).
+		Select("id", "community_id", "upvotes", "downvotes", "net_votes", "created_at").
+		Order("net_votes DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// sampleCandidateFactor 每类活动最多取 n 的多少倍作为抽样候选
+const sampleCandidateFactor = 5
+
+// SampleActivePostIDs 从 since 之后有活动（发布、编辑/投票、被评论）的帖子中随机抽取至多 n 个 ID，仅供样本池定时任务使用
+// 三类活动分别走 posts.created_at、posts.updated_at、comments.created_at 索引取最近的候选，去重后在内存中洗牌；
+// 窗口内没有帖子时按主键取最新的 n 篇帖子洗牌
+func (r *RankingRepository) SampleActivePostIDs(ctx context.Context, since time.Time, n int) ([]int64, error) {
+	limit := n * sampleCandidateFactor
+	var created, updated, commented []int64
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&model.Post{}).Where("created_at >= ?", since).
+		Order("created_at DESC").Limit(limit).Pluck("id", &created).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&model.Post{}).Where("updated_at >= ?", since).
+		Order("updated_at DESC").Limit(limit).Pluck("id", &updated).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&model.Comment{}).Where("created_at >= ?", since).
+		Order("created_at DESC").Limit(limit).Pluck("post_id", &commented).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]struct{}, len(created)+len(updated))
+	candidates := make([]int64, 0, len(created)+len(updated))
+	for _, list := range [][]int64{created, updated, commented} {
+		for _, id := range list {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				candidates = append(candidates, id)
+			}
+		}
+	}
+	var ids []int64
+	if len(candidates) > 0 {
+		// 评论所属帖子可能已删除，按主键过滤一次
+		if err := db.Model(&model.Post{}).Where("id IN ?", candidates).Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(ids) == 0 {
+		if err := db.Model(&model.Post{}).Order("id DESC").Limit(n).Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+	}
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids, nil
+}
+
 func (r *RankingRepository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.WithContext(ctx).DB()
 	if err != nil {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// ScoredPost 一条预计算排名记录
+type ScoredPost struct {
+	PostID int64
+	Score  float64
+}
+
+// ScoreStore 预计算排名存储，key 为榜单标识（如 top:week、hot:all），
+// 每个榜单保存按分数降序排列的帖子 ID（对应设计文档 5.1 节的 Sorted Set）
+type ScoreStore interface {
+	// Replace 整体替换某榜单，entries 需已按分数降序排列
+	Replace(ctx context.Context, key string, entries []ScoredPost) error
+	// Range 分页读取榜单中的帖子 ID；榜单尚未计算时 ok=false
+	Range(ctx context.Context, key string, offset, limit int) (ids []int64, total int64, ok bool, err error)
+}
+
+// MemoryScoreStore 进程内排名存储（单实例部署或 Redis 不可用时使用）
+type MemoryScoreStore struct {
+	mu     sync.RWMutex
+	boards map[string][]ScoredPost
+}
+
+// NewMemoryScoreStore 创建进程内排名存储
+func NewMemoryScoreStore() *MemoryScoreStore {
+	return &MemoryScoreStore{boards: make(map[string][]ScoredPost)}
+}
+
+// Replace 整体替换某榜单
+func (m *MemoryScoreStore) Replace(ctx context.Context, key string, entries []ScoredPost) error {
+	board := make([]ScoredPost, len(entries))
+	copy(board, entries)
+	m.mu.Lock()
+	m.boards[key] = board
+	m.mu.Unlock()
+	return nil
+}
+
+// Range 分页读取榜单中的帖子 ID
+func (m *MemoryScoreStore) Range(ctx context.Context, key string, offset, limit int) ([]int64, int64, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	board, ok := m.boards[key]
+	if !ok {
+		return nil, 0, false, nil
+	}
+	total := int64(len(board))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(board) {
+		return []int64{}, total, true, nil
+	}
+	end := offset + limit
+	if end > len(board) {
+		end = len(board)
+	}
+	ids := make([]int64, 0, end-offset)
+	for _, e := range board[offset:end] {
+		ids = append(ids, e.PostID)
+	}
+	return ids, total, true, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// redditHotDivisor Reddit 热度算法中时间项的除数（约 12.5 小时提升一个数量级）
+const redditHotDivisor = 45000
+
+// HackerNewsScore Top 榜分数（设计文档 5.1.1）：Score = (P - 1) / (T + 2)^G
+// netVotes 为净票数 P，ageHours 为发布至今的小时数 T，gravity 为重力因子 G
+func HackerNewsScore(netVotes int, ageHours, gravity float64) float64 {
+	if ageHours < 0 {
+		ageHours = 0
+	}
+	return float64(netVotes-1) / math.Pow(ageHours+2, gravity)
+}
+
+// RedditHotScore 热搜榜分数（设计文档 5.1.2）：Score = y * log10(z) + t / 45000
+// z = max(1, |ups - downs|)，y 为投票方向，t 为发布时间与纪元的秒数差。
+// 与 Reddit 原实现一致，方向 y 作用于票数项，使新帖在票数相同时始终排在旧帖之前
+func RedditHotScore(ups, downs int, createdAt time.Time, epoch int64) float64 {
+	s := ups - downs
+	z := math.Abs(float64(s))
+	if z < 1 {
+		z = 1
+	}
+	var y float64
+	switch {
+	case s > 0:
+		y = 1
+	case s < 0:
+		y = -1
+	}
+	t := float64(createdAt.Unix() - epoch)
+	return y*math.Log10(z) + t/redditHotDivisor
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestHackerNewsScore(t *testing.T) {
+	cases := []struct {
+		name     string
+		netVotes int
+		ageHours float64
+		gravity  float64
+		want     float64
+	}{
+		{"single vote scores zero", 1, 5, 1.8, 0},
+		{"fresh post", 11, 0, 1.8, 10 / math.Pow(2, 1.8)},
+		{"aged post", 11, 10, 1.8, 10 / math.Pow(12, 1.8)},
+		{"negative net votes", -4, 0, 1.8, -5 / math.Pow(2, 1.8)},
+		{"negative age clamped", 11, -3, 1.8, 10 / math.Pow(2, 1.8)},
+		{"gravity 1", 11, 3, 1, 10.0 / 5},
+	}
+	for _, c := range cases {
+		if got := HackerNewsScore(c.netVotes, c.ageHours, c.gravity); !approx(got, c.want) {
+			t.Errorf("%s: HackerNewsScore=%v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// 票数相同时越旧分数越低，重力越大衰减越快
+	if HackerNewsScore(50, 24, 1.8) >= HackerNewsScore(50, 2, 1.8) {
+		t.Error("older post should score lower")
+	}
+	if HackerNewsScore(50, 24, 2.5) >= HackerNewsScore(50, 24, 1.8) {
+		t.Error("higher gravity should decay faster")
+	}
+}
+
+func TestRedditHotScore(t *testing.T) {
+	const epoch = 1000
+	at := time.Unix(epoch+redditHotDivisor, 0) // 时间项恰好为 1
+	cases := []struct {
+		name       string
+		ups, downs int
+		want       float64
+	}{
+		{"positive", 101, 1, 2 + 1},
+		{"single net vote", 2, 1, 0 + 1},
+		{"balanced ignores magnitude", 50, 50, 0 + 1},
+		{"negative", 1, 11, -1 + 1},
+		{"no votes", 0, 0, 0 + 1},
+	}
+	for _, c := range cases {
+		if got := RedditHotScore(c.ups, c.downs, at, epoch); !approx(got, c.want) {
+			t.Errorf("%s: RedditHotScore=%v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// 同样票数新帖在前；早于纪元的帖子时间项为负
+	older := RedditHotScore(10, 0, at.Add(-time.Hour), epoch)
+	if newer := RedditHotScore(10, 0, at, epoch); newer <= older {
+		t.Errorf("newer=%v should outrank older=%v", newer, older)
+	}
+	if got := RedditHotScore(0, 0, time.Unix(epoch-redditHotDivisor, 0), epoch); !approx(got, -1) {
+		t.Errorf("before epoch score=%v, want -1", got)
+	}
+}
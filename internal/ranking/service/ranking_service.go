@@ -2,7 +2,17 @@ package service
 
 import (
 	"context"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"agent-hub/internal/cache"
+	"agent-hub/internal/config"
+	contentRepo "agent-hub/internal/content/repository"
 	"agent-hub/internal/model"
 	"agent-hub/internal/ranking/repository"
 )
@@ -14,29 +24,378 @@ const (
 	LeaderboardInfluence = "influence" // 影响力榜（关注者数）
 )
 
+// 预计算榜单的排序方式
+const (
+	SortTop = "top" // HN 算法
+	SortHot = "hot" // Reddit 算法
+)
+
+// TimeRanges 预计算榜单支持的时间范围（与 GET /posts 的 time_range 一致）
+var TimeRanges = []string{"hour", "day", "week", "month", "year", "all"}
+
+// largestWindow 最大的有限时间范围，每次刷新只扫描该范围内发布的帖子
+const largestWindow = "year"
+
+// 排名配置缺省值
+const (
+	defaultGravity         = 1.8
+	defaultEpoch           = 1771718400 // 2026-02-22 00:00:00 UTC
+	defaultRefreshInterval = 5 * time.Minute
+
+	defaultRandomPoolSize     = 1000
+	defaultRandomPoolWindow   = 7 * 24 * time.Hour
+	defaultRandomPoolInterval = time.Hour
+
+	defaultAllBoardSize = 1000
+)
+
+// FeedRanker 供内容服务读取预计算的 Top/热搜排名与随机样本池（避免内容服务依赖排名实现）
+type FeedRanker interface {
+	// RankedPostIDs 分页读取榜单帖子 ID，communityID 为 0 时读取全站榜单；榜单尚未计算完成时 ok=false，调用方应回退到数据库排序
+	RankedPostIDs(ctx context.Context, sortBy, timeRange string, communityID int64, limit, offset int) (ids []int64, total int64, ok bool, err error)
+	// RandomPostIDs 从随机样本池取帖子 ID；样本池为空时 ok=false
+	RandomPostIDs(ctx context.Context, seed string, limit, offset int) (ids []int64, total int64, ok bool, err error)
+}
+
 // RankingService 排行榜与热搜榜（排名服务）
 type RankingService struct {
-	repo *repository.RankingRepository
+	repo            *repository.RankingRepository
+	posts           *contentRepo.PostRepository
+	store           repository.ScoreStore
+	pool            repository.RandomPool
+	leaderboards    *cache.LeaderboardCache
+	gravity         float64
+	epoch           int64
+	refreshInterval time.Duration
+
+	poolSize     int
+	poolWindow   time.Duration
+	poolInterval time.Duration
+	allBoardSize int
+
+	// boardCommunities 已写入过社区榜单的社区（仅 Refresh 访问），社区帖子被全部删除后用于清空其榜单
+	boardCommunities map[int64]struct{}
 }
 
-// NewRankingService 创建排名服务
-func NewRankingService(repo *repository.RankingRepository) *RankingService {
-	return &RankingService{repo: repo}
+// NewRankingService 创建排名服务，posts 用于按榜单 ID 读取帖子详情，cfg 中未配置的项使用缺省值
+func NewRankingService(repo *repository.RankingRepository, posts *contentRepo.PostRepository, store repository.ScoreStore, pool repository.RandomPool, leaderboards *cache.LeaderboardCache, cfg config.RankingConfig) *RankingService {
+	s := &RankingService{
+		repo:            repo,
+		posts:           posts,
+		store:           store,
+		pool:            pool,
+		leaderboards:    leaderboards,
+		gravity:         cfg.Gravity,
+		epoch:           cfg.Epoch,
+		refreshInterval: cfg.RefreshInterval,
+		poolSize:        cfg.RandomPoolSize,
+		poolWindow:      cfg.RandomPoolWindow,
+		poolInterval:    cfg.RandomPoolInterval,
+		allBoardSize:    cfg.AllBoardSize,
+
+		boardCommunities: make(map[int64]struct{}),
+	}
+	if s.gravity <= 0 {
+		s.gravity = defaultGravity
+	}
+	if s.epoch <= 0 {
+		s.epoch = defaultEpoch
+	}
+	if s.refreshInterval <= 0 {
+		s.refreshInterval = defaultRefreshInterval
+	}
+	if s.poolSize <= 0 {
+		s.poolSize = defaultRandomPoolSize
+	}
+	if s.poolWindow <= 0 {
+		s.poolWindow = defaultRandomPoolWindow
+	}
+	if s.poolInterval <= 0 {
+		s.poolInterval = defaultRandomPoolInterval
+	}
+	if s.allBoardSize <= 0 {
+		s.allBoardSize = defaultAllBoardSize
+	}
+	return s
 }
 
-// GetLeaderboardPoints 积分榜
+// GetLeaderboardPoints 积分榜（cache-aside）
 func (s *RankingService) GetLeaderboardPoints(ctx context.Context, limit int) ([]*model.Agent, error) {
-	return s.repo.TopAgentsByPoints(ctx, limit)
+	return s.leaderboards.TopAgentsByPoints(ctx, limit)
 }
 
-// GetLeaderboardInfluence 影响力榜
+// GetLeaderboardInfluence 影响力榜（cache-aside）
 func (s *RankingService) GetLeaderboardInfluence(ctx context.Context, limit int) ([]*model.Agent, error) {
-	return s.repo.TopAgentsByFollowers(ctx, limit)
+	return s.leaderboards.TopAgentsByFollowers(ctx, limit)
 }
 
-// GetLeaderboardContent 内容榜
+// GetLeaderboardContent 内容榜（cache-aside）
 func (s *RankingService) GetLeaderboardContent(ctx context.Context, limit int) ([]*model.Post, error) {
-	return s.repo.TopPostsByNetVotes(ctx, limit)
+	return s.leaderboards.TopPostsByNetVotes(ctx, limit)
+}
+
+// GetHotPosts 热搜榜（从预计算榜单读取），榜单尚未计算完成时返回空列表
+func (s *RankingService) GetHotPosts(ctx context.Context, timeRange string, limit, offset int) ([]*model.Post, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	ids, total, _, err := s.RankedPostIDs(ctx, SortHot, timeRange, 0, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err := s.posts.ListByIDs(ctx, ids)
+	return posts, total, err
+}
+
+// RankedPostIDs 分页读取预计算榜单中的帖子 ID，communityID 为 0 时读取全站榜单
+func (s *RankingService) RankedPostIDs(ctx context.Context, sortBy, timeRange string, communityID int64, limit, offset int) ([]int64, int64, bool, error) {
+	key := boardKey(sortBy, normalizeTimeRange(timeRange))
+	if communityID > 0 {
+		key = communityBoardKey(key, communityID)
+	}
+	return s.store.Range(ctx, key, offset, limit)
+}
+
+// RandomPostIDs 从随机样本池取帖子 ID
+// seed 为空时每次独立抽样；seed 非空时按种子对样本池做稳定洗牌，同一种子配合 offset 翻页不会出现重复帖子，
+// 客户端每次点击 Shuffle 沿用种子并推进 offset，翻完整个样本池后再更换种子
+func (s *RankingService) RandomPostIDs(ctx context.Context, seed string, limit, offset int) ([]int64, int64, bool, error) {
+	if seed == "" {
+		total, err := s.pool.Size(ctx)
+		if err != nil || total == 0 {
+			return nil, 0, false, err
+		}
+		ids, err := s.pool.Sample(ctx, limit)
+		return ids, total, true, err
+	}
+
+	members, err := s.pool.Members(ctx)
+	if err != nil || len(members) == 0 {
+		return nil, 0, false, err
+	}
+	// 先排序消除 Set 返回顺序的不确定性，再用种子洗牌
+	sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+	rng := rand.New(rand.NewSource(seedValue(seed)))
+	rng.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+
+	total := int64(len(members))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(members) {
+		return []int64{}, total, true, nil
+	}
+	end := offset + limit
+	if end > len(members) {
+		end = len(members)
+	}
+	return members[offset:end], total, true, nil
+}
+
+// seedValue 将客户端传入的任意字符串种子映射为 PRNG 种子
+func seedValue(seed string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	return int64(h.Sum64())
+}
+
+// Run 启动排名定时任务：立即计算一次榜单与样本池，之后分别按 refreshInterval、poolInterval 周期刷新，ctx 取消时退出
+func (s *RankingService) Run(ctx context.Context) {
+	s.refreshAndLog(ctx)
+	s.refreshPoolAndLog(ctx)
+	rankTicker := time.NewTicker(s.refreshInterval)
+	defer rankTicker.Stop()
+	poolTicker := time.NewTicker(s.poolInterval)
+	defer poolTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-rankTicker.C:
+			s.refreshAndLog(ctx)
+		case <-poolTicker.C:
+			s.refreshPoolAndLog(ctx)
+		}
+	}
+}
+
+func (s *RankingService) refreshAndLog(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("ranking refresh: %v", err)
+	}
+}
+
+func (s *RankingService) refreshPoolAndLog(ctx context.Context) {
+	if err := s.RefreshRandomPool(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("random pool refresh: %v", err)
+	}
+}
+
+// RefreshRandomPool 从近期活跃帖子中随机抽取一批 ID 替换样本池
+func (s *RankingService) RefreshRandomPool(ctx context.Context) error {
+	ids, err := s.repo.SampleActivePostIDs(ctx, time.Now().Add(-s.poolWindow), s.poolSize)
+	if err != nil {
+		return err
+	}
+	return s.pool.Replace(ctx, ids)
+}
+
+// Refresh 重新计算各时间范围内帖子的 Top（HN）与热搜（Reddit）分数并写入排名存储
+// 除全站榜单外，同时为每个社区写入一份社区榜单（供社区信息流使用）；只扫描最近一年内发布的帖子，
+// 更早的帖子仅以全站净票数 Top N 的身份进入全时段榜单
+func (s *RankingService) Refresh(ctx context.Context) error {
+	now := time.Now()
+	recent, err := s.repo.ListPostsForScoring(ctx, timeRangeSince(largestWindow, now))
+	if err != nil {
+		return err
+	}
+	allTime, err := s.repo.ListTopPostsForScoring(ctx, s.allBoardSize)
+	if err != nil {
+		return err
+	}
+
+	boards, communities := s.buildBoards(recent, allTime, now)
+	// 此前有榜单但本次没有候选帖子的社区写入空榜单
+	for id := range s.boardCommunities {
+		if _, ok := communities[id]; ok {
+			continue
+		}
+		for _, tr := range TimeRanges {
+			boards[communityBoardKey(boardKey(SortTop, tr), id)] = nil
+			boards[communityBoardKey(boardKey(SortHot, tr), id)] = nil
+		}
+	}
+	for key, entries := range boards {
+		if err := s.replaceBoard(ctx, key, entries); err != nil {
+			return err
+		}
+	}
+	s.boardCommunities = communities
+	return nil
+}
+
+// buildBoards 计算全部榜单（已排序），返回榜单 key 到条目的映射与出现过的社区
+// 有限时间范围的榜单取自 recent；全时段榜单取 recent 与 allTime 的并集，只保留前 allBoardSize 名
+func (s *RankingService) buildBoards(recent, allTime []repository.ScoringPost, now time.Time) (map[string][]repository.ScoredPost, map[int64]struct{}) {
+	communities := make(map[int64]struct{})
+	for _, p := range recent {
+		communities[p.CommunityID] = struct{}{}
+	}
+	for _, p := range allTime {
+		communities[p.CommunityID] = struct{}{}
+	}
+
+	boards := make(map[string][]repository.ScoredPost)
+	for _, tr := range TimeRanges {
+		for _, sortBy := range []string{SortTop, SortHot} {
+			key := boardKey(sortBy, tr)
+			boards[key] = []repository.ScoredPost{}
+			for id := range communities {
+				boards[communityBoardKey(key, id)] = []repository.ScoredPost{}
+			}
+		}
+	}
+	add := func(tr string, p repository.ScoringPost) {
+		ageHours := now.Sub(p.CreatedAt).Hours()
+		t := repository.ScoredPost{PostID: p.ID, Score: HackerNewsScore(p.NetVotes, ageHours, s.gravity)}
+		h := repository.ScoredPost{PostID: p.ID, Score: RedditHotScore(p.Upvotes, p.Downvotes, p.CreatedAt, s.epoch)}
+		for _, key := range []string{boardKey(SortTop, tr), communityBoardKey(boardKey(SortTop, tr), p.CommunityID)} {
+			boards[key] = append(boards[key], t)
+		}
+		for _, key := range []string{boardKey(SortHot, tr), communityBoardKey(boardKey(SortHot, tr), p.CommunityID)} {
+			boards[key] = append(boards[key], h)
+		}
+	}
+
+	seen := make(map[int64]struct{}, len(recent))
+	for _, p := range recent {
+		seen[p.ID] = struct{}{}
+		for _, tr := range TimeRanges {
+			if since := timeRangeSince(tr, now); since.IsZero() || !p.CreatedAt.Before(since) {
+				add(tr, p)
+			}
+		}
+	}
+	for _, p := range allTime {
+		if _, ok := seen[p.ID]; !ok {
+			add("all", p)
+		}
+	}
+
+	allTop, allHot := boardKey(SortTop, "all"), boardKey(SortHot, "all")
+	for key, entries := range boards {
+		sortScored(entries)
+		if isBoardOf(key, allTop) || isBoardOf(key, allHot) {
+			if len(entries) > s.allBoardSize {
+				boards[key] = entries[:s.allBoardSize]
+			}
+		}
+	}
+	return boards, communities
+}
+
+// isBoardOf key 是否为全站榜单 base 或其社区榜单
+func isBoardOf(key, base string) bool {
+	return key == base || strings.HasPrefix(key, base+":c")
+}
+
+// replaceBoard 排序后整体替换榜单
+func (s *RankingService) replaceBoard(ctx context.Context, key string, entries []repository.ScoredPost) error {
+	if entries == nil {
+		entries = []repository.ScoredPost{}
+	}
+	sortScored(entries)
+	return s.store.Replace(ctx, key, entries)
+}
+
+// sortScored 按分数降序排列，分数相同时新帖（ID 大）在前，保证分页稳定
+func sortScored(list []repository.ScoredPost) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].PostID > list[j].PostID
+	})
+}
+
+func boardKey(sortBy, timeRange string) string {
+	return sortBy + ":" + timeRange
+}
+
+// communityBoardKey 社区榜单 key，如 top:week:c3
+func communityBoardKey(key string, communityID int64) string {
+	return key + ":c" + strconv.FormatInt(communityID, 10)
+}
+
+// normalizeTimeRange 未识别的时间范围按 all 处理
+func normalizeTimeRange(timeRange string) string {
+	for _, tr := range TimeRanges {
+		if tr == timeRange {
+			return tr
+		}
+	}
+	return "all"
+}
+
+// timeRangeSince 计算时间范围起点（与 PostRepository.List 保持一致），all 返回零值
+func timeRangeSince(timeRange string, now time.Time) time.Time {
+	switch timeRange {
+	case "hour":
+		return now.Add(-time.Hour)
+	case "day":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case "week":
+		return now.AddDate(0, 0, -7)
+	case "month":
+		return now.AddDate(0, -1, 0)
+	case "year":
+		return now.AddDate(-1, 0, 0)
+	default:
+		return time.Time{}
+	}
 }
 
 func (s *RankingService) Health(ctx context.Context) error {
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"agent-hub/internal/config"
+	"agent-hub/internal/ranking/repository"
+)
+
+func TestBoardBuildAndPagination(t *testing.T) {
+	ctx := context.Background()
+	s := NewRankingService(nil, nil, repository.NewMemoryScoreStore(), repository.NewMemoryRandomPool(), nil, config.RankingConfig{})
+
+	if _, _, ok, err := s.RankedPostIDs(ctx, SortTop, "week", 0, 10, 0); ok || err != nil {
+		t.Fatalf("uncomputed board ok=%v err=%v, want ok=false", ok, err)
+	}
+
+	// 按分数降序写入，分数相同时 ID 大的在前
+	entries := []repository.ScoredPost{{PostID: 1, Score: 0.5}, {PostID: 2, Score: 3}, {PostID: 3, Score: 0.5}, {PostID: 4, Score: -1}, {PostID: 5, Score: 2}}
+	if err := s.replaceBoard(ctx, boardKey(SortTop, "week"), entries); err != nil {
+		t.Fatal(err)
+	}
+	var all []int64
+	for offset := 0; ; offset += 2 {
+		ids, total, ok, err := s.RankedPostIDs(ctx, SortTop, "week", 0, 2, offset)
+		if err != nil || !ok || total != 5 {
+			t.Fatalf("page offset=%d total=%d ok=%v err=%v", offset, total, ok, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		all = append(all, ids...)
+	}
+	if want := []int64{2, 5, 3, 1, 4}; !reflect.DeepEqual(all, want) {
+		t.Fatalf("paged board=%v, want %v", all, want)
+	}
+
+	// 未识别的时间范围读 all 榜；社区榜单与全站榜单互不影响；空榜单视为已计算
+	if err := s.replaceBoard(ctx, boardKey(SortHot, "all"), []repository.ScoredPost{{PostID: 9, Score: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _, ok, _ := s.RankedPostIDs(ctx, SortHot, "decade", 0, 10, 0); !ok || !reflect.DeepEqual(ids, []int64{9}) {
+		t.Fatalf("fallback to all board=%v ok=%v", ids, ok)
+	}
+	if err := s.replaceBoard(ctx, communityBoardKey(boardKey(SortHot, "all"), 3), nil); err != nil {
+		t.Fatal(err)
+	}
+	if ids, total, ok, _ := s.RankedPostIDs(ctx, SortHot, "all", 3, 10, 0); !ok || total != 0 || len(ids) != 0 {
+		t.Fatalf("empty community board=%v total=%d ok=%v", ids, total, ok)
+	}
+}
+
+func TestRandomPostIDsSeedStability(t *testing.T) {
+	ctx := context.Background()
+	pool := repository.NewMemoryRandomPool()
+	s := NewRankingService(nil, nil, repository.NewMemoryScoreStore(), pool, nil, config.RankingConfig{})
+
+	// 样本池为空时 ok=false，由调用方回退到数据库
+	if _, _, ok, err := s.RandomPostIDs(ctx, "abc", 5, 0); ok || err != nil {
+		t.Fatalf("empty pool ok=%v err=%v", ok, err)
+	}
+	if _, _, ok, err := s.RandomPostIDs(ctx, "", 5, 0); ok || err != nil {
+		t.Fatalf("empty pool unseeded ok=%v err=%v", ok, err)
+	}
+
+	ids := make([]int64, 20)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	if err := pool.Replace(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+
+	page := func(seed string, offset int) []int64 {
+		got, total, ok, err := s.RandomPostIDs(ctx, seed, 7, offset)
+		if err != nil || !ok || total != 20 {
+			t.Fatalf("seed=%q offset=%d total=%d ok=%v err=%v", seed, offset, total, ok, err)
+		}
+		return got
+	}
+	// 同一种子结果稳定，且与样本池写入顺序无关
+	first := page("abc", 0)
+	if again := page("abc", 0); !reflect.DeepEqual(first, again) {
+		t.Fatalf("same seed differs: %v vs %v", first, again)
+	}
+	reversed := make([]int64, len(ids))
+	for i, id := range ids {
+		reversed[len(ids)-1-i] = id
+	}
+	_ = pool.Replace(ctx, reversed)
+	if again := page("abc", 0); !reflect.DeepEqual(first, again) {
+		t.Fatalf("seeded order depends on pool order: %v vs %v", first, again)
+	}
+
+	// 同一种子翻页不重复，覆盖整个样本池
+	seen := make(map[int64]bool)
+	for offset := 0; offset < 20; offset += 7 {
+		for _, id := range page("abc", offset) {
+			if seen[id] {
+				t.Fatalf("id %d repeated across pages", id)
+			}
+			seen[id] = true
+		}
+	}
+	if len(seen) != 20 {
+		t.Fatalf("pages covered %d ids, want 20", len(seen))
+	}
+	if got := page("abc", 20); len(got) != 0 {
+		t.Fatalf("past the end=%v", got)
+	}
+	if other := page("xyz", 0); reflect.DeepEqual(first, other) {
+		t.Fatalf("different seeds produced the same page %v", other)
+	}
+}
+
+func TestBuildBoardsWindowsAndAllTimeCap(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := NewRankingService(nil, nil, repository.NewMemoryScoreStore(), repository.NewMemoryRandomPool(), nil, config.RankingConfig{AllBoardSize: 2})
+
+	recent := []repository.ScoringPost{
+		{ID: 1, CommunityID: 1, Upvotes: 1, NetVotes: 1, CreatedAt: now.Add(-30 * time.Minute)},
+		{ID: 2, CommunityID: 2, Upvotes: 5, NetVotes: 5, CreatedAt: now.Add(-3 * 24 * time.Hour)},
+		{ID: 3, CommunityID: 1, Upvotes: 2, NetVotes: 2, CreatedAt: now.Add(-200 * 24 * time.Hour)},
+	}
+	// 一年前的高票帖只经由净票数 Top N 进入全时段榜单；与 recent 重复的帖子只计一次
+	allTime := []repository.ScoringPost{
+		{ID: 9, CommunityID: 3, Upvotes: 900, NetVotes: 900, CreatedAt: now.AddDate(-2, 0, 0)},
+		recent[1],
+	}
+	boards, communities := s.buildBoards(recent, allTime, now)
+
+	ids := func(key string) []int64 {
+		out := []int64{}
+		for _, e := range boards[key] {
+			out = append(out, e.PostID)
+		}
+		return out
+	}
+	if got := ids(boardKey(SortTop, "hour")); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("hour board=%v", got)
+	}
+	if got := ids(boardKey(SortTop, "week")); len(got) != 2 {
+		t.Fatalf("week board=%v, want posts 1 and 2", got)
+	}
+	if got := ids(boardKey(SortHot, "year")); len(got) != 3 {
+		t.Fatalf("year board=%v, want all recent posts", got)
+	}
+	if got := ids(boardKey(SortTop, "all")); len(got) != 2 {
+		t.Fatalf("all board=%v, want capped at 2", got)
+	}
+	if got := ids(communityBoardKey(boardKey(SortTop, "all"), 3)); !reflect.DeepEqual(got, []int64{9}) {
+		t.Fatalf("community 3 all board=%v", got)
+	}
+	if got, ok := boards[communityBoardKey(boardKey(SortTop, "hour"), 3)]; !ok || len(got) != 0 {
+		t.Fatalf("community 3 hour board=%v ok=%v, want empty", got, ok)
+	}
+	if len(communities) != 3 {
+		t.Fatalf("communities=%v", communities)
+	}
+}
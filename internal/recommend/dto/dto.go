@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"agent-hub/internal/model"
+	userDto "agent-hub/internal/user/dto"
+)
+
+// SimilarAgentItem 相似 Agent 列表项
+type SimilarAgentItem struct {
+	Score float64                     `json:"score"`
+	Agent userDto.AgentPublicResponse `json:"agent"`
+}
+
+// ToSimilarAgentItems 相似度结果转 API 响应（相似 Agent 已被删除的条目会被跳过）
+func ToSimilarAgentItems(list []*model.AgentSimilarity) []SimilarAgentItem {
+	items := make([]SimilarAgentItem, 0, len(list))
+	for _, s := range list {
+		if s.SimilarAgent == nil {
+			continue
+		}
+		items = append(items, SimilarAgentItem{
+			Score: s.Score,
+			Agent: userDto.ToAgentPublicResponse(s.SimilarAgent),
+		})
+	}
+	return items
+}
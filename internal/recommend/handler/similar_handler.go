@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/recommend/dto"
+	"agent-hub/internal/recommend/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// SimilarHandler 相似 Agent HTTP 接口
+type SimilarHandler struct {
+	recommendService *service.RecommendService
+}
+
+// NewSimilarHandler 创建相似 Agent Handler
+func NewSimilarHandler(recommendService *service.RecommendService) *SimilarHandler {
+	return &SimilarHandler{recommendService: recommendService}
+}
+
+// Get GET /api/v1/agents/:agent_name/similar?limit=
+func (h *SimilarHandler) Get(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	list, err := h.recommendService.SimilarAgents(c.Request.Context(), c.Param("agent_name"), limit)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get similar agents failed")
+			return
+		}
+	}
+	response.OK(c, gin.H{"items": dto.ToSimilarAgentItems(list)})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// insertBatchSize 批量写入相似度的每批行数
+const insertBatchSize = 500
+
+// SimilarityRepository 相似 Agent 数据访问层：读取计算所需的原始信号并保存计算结果
+type SimilarityRepository struct {
+	db *gorm.DB
+}
+
+// NewSimilarityRepository 创建相似 Agent 仓储
+func NewSimilarityRepository(db *gorm.DB) *SimilarityRepository {
+	return &SimilarityRepository{db: db}
+}
+
+// AgentBio Agent ID 与简介
+type AgentBio struct {
+	ID  int64
+	Bio *string
+}
+
+// VoteRow 投票信号
+type VoteRow struct {
+	AgentID    int64
+	TargetType string
+	TargetID   int64
+	VoteType   int8
+}
+
+// AgentCommunity Agent 与其活跃（发帖或订阅）的社区
+type AgentCommunity struct {
+	AgentID     int64
+	CommunityID int64
+}
+
+// ListAgentBios 查询全部 Agent 的 ID 与简介
+func (r *SimilarityRepository) ListAgentBios(ctx context.Context) ([]AgentBio, error) {
+	var rows []AgentBio
+	err := r.db.WithContext(ctx).Model(&model.Agent{}).Select("id", "bio").Order("id").Scan(&rows).Error
+	return rows, err
+}
+
+// ListFollows 查询全部关注关系
+func (r *SimilarityRepository) ListFollows(ctx context.Context) ([]model.Follow, error) {
+	var rows []model.Follow
+	err := r.db.WithContext(ctx).Model(&model.Follow{}).Select("follower_id", "following_id").Scan(&rows).Error
+	return rows, err
+}
+
+// ListVotes 查询全部投票记录
+func (r *SimilarityRepository) ListVotes(ctx context.Context) ([]VoteRow, error) {
+	var rows []VoteRow
+	err := r.db.WithContext(ctx).Model(&model.Vote{}).
+		Select("agent_id", "target_type", "target_id", "vote_type").Scan(&rows).Error
+	return rows, err
+}
+
+// ListAgentCommunities 查询 Agent 发过帖或订阅的社区（去重）
+func (r *SimilarityRepository) ListAgentCommunities(ctx context.Context) ([]AgentCommunity, error) {
+	var rows []AgentCommunity
+	err := r.db.WithContext(ctx).Raw(
+		"SELECT agent_id, community_id FROM posts WHERE deleted_at IS NULL " +
+			"UNION SELECT agent_id, community_id FROM community_subscriptions",
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// ReplaceAll 用新一轮计算结果整体替换相似度表（同一事务内先清空再写入）
+func (r *SimilarityRepository) ReplaceAll(ctx context.Context, rows []model.AgentSimilarity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.AgentSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, insertBatchSize).Error
+	})
+}
+
+// ListByAgentID 查询某 Agent 的相似 Agent，按分数降序，预加载相似 Agent 信息
+func (r *SimilarityRepository) ListByAgentID(ctx context.Context, agentID int64, limit int) ([]*model.AgentSimilarity, error) {
+	var list []*model.AgentSimilarity
+	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).
+		Preload("SimilarAgent").Order("score DESC, similar_agent_id ASC").
+		Limit(limit).Find(&list).Error
+	return list, err
+}
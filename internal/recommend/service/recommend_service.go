@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"agent-hub/internal/cache"
+	"agent-hub/internal/config"
+	"agent-hub/internal/model"
+	"agent-hub/internal/recommend/repository"
+)
+
+var ErrAgentNotFound = errors.New("agent not found")
+
+// 推荐配置缺省值
+const (
+	defaultRefreshInterval = time.Hour
+	defaultTopK            = 20
+)
+
+// RecommendService 相似 Agent 推荐（推荐服务）：定时批量计算并保存每个 Agent 的 Top-K 相似 Agent
+type RecommendService struct {
+	repo            *repository.SimilarityRepository
+	agentCache      *cache.AgentCache
+	weights         Weights
+	topK            int
+	refreshInterval time.Duration
+}
+
+// NewRecommendService 创建推荐服务，cfg 中未配置的项使用缺省值
+func NewRecommendService(repo *repository.SimilarityRepository, agentCache *cache.AgentCache, cfg config.RecommendConfig) *RecommendService {
+	s := &RecommendService{
+		repo:            repo,
+		agentCache:      agentCache,
+		weights:         DefaultWeights,
+		topK:            cfg.TopK,
+		refreshInterval: cfg.RefreshInterval,
+	}
+	if s.topK <= 0 {
+		s.topK = defaultTopK
+	}
+	if s.refreshInterval <= 0 {
+		s.refreshInterval = defaultRefreshInterval
+	}
+	return s
+}
+
+// Run 启动时计算一次，之后按 refreshInterval 定时重算，直到 ctx 取消
+func (s *RecommendService) Run(ctx context.Context) {
+	s.refreshAndLog(ctx)
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshAndLog(ctx)
+		}
+	}
+}
+
+func (s *RecommendService) refreshAndLog(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("similar agents refresh: %v", err)
+	}
+}
+
+// Refresh 读取关注、投票、社区与简介信号，重新计算并替换全部相似度结果
+func (s *RecommendService) Refresh(ctx context.Context) error {
+	signals, err := s.loadSignals(ctx)
+	if err != nil {
+		return err
+	}
+
+	neighbors := TopSimilar(signals, s.weights, s.topK)
+	rows := make([]model.AgentSimilarity, 0, len(neighbors)*s.topK)
+	for _, a := range signals {
+		for _, n := range neighbors[a.AgentID] {
+			rows = append(rows, model.AgentSimilarity{AgentID: a.AgentID, SimilarAgentID: n.AgentID, Score: n.Score})
+		}
+	}
+	return s.repo.ReplaceAll(ctx, rows)
+}
+
+// loadSignals 汇总每个 Agent 的特征，按 Agent ID 升序返回
+func (s *RecommendService) loadSignals(ctx context.Context) ([]AgentSignals, error) {
+	bios, err := s.repo.ListAgentBios(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signals := make([]AgentSignals, len(bios))
+	byID := make(map[int64]*AgentSignals, len(bios))
+	for i, b := range bios {
+		signals[i].AgentID = b.ID
+		if b.Bio != nil {
+			signals[i].BioTerms = BioTerms(*b.Bio)
+		}
+		byID[b.ID] = &signals[i]
+	}
+
+	follows, err := s.repo.ListFollows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range follows {
+		if a := byID[f.FollowingID]; a != nil {
+			a.Followers = append(a.Followers, f.FollowerID)
+		}
+	}
+
+	votes, err := s.repo.ListVotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		if a := byID[v.AgentID]; a != nil {
+			a.Votes = append(a.Votes, v.TargetType+":"+strconv.FormatInt(v.TargetID, 10)+":"+strconv.Itoa(int(v.VoteType)))
+		}
+	}
+
+	communities, err := s.repo.ListAgentCommunities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range communities {
+		if a := byID[c.AgentID]; a != nil {
+			a.Communities = append(a.Communities, c.CommunityID)
+		}
+	}
+	return signals, nil
+}
+
+// SimilarAgents 读取某 Agent 的相似 Agent（来自最近一次批量计算）
+func (s *RecommendService) SimilarAgents(ctx context.Context, agentName string, limit int) ([]*model.AgentSimilarity, error) {
+	a, err := s.agentCache.GetByNameWithUser(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAgentNotFound
+	}
+	if limit <= 0 || limit > s.topK {
+		limit = s.topK
+	}
+	return s.repo.ListByAgentID(ctx, a.ID, limit)
+}
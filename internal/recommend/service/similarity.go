@@ -0,0 +1,191 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AgentSignals 计算相似度所需的单个 Agent 特征
+type AgentSignals struct {
+	AgentID     int64
+	Followers   []int64  // 关注该 Agent 的 Agent ID
+	Votes       []string // 投票记录，形如 "post:12:1"（目标类型:目标 ID:方向）
+	Communities []int64  // 发过帖或订阅的社区 ID
+	BioTerms    []string // 简介分词结果，见 BioTerms
+}
+
+// Weights 各维度相似度的权重
+type Weights struct {
+	Followers   float64 // 关注者重合
+	Votes       float64 // 共同投票（同一目标、同一方向）
+	Communities float64 // 共同社区
+	Bio         float64 // 简介词项重合
+}
+
+// DefaultWeights 缺省权重，合计为 1，最终分数落在 [0, 1]
+var DefaultWeights = Weights{Followers: 0.35, Votes: 0.3, Communities: 0.2, Bio: 0.15}
+
+// Neighbor 相似 Agent 及其分数
+type Neighbor struct {
+	AgentID int64
+	Score   float64
+}
+
+// maxBucketSize 被过多 Agent 共享的特征（如默认社区、热门帖子）区分度低且会产生大量候选对，计算交集时跳过
+const maxBucketSize = 2000
+
+// TopSimilar 计算每个 Agent 的 Top-K 相似 Agent
+// 每个维度取 Jaccard 系数 |A∩B| / |A∪B|，按权重加总；只比较至少共享一个特征的 Agent 对（倒排索引），
+// 同分时按 Agent ID 升序，结果与输入顺序无关
+func TopSimilar(agents []AgentSignals, w Weights, k int) map[int64][]Neighbor {
+	if k <= 0 {
+		return map[int64][]Neighbor{}
+	}
+
+	dims := []struct {
+		weight float64
+		sets   []map[string]struct{}
+	}{
+		{w.Followers, make([]map[string]struct{}, len(agents))},
+		{w.Votes, make([]map[string]struct{}, len(agents))},
+		{w.Communities, make([]map[string]struct{}, len(agents))},
+		{w.Bio, make([]map[string]struct{}, len(agents))},
+	}
+	for i, a := range agents {
+		dims[0].sets[i] = int64Set(a.Followers)
+		dims[1].sets[i] = stringSet(a.Votes)
+		dims[2].sets[i] = int64Set(a.Communities)
+		dims[3].sets[i] = stringSet(a.BioTerms)
+	}
+
+	scores := make(map[[2]int]float64)
+	for _, d := range dims {
+		if d.weight <= 0 {
+			continue
+		}
+		for pair, inter := range intersections(d.sets) {
+			union := len(d.sets[pair[0]]) + len(d.sets[pair[1]]) - inter
+			scores[pair] += d.weight * float64(inter) / float64(union)
+		}
+	}
+
+	result := make(map[int64][]Neighbor, len(agents))
+	for pair, score := range scores {
+		a, b := agents[pair[0]].AgentID, agents[pair[1]].AgentID
+		if a == b || score <= 0 {
+			continue
+		}
+		result[a] = append(result[a], Neighbor{AgentID: b, Score: score})
+		result[b] = append(result[b], Neighbor{AgentID: a, Score: score})
+	}
+	for id, list := range result {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			return list[i].AgentID < list[j].AgentID
+		})
+		if len(list) > k {
+			list = list[:k]
+		}
+		result[id] = list
+	}
+	return result
+}
+
+// intersections 用倒排索引统计共享特征的 Agent 对（下标 i<j）的交集大小
+func intersections(sets []map[string]struct{}) map[[2]int]int {
+	index := make(map[string][]int)
+	for i, set := range sets {
+		for f := range set {
+			index[f] = append(index[f], i)
+		}
+	}
+	counts := make(map[[2]int]int)
+	for _, members := range index {
+		if len(members) < 2 || len(members) > maxBucketSize {
+			continue
+		}
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				i, j := members[x], members[y]
+				if i > j {
+					i, j = j, i
+				}
+				counts[[2]int{i, j}]++
+			}
+		}
+	}
+	return counts
+}
+
+// bioStopWords 简介中常见但无区分度的英文词
+var bioStopWords = map[string]struct{}{
+	"the": {}, "and": {}, "for": {}, "with": {}, "that": {}, "this": {}, "from": {},
+	"are": {}, "you": {}, "your": {}, "about": {}, "into": {}, "agent": {},
+}
+
+// BioTerms 简介分词：按非字母数字切分并转小写，去掉停用词与长度小于 3 的英文词；
+// 中文等无空格文字按单字切分后取相邻二元组
+func BioTerms(bio string) []string {
+	var terms []string
+	seen := make(map[string]struct{})
+	add := func(t string) {
+		if _, ok := seen[t]; ok {
+			return
+		}
+		seen[t] = struct{}{}
+		terms = append(terms, t)
+	}
+
+	for _, field := range strings.FieldsFunc(strings.ToLower(bio), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		var han []rune
+		var word []rune
+		flushWord := func() {
+			if len(word) >= 3 {
+				if _, stop := bioStopWords[string(word)]; !stop {
+					add(string(word))
+				}
+			}
+			word = word[:0]
+		}
+		flushHan := func() {
+			for i := 0; i+1 < len(han); i++ {
+				add(string(han[i : i+2]))
+			}
+			han = han[:0]
+		}
+		for _, r := range field {
+			if unicode.Is(unicode.Han, r) {
+				flushWord()
+				han = append(han, r)
+				continue
+			}
+			flushHan()
+			word = append(word, r)
+		}
+		flushWord()
+		flushHan()
+	}
+	return terms
+}
+
+func int64Set(ids []int64) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[strconv.FormatInt(id, 10)] = struct{}{}
+	}
+	return set
+}
+
+func stringSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, s := range items {
+		set[s] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// seededGraph 固定的小型社交图：
+// 1 与 2 被同一批 Agent 关注、投票一致、同在社区 10、简介相近；3 只与 1 共享社区；4 与任何人都不相交
+func seededGraph() []AgentSignals {
+	return []AgentSignals{
+		{AgentID: 1, Followers: []int64{5, 6, 7}, Votes: []string{"post:1:1", "post:2:-1"}, Communities: []int64{10, 11}, BioTerms: BioTerms("Rust compiler hacker")},
+		{AgentID: 2, Followers: []int64{5, 6}, Votes: []string{"post:1:1", "post:2:-1"}, Communities: []int64{10}, BioTerms: BioTerms("Rust compiler fan")},
+		{AgentID: 3, Communities: []int64{11}, BioTerms: BioTerms("gardening")},
+		{AgentID: 4, Votes: []string{"post:1:-1"}},
+	}
+}
+
+func TestTopSimilarScoresSeededGraph(t *testing.T) {
+	got := TopSimilar(seededGraph(), DefaultWeights, 5)
+
+	// 1-2：关注者 2/3，投票 2/2，社区 1/2，简介 {rust, compiler} / {rust, compiler, hacker, fan} = 2/4
+	want12 := 0.35*2/3 + 0.3*1 + 0.2*0.5 + 0.15*0.5
+	// 1-3：仅社区 11 重合，1/2
+	want13 := 0.2 * 0.5
+
+	if len(got[1]) != 2 || got[1][0].AgentID != 2 || got[1][1].AgentID != 3 {
+		t.Fatalf("neighbours of 1 = %+v, want [2 3]", got[1])
+	}
+	if math.Abs(got[1][0].Score-want12) > 1e-9 || math.Abs(got[1][1].Score-want13) > 1e-9 {
+		t.Fatalf("scores of 1 = %+v, want %.4f / %.4f", got[1], want12, want13)
+	}
+	if len(got[2]) != 1 || got[2][0].AgentID != 1 || got[2][0].Score != got[1][0].Score {
+		t.Fatalf("neighbours of 2 = %+v, want symmetric [1]", got[2])
+	}
+	if _, ok := got[4]; ok {
+		t.Fatalf("agent 4 voted the opposite way and should have no neighbours, got %+v", got[4])
+	}
+}
+
+func TestTopSimilarIsDeterministic(t *testing.T) {
+	want := TopSimilar(seededGraph(), DefaultWeights, 5)
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		agents := seededGraph()
+		rng.Shuffle(len(agents), func(i, j int) { agents[i], agents[j] = agents[j], agents[i] })
+		if got := TopSimilar(agents, DefaultWeights, 5); !reflect.DeepEqual(got, want) {
+			t.Fatalf("shuffled input changed result:\n got %+v\nwant %+v", got, want)
+		}
+	}
+}
+
+func TestTopSimilarTruncatesAndBreaksTiesByID(t *testing.T) {
+	// 9 与 1..5 各共享同一个社区，分数相同，按 ID 升序截取前 3 个
+	agents := []AgentSignals{{AgentID: 9, Communities: []int64{1}}}
+	for id := int64(5); id >= 1; id-- {
+		agents = append(agents, AgentSignals{AgentID: id, Communities: []int64{1}})
+	}
+	got := TopSimilar(agents, Weights{Communities: 1}, 3)[9]
+	if len(got) != 3 {
+		t.Fatalf("neighbours of 9 = %+v, want 3", got)
+	}
+	if ids := []int64{got[0].AgentID, got[1].AgentID, got[2].AgentID}; !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Fatalf("neighbours of 9 = %+v, want ids [1 2 3]", got)
+	}
+}
+
+func TestBioTerms(t *testing.T) {
+	got := BioTerms("The Rust agent, for RUST & Go fans! 机器学习")
+	want := []string{"rust", "fans", "机器", "器学", "学习"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BioTerms = %q, want %q", got, want)
+	}
+}
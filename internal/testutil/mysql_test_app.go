@@ -16,6 +16,8 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
 	"agent-hub/internal/config"
 	contentHandler "agent-hub/internal/content/handler"
 	contentRepo "agent-hub/internal/content/repository"
@@ -25,28 +27,38 @@ import (
 	interactionService "agent-hub/internal/interaction/service"
 	"agent-hub/internal/middleware"
 	"agent-hub/internal/model"
+	moderationHandler "agent-hub/internal/moderation/handler"
+	moderationRepo "agent-hub/internal/moderation/repository"
+	moderationService "agent-hub/internal/moderation/service"
 	notificationHandler "agent-hub/internal/notification/handler"
 	notificationRepo "agent-hub/internal/notification/repository"
 	notificationService "agent-hub/internal/notification/service"
+	pointsHandler "agent-hub/internal/points/handler"
 	pointsRepo "agent-hub/internal/points/repository"
 	pointsService "agent-hub/internal/points/service"
+	"agent-hub/internal/quality"
 	rankingHandler "agent-hub/internal/ranking/handler"
 	rankingRepo "agent-hub/internal/ranking/repository"
 	rankingService "agent-hub/internal/ranking/service"
+	recommendHandler "agent-hub/internal/recommend/handler"
+	recommendRepo "agent-hub/internal/recommend/repository"
+	recommendService "agent-hub/internal/recommend/service"
 	searchHandler "agent-hub/internal/search/handler"
 	searchRepo "agent-hub/internal/search/repository"
 	searchService "agent-hub/internal/search/service"
 	userHandler "agent-hub/internal/user/handler"
 	userRepo "agent-hub/internal/user/repository"
 	userService "agent-hub/internal/user/service"
+	"agent-hub/pkg/ratelimit"
+	"agent-hub/pkg/sensitive"
 )
 
 type MySQLTestApp struct {
-	Router      *gin.Engine
-	DB          *gorm.DB
-	DBName      string
-	JWTSecret   []byte
-	ExpireHours int
+	Router    *gin.Engine
+	DB        *gorm.DB
+	DBName    string
+	JWTSecret []byte
+	AccessTTL time.Duration
 }
 
 func NewMySQLTestApp(t *testing.T) *MySQLTestApp {
@@ -106,57 +118,115 @@ func NewMySQLTestApp(t *testing.T) *MySQLTestApp {
 	if len(jwtSecret) == 0 {
 		jwtSecret = []byte("test-secret")
 	}
-	expireHours := cfg.JWT.ExpireHours
-	if expireHours <= 0 {
-		expireHours = 168
-	}
 
 	// Repositories
 	userRepository := userRepo.NewUserRepository(db)
 	agentRepository := userRepo.NewAgentRepository(db)
+	tagRepository := userRepo.NewTagRepository(db)
 	userPointsRepo := userRepo.NewPointsRepository(db)
 	postRepository := contentRepo.NewPostRepository(db)
 	commentRepository := contentRepo.NewCommentRepository(db)
 	communityRepository := contentRepo.NewCommunityRepository(db)
 	voteRepository := interactionRepo.NewVoteRepository(db)
 	followRepository := interactionRepo.NewFollowRepository(db)
+	moderatorRepository := contentRepo.NewModeratorRepository(db)
+	subscriptionRepository := interactionRepo.NewSubscriptionRepository(db)
 	pointsRepository := pointsRepo.NewPointsRepository(db)
 	rankingRepository := rankingRepo.NewRankingRepository(db)
 	notificationRepository := notificationRepo.NewNotificationRepository(db)
 
-	// Services + Handlers
-	userSvc := userService.NewUserService(userRepository, agentRepository, userPointsRepo)
-	authHandler := userHandler.NewAuthHandler(userSvc, jwtSecret, expireHours)
-	agentHandler := userHandler.NewAgentHandler(userSvc, jwtSecret, expireHours)
+	// Cache（cache-aside，Redis 不可用时直接回源 MySQL）
+	appCache := cache.New(nil, 0)
+	agentCache := cache.NewAgentCache(appCache, agentRepository)
+	postCache := cache.NewPostCache(appCache, postRepository)
+	leaderboardCache := cache.NewLeaderboardCache(appCache, rankingRepository)
 
-	pointsSvc := pointsService.NewPointsService(pointsRepository)
+	// Services + Handlers
 	notificationSvc := notificationService.NewNotificationService(notificationRepository)
+	pointsSvc := pointsService.NewPointsService(pointsRepository, pointsRepo.NewRuleRepository(db))
+	if err := pointsSvc.Reload(context.Background()); err != nil {
+		t.Fatalf("load points rules: %v", err)
+	}
+	denylist := cache.NewDenylist(appCache)
+	sessionSvc := userService.NewSessionService(userRepo.NewRefreshTokenRepository(db), userRepository, agentRepository, denylist, jwtSecret, cfg.JWT)
+	sensitiveFilter := sensitive.NewFilter(
+		sensitive.List{Name: "reject", Action: sensitive.ActionReject, Words: []string{"scamcoin"}},
+		sensitive.List{Name: "mask", Action: sensitive.ActionMask, Words: []string{"darn"}},
+		sensitive.List{Name: "flag", Action: sensitive.ActionFlag, Words: []string{"airdrop"}},
+	)
+	reportRepository := moderationRepo.NewReportRepository(db)
+	flagger := moderationService.NewSystemFlagger(reportRepository)
+	// 测试用较小的阈值：失败 2 次后开始退避，4 次锁定
+	loginGuard := userService.NewLoginGuard(cache.NewLoginAttempts(appCache), agentRepository, notificationSvc, config.LoginConfig{BackoffAfter: 2, LockoutAfter: 4, IPLockoutAfter: 8})
+	// 测试用较小的创始窗口：按创建顺序的前 2 个 Agent
+	foundingWindow := userService.FoundingWindow{MaxAgents: 2}
+	userSvc := userService.NewUserService(userRepository, agentRepository, tagRepository, userPointsRepo, pointsSvc, foundingWindow, agentCache, leaderboardCache, sessionSvc, sensitiveFilter, flagger, loginGuard)
+	authHandler := userHandler.NewAuthHandler(userSvc, sessionSvc)
+	adminHandler := userHandler.NewAdminHandler(userSvc)
+
+	pointsHdl := pointsHandler.NewPointsHandler(pointsSvc)
+	pointsRuleHdl := pointsHandler.NewRuleHandler(pointsSvc)
+	// 测试用里程碑：连续 3 天奖励 15 分
+	activitySvc := userService.NewActivityService(agentRepository, pointsSvc, agentCache, leaderboardCache, map[int]int{3: 15})
+
+	rankingSvc := rankingService.NewRankingService(rankingRepository, postRepository, rankingRepo.NewMemoryScoreStore(), rankingRepo.NewMemoryRandomPool(), leaderboardCache, cfg.Ranking)
+	leaderboardHandler := rankingHandler.NewLeaderboardHandler(rankingSvc)
+	hotHandler := rankingHandler.NewHotHandler(rankingSvc)
 
-	contentSvc := contentService.NewContentService(postRepository, commentRepository, communityRepository, pointsSvc, notificationSvc)
+	checker := authz.NewChecker(moderatorRepository)
+	suspensionSvc := moderationService.NewSuspensionService(moderationRepo.NewSuspensionRepository(db), agentRepository)
+	qualityPipeline := quality.NewDefaultPipeline(contentService.NewRecentContent(postRepository, commentRepository))
+	contentSvc := contentService.NewContentService(postRepository, commentRepository, communityRepository, pointsSvc, notificationSvc, rankingSvc, postCache, agentCache, leaderboardCache, moderatorRepository, checker, suspensionSvc, qualityPipeline, flagger, sensitiveFilter)
 	postHandler := contentHandler.NewPostHandler(contentSvc)
 	commentHandler := contentHandler.NewCommentHandler(contentSvc)
+	communityHandler := contentHandler.NewCommunityHandler(contentSvc)
+	feedHandler := contentHandler.NewFeedHandler(contentSvc)
 
 	interactionSvc := interactionService.NewInteractionService(
-		voteRepository, followRepository,
-		postRepository, commentRepository,
+		voteRepository, followRepository, subscriptionRepository,
+		postRepository, commentRepository, communityRepository,
 		agentRepository,
 		pointsSvc,
 		notificationSvc,
+		postCache, agentCache, leaderboardCache,
+		suspensionSvc,
 	)
 	voteHandler := interactionHandler.NewVoteHandler(interactionSvc)
 	followHandler := interactionHandler.NewFollowHandler(interactionSvc)
+	agentHandler := userHandler.NewAgentHandler(userSvc, interactionSvc, suspensionSvc)
+	apiKeySvc := userService.NewAPIKeyService(userRepo.NewAPIKeyRepository(db))
+	apiKeyHandler := userHandler.NewAPIKeyHandler(apiKeySvc)
+	subscriptionHandler := interactionHandler.NewSubscriptionHandler(interactionSvc)
 
-	rankingSvc := rankingService.NewRankingService(rankingRepository)
-	leaderboardHandler := rankingHandler.NewLeaderboardHandler(rankingSvc)
+	recommendSvc := recommendService.NewRecommendService(recommendRepo.NewSimilarityRepository(db), agentCache, cfg.Recommend)
+	similarHandler := recommendHandler.NewSimilarHandler(recommendSvc)
 
 	searchRepository := searchRepo.NewSearchRepository(db)
 	searchSvc := searchService.NewSearchService(searchRepository)
 	sHandler := searchHandler.NewSearchHandler(searchSvc)
 
+	moderationSvc := moderationService.NewModerationService(
+		moderationRepo.NewRemovalRepository(db),
+		postRepository, commentRepository, communityRepository,
+		pointsSvc, notificationSvc, checker,
+		postCache, agentCache, leaderboardCache,
+	)
+	modHandler := moderationHandler.NewModerationHandler(moderationSvc)
+	reportSvc := moderationService.NewReportService(
+		reportRepository, suspensionSvc,
+		postRepository, commentRepository, communityRepository, agentRepository,
+		pointsSvc, checker, agentCache, leaderboardCache,
+	)
+	reportHandler := moderationHandler.NewReportHandler(reportSvc)
+	suspensionHandler := moderationHandler.NewSuspensionHandler(suspensionSvc)
+
 	notifHandler := notificationHandler.NewNotificationHandler(notificationSvc)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
 	r.Use(middleware.Recovery())
 	r.Use(middleware.RequestID())
 
@@ -164,48 +234,106 @@ func NewMySQLTestApp(t *testing.T) *MySQLTestApp {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// 写接口同时接受用户 JWT 与 Agent API Key，API Key 须具备对应 scope
+	authed := middleware.Auth(jwtSecret, denylist, apiKeySvc, suspensionSvc)
+	requireJWT := middleware.JWT(jwtSecret, denylist, suspensionSvc)
+	optionalJWT := middleware.OptionalJWT(jwtSecret, denylist)
+	var limiter ratelimit.Store = ratelimit.NewMemoryStore()
+	authLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("auth", cfg.RateLimit.AuthPerMinute), middleware.ByIP)
+	postLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("posts", cfg.RateLimit.PostsPerMinute), middleware.ByAgent)
+	commentLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("comments", cfg.RateLimit.CommentsPerMinute), middleware.ByAgent)
+	voteLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("votes", cfg.RateLimit.VotesPerMinute), middleware.ByAgent)
+	followLimit := middleware.RateLimit(limiter, ratelimit.PerMinute("follows", cfg.RateLimit.FollowsPerMinute), middleware.ByAgent)
+
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.TrackActivity(activitySvc))
 	{
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", authLimit, authHandler.Register)
+			auth.POST("/login", authLimit, authHandler.Login)
+			auth.POST("/refresh", authLimit, authHandler.Refresh)
+			auth.POST("/logout", requireJWT, authHandler.Logout)
 			auth.GET("/oauth/twitter", authHandler.OAuthTwitter)
 			auth.GET("/oauth/twitter/callback", authHandler.OAuthTwitterCallback)
 		}
 
-		v1.POST("/agents", middleware.JWT(jwtSecret), agentHandler.Create)
-		v1.GET("/agents/:agent_name", agentHandler.GetByName)
-		v1.PUT("/me/agent", middleware.JWT(jwtSecret), agentHandler.UpdateMe)
-
-		v1.POST("/posts", middleware.JWT(jwtSecret), postHandler.Create)
+		v1.POST("/agents", requireJWT, agentHandler.Create)
+		v1.GET("/agents/:agent_name", optionalJWT, agentHandler.GetByName)
+		v1.GET("/agents/:agent_name/followers", optionalJWT, followHandler.Followers)
+		v1.GET("/agents/:agent_name/following", optionalJWT, followHandler.Following)
+		v1.GET("/agents/:agent_name/posts", postHandler.ListByAgent)
+		v1.GET("/agents/:agent_name/comments", commentHandler.ListByAgent)
+		v1.GET("/agents/:agent_name/best", postHandler.Best)
+		v1.GET("/agents/:agent_name/similar", similarHandler.Get)
+		v1.PUT("/me/password", requireJWT, authHandler.ChangePassword)
+		v1.GET("/tags/:tag/agents", agentHandler.ListByTag)
+		v1.GET("/founding-agents", agentHandler.ListFounding)
+		v1.PUT("/me/agent", authed, middleware.RequireScope(model.ScopeAgentWrite), agentHandler.UpdateMe)
+		v1.POST("/me/agent/keys", requireJWT, apiKeyHandler.Create)
+		v1.GET("/me/agent/keys", requireJWT, apiKeyHandler.List)
+		v1.DELETE("/me/agent/keys/:key_id", requireJWT, apiKeyHandler.Revoke)
+
+		v1.POST("/communities", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), postLimit, communityHandler.Create)
+		v1.GET("/communities", communityHandler.List)
+		v1.GET("/communities/:name", communityHandler.Get)
+		v1.GET("/communities/:name/posts", communityHandler.ListPosts)
+		v1.GET("/communities/:name/moderators", communityHandler.ListModerators)
+		v1.POST("/communities/:name/moderators", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), communityHandler.AddModerator)
+		v1.DELETE("/communities/:name/moderators/:agent_name", authed, middleware.RequireScope(model.ScopeCommunitiesWrite), communityHandler.RemoveModerator)
+		v1.POST("/communities/:name/subscribe", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, subscriptionHandler.Subscribe)
+		v1.DELETE("/communities/:name/subscribe", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, subscriptionHandler.Unsubscribe)
+		v1.GET("/me/feed", authed, feedHandler.Feed)
+		v1.GET("/me/relationships", authed, followHandler.Relationships)
+		v1.POST("/posts", authed, middleware.RequireScope(model.ScopePostsWrite), postLimit, postHandler.Create)
 		v1.GET("/posts", postHandler.List)
 		v1.GET("/posts/:post_id", postHandler.Get)
-		v1.PUT("/posts/:post_id", middleware.JWT(jwtSecret), postHandler.Update)
-		v1.DELETE("/posts/:post_id", middleware.JWT(jwtSecret), postHandler.Delete)
+		v1.PUT("/posts/:post_id", authed, middleware.RequireScope(model.ScopePostsWrite), postHandler.Update)
+		v1.DELETE("/posts/:post_id", authed, middleware.RequireScope(model.ScopePostsWrite), postHandler.Delete)
 
-		v1.POST("/posts/:post_id/comments", middleware.JWT(jwtSecret), commentHandler.Create)
+		v1.POST("/posts/:post_id/comments", authed, middleware.RequireScope(model.ScopeCommentsWrite), commentLimit, commentHandler.Create)
 		v1.GET("/posts/:post_id/comments", commentHandler.List)
-		v1.DELETE("/comments/:comment_id", middleware.JWT(jwtSecret), commentHandler.Delete)
+		v1.DELETE("/comments/:comment_id", authed, middleware.RequireScope(model.ScopeCommentsWrite), commentHandler.Delete)
 
-		v1.POST("/posts/:post_id/vote", middleware.JWT(jwtSecret), voteHandler.PostVote)
-		v1.POST("/comments/:comment_id/vote", middleware.JWT(jwtSecret), voteHandler.CommentVote)
-		v1.POST("/agents/:agent_name/follow", middleware.JWT(jwtSecret), followHandler.Follow)
+		v1.POST("/posts/:post_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.PostVote)
+		v1.POST("/comments/:comment_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.CommentVote)
+		v1.DELETE("/posts/:post_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.DeletePostVote)
+		v1.DELETE("/comments/:comment_id/vote", authed, middleware.RequireScope(model.ScopeVotesWrite), voteLimit, voteHandler.DeleteCommentVote)
+		v1.POST("/agents/:agent_name/follow", authed, middleware.RequireScope(model.ScopeFollowsWrite), followLimit, followHandler.Follow)
+		v1.POST("/reports", authed, middleware.RequireScope(model.ScopeReportsWrite), reportHandler.Create)
 
 		v1.GET("/search", sHandler.Search)
 		v1.GET("/leaderboard", leaderboardHandler.Get)
-
-		v1.GET("/notifications", middleware.JWT(jwtSecret), notifHandler.List)
-		v1.PATCH("/notifications/:id/read", middleware.JWT(jwtSecret), notifHandler.MarkRead)
-		v1.POST("/notifications/read-all", middleware.JWT(jwtSecret), notifHandler.MarkAllRead)
+		v1.GET("/hot", hotHandler.List)
+
+		v1.POST("/moderation/posts/:post_id/remove", requireJWT, modHandler.RemovePost)
+		v1.POST("/moderation/comments/:comment_id/remove", requireJWT, modHandler.RemoveComment)
+		v1.POST("/moderation/removals/:removal_id/restore", requireJWT, modHandler.Restore)
+		v1.GET("/moderation/removals", requireJWT, modHandler.ListRemovals)
+		v1.GET("/moderation/reports", requireJWT, reportHandler.List)
+		v1.POST("/moderation/reports/:report_id/resolve", requireJWT, reportHandler.Resolve)
+		v1.PUT("/admin/users/:user_id/role", requireJWT, middleware.RequireRole(model.RoleOwner), adminHandler.SetRole)
+		v1.PUT("/admin/agents/:agent_name/founding", requireJWT, middleware.RequireRole(model.RoleAdmin), adminHandler.SetFounding)
+		v1.POST("/admin/agents/:agent_name/suspensions", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.Create)
+		v1.GET("/admin/agents/:agent_name/suspensions", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.List)
+		v1.DELETE("/admin/suspensions/:suspension_id", requireJWT, middleware.RequireRole(model.RoleAdmin), suspensionHandler.Lift)
+
+		v1.GET("/notifications", authed, notifHandler.List)
+		v1.PATCH("/notifications/:id/read", authed, notifHandler.MarkRead)
+		v1.POST("/notifications/read-all", authed, notifHandler.MarkAllRead)
+		v1.GET("/me/points/history", authed, pointsHdl.History)
+		v1.GET("/me/points/summary", authed, pointsHdl.Summary)
+		v1.GET("/admin/points/rules", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.List)
+		v1.POST("/admin/points/rules", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.Create)
+		v1.POST("/admin/points/rules/dry-run", requireJWT, middleware.RequireRole(model.RoleAdmin), pointsRuleHdl.DryRun)
 	}
 
 	return &MySQLTestApp{
-		Router:      r,
-		DB:          db,
-		DBName:      dbName,
-		JWTSecret:   jwtSecret,
-		ExpireHours: expireHours,
+		Router:    r,
+		DB:        db,
+		DBName:    dbName,
+		JWTSecret: jwtSecret,
+		AccessTTL: sessionSvc.AccessTTL(),
 	}
 }
 
@@ -243,6 +371,7 @@ func loadConfigForTests() (*config.Config, error) {
 
 	// 绑定环境变量（与 config.Load 保持一致）
 	_ = v.BindEnv("server.port", "SERVER_PORT")
+	_ = v.BindEnv("server.trusted_proxies", "SERVER_TRUSTED_PROXIES")
 	_ = v.BindEnv("mysql.host", "MYSQL_HOST")
 	_ = v.BindEnv("mysql.port", "MYSQL_PORT")
 	_ = v.BindEnv("mysql.user", "MYSQL_USER")
@@ -250,10 +379,22 @@ func loadConfigForTests() (*config.Config, error) {
 	_ = v.BindEnv("mysql.database", "MYSQL_DATABASE")
 	_ = v.BindEnv("redis.addr", "REDIS_ADDR")
 	_ = v.BindEnv("redis.password", "REDIS_PASSWORD")
+	_ = v.BindEnv("redis.cache_ttl", "REDIS_CACHE_TTL")
 	_ = v.BindEnv("jwt.secret", "JWT_SECRET")
-	_ = v.BindEnv("jwt.expire_hours", "JWT_EXPIRE_HOURS")
+	_ = v.BindEnv("jwt.access_ttl", "JWT_ACCESS_TTL")
+	_ = v.BindEnv("jwt.refresh_ttl", "JWT_REFRESH_TTL")
 	_ = v.BindEnv("log.level", "LOG_LEVEL")
 	_ = v.BindEnv("log.format", "LOG_FORMAT")
+	_ = v.BindEnv("ranking.gravity", "RANKING_GRAVITY")
+	_ = v.BindEnv("ranking.epoch", "RANKING_EPOCH")
+	_ = v.BindEnv("ranking.refresh_interval", "RANKING_REFRESH_INTERVAL")
+	_ = v.BindEnv("ranking.random_pool_size", "RANKING_RANDOM_POOL_SIZE")
+	_ = v.BindEnv("ranking.random_pool_window", "RANKING_RANDOM_POOL_WINDOW")
+	_ = v.BindEnv("ranking.random_pool_interval", "RANKING_RANDOM_POOL_INTERVAL")
+	_ = v.BindEnv("ranking.all_board_size", "RANKING_ALL_BOARD_SIZE")
+	_ = v.BindEnv("recommend.refresh_interval", "RECOMMEND_REFRESH_INTERVAL")
+	_ = v.BindEnv("recommend.top_k", "RECOMMEND_TOP_K")
+	_ = v.BindEnv("rbac.owner_email", "RBAC_OWNER_EMAIL")
 
 	// 如果 repo root 有配置文件就读它；没有也不当错误（全靠 env）
 	_ = v.ReadInConfig()
@@ -1,6 +1,10 @@
 package dto
 
-import "agent-hub/internal/model"
+import (
+	"time"
+
+	"agent-hub/internal/model"
+)
 
 // AgentPublicResponse Agent 公开信息（含人类所有者，用于 GET /agents/:name）
 type AgentPublicResponse struct {
@@ -13,10 +17,41 @@ type AgentPublicResponse struct {
 	FollowingCount  int     `json:"following_count"`
 	IsVerified      bool    `json:"is_verified"`
 	IsFoundingAgent bool    `json:"is_founding_agent"`
+	CurrentStreak   int     `json:"current_streak"` // 连续活跃天数，中断后为 0
+	LongestStreak   int     `json:"longest_streak"`
+	Tags            []string `json:"tags,omitempty"` // 技能标签，仅详情返回
 	CreatedAt       string  `json:"created_at"`
 
 	// 人类所有者
 	HumanOwner *HumanOwnerResponse `json:"human_owner,omitempty"`
+
+	// 与当前访问者的关注关系，仅访问者已登录时返回
+	IsFollowing *bool `json:"is_following,omitempty"`
+	FollowsYou  *bool `json:"follows_you,omitempty"`
+
+	// 当前生效的封禁，未被封禁时不返回
+	Suspended       bool    `json:"suspended,omitempty"`
+	SuspensionScope string  `json:"suspension_scope,omitempty"`
+	SuspendedUntil  *string `json:"suspended_until,omitempty"` // 为空表示永久
+}
+
+// SetRelationship 附加与当前访问者的关注关系
+func (r *AgentPublicResponse) SetRelationship(following, followsYou bool) {
+	r.IsFollowing = &following
+	r.FollowsYou = &followsYou
+}
+
+// SetSuspension 标记当前生效的封禁，m 为 nil 时不做修改
+func (r *AgentPublicResponse) SetSuspension(m *model.AgentSuspension) {
+	if m == nil {
+		return
+	}
+	r.Suspended = true
+	r.SuspensionScope = m.Scope
+	if m.EndsAt != nil {
+		until := m.EndsAt.Format("2006-01-02T15:04:05Z07:00")
+		r.SuspendedUntil = &until
+	}
 }
 
 // HumanOwnerResponse 人类所有者信息
@@ -38,6 +73,9 @@ func ToAgentPublicResponse(a *model.Agent) AgentPublicResponse {
 		FollowingCount:  a.FollowingCount,
 		IsVerified:      a.IsVerified,
 		IsFoundingAgent: a.IsFoundingAgent,
+		CurrentStreak:   a.StreakOn(model.ActivityDay(time.Now())),
+		LongestStreak:   a.LongestStreak,
+		Tags:            a.Tags,
 		CreatedAt:       a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 	if a.User != nil {
@@ -48,3 +86,46 @@ func ToAgentPublicResponse(a *model.Agent) AgentPublicResponse {
 	}
 	return resp
 }
+
+// APIKeyResponse API Key 信息（不含明文）
+type APIKeyResponse struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	LastUsedAt *string  `json:"last_used_at"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// ToAPIKeyResponse 将 model.APIKey 转为 API 响应
+func ToAPIKeyResponse(k *model.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.ScopeList(),
+		CreatedAt: k.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if k.LastUsedAt != nil {
+		s := k.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.LastUsedAt = &s
+	}
+	if k.RevokedAt != nil {
+		s := k.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.RevokedAt = &s
+	}
+	return resp
+}
+
+// UserRoleResponse 用户角色信息
+type UserRoleResponse struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// ToUserRoleResponse 将 model.User 转为角色响应
+func ToUserRoleResponse(u *model.User) UserRoleResponse {
+	return UserRoleResponse{UserID: u.ID, Username: u.Username, Role: u.Role}
+}
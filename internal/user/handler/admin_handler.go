@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/user/dto"
+	"agent-hub/internal/user/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// AdminHandler 平台管理 HTTP 接口（角色任免、创始 Agent）
+type AdminHandler struct {
+	userService *service.UserService
+}
+
+// NewAdminHandler 创建平台管理 Handler
+func NewAdminHandler(userService *service.UserService) *AdminHandler {
+	return &AdminHandler{userService: userService}
+}
+
+// SetRole PUT /api/v1/admin/users/:user_id/role（仅 Owner）
+func (h *AdminHandler) SetRole(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid user_id")
+		return
+	}
+
+	var in service.SetRoleInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	u, err := h.userService.SetRole(c.Request.Context(), middleware.CurrentActor(c), userID, in)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRole:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "role must be admin or member")
+			return
+		case service.ErrUserNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "User not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Cannot change the owner's role")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Set role failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToUserRoleResponse(u))
+}
+
+// SetFounding PUT /api/v1/admin/agents/:agent_name/founding（Admin）授予或撤销创始 Agent
+func (h *AdminHandler) SetFounding(c *gin.Context) {
+	var in service.SetFoundingInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	a, err := h.userService.SetFounding(c.Request.Context(), middleware.CurrentActor(c), c.Param("agent_name"), in)
+	if err != nil {
+		switch err {
+		case service.ErrAgentNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrForbidden:
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Admin role required")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Set founding agent failed")
+			return
+		}
+	}
+	response.OK(c, dto.ToAgentPublicResponse(a))
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	pkgerrors "agent-hub/pkg/errors"
@@ -9,21 +10,23 @@ import (
 	"agent-hub/internal/user/dto"
 	"agent-hub/internal/user/service"
 	"agent-hub/internal/middleware"
+	interactionService "agent-hub/internal/interaction/service"
+	moderationService "agent-hub/internal/moderation/service"
 )
 
 // AgentHandler Agent 相关 HTTP 接口
 type AgentHandler struct {
 	userService    *service.UserService
-	jwtSecret      []byte
-	jwtExpireHours int
+	relations      interactionService.RelationshipChecker
+	suspensions    moderationService.SuspensionLookup
 }
 
-// NewAgentHandler 创建 Agent Handler
-func NewAgentHandler(userService *service.UserService, jwtSecret []byte, jwtExpireHours int) *AgentHandler {
+// NewAgentHandler 创建 Agent Handler，relations 可为 nil（不返回 follows_you），suspensions 可为 nil（不返回封禁状态）
+func NewAgentHandler(userService *service.UserService, relations interactionService.RelationshipChecker, suspensions moderationService.SuspensionLookup) *AgentHandler {
 	return &AgentHandler{
 		userService:    userService,
-		jwtSecret:     jwtSecret,
-		jwtExpireHours: jwtExpireHours,
+		relations:      relations,
+		suspensions:    suspensions,
 	}
 }
 
@@ -37,7 +40,7 @@ func (h *AgentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	a, token, err := h.userService.CreateAgent(c.Request.Context(), userID, in, h.jwtSecret, h.jwtExpireHours)
+	a, token, err := h.userService.CreateAgent(c.Request.Context(), userID, middleware.GetSessionID(c), in)
 	if err != nil {
 		switch err {
 		case service.ErrAgentExists:
@@ -46,6 +49,9 @@ func (h *AgentHandler) Create(c *gin.Context) {
 		case service.ErrAgentNameTaken:
 			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Agent name already taken")
 			return
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
 		default:
 			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create agent failed")
 			return
@@ -59,6 +65,7 @@ func (h *AgentHandler) Create(c *gin.Context) {
 }
 
 // GetByName 获取 Agent 公开信息 GET /api/v1/agents/:agent_name
+// 访问者已登录时附加 is_following / follows_you
 func (h *AgentHandler) GetByName(c *gin.Context) {
 	agentName := c.Param("agent_name")
 	if agentName == "" {
@@ -76,7 +83,25 @@ func (h *AgentHandler) GetByName(c *gin.Context) {
 		return
 	}
 
-	response.OK(c, dto.ToAgentPublicResponse(a))
+	resp := dto.ToAgentPublicResponse(a)
+	if h.suspensions != nil {
+		sus, err := h.suspensions.Active(c.Request.Context(), a.ID)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get agent failed")
+			return
+		}
+		resp.SetSuspension(sus)
+	}
+	if viewerID, ok := middleware.GetAgentID(c); ok && viewerID > 0 && viewerID != a.ID && h.relations != nil {
+		rels, err := h.relations.Relationships(c.Request.Context(), viewerID, []int64{a.ID})
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Get agent failed")
+			return
+		}
+		rel := rels[a.ID]
+		resp.SetRelationship(rel.Following, rel.FollowsYou)
+	}
+	response.OK(c, resp)
 }
 
 // UpdateMe 更新当前用户的 Agent PUT /api/v1/me/agent（需认证）
@@ -91,8 +116,20 @@ func (h *AgentHandler) UpdateMe(c *gin.Context) {
 
 	a, err := h.userService.UpdateAgent(c.Request.Context(), userID, in)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Update agent failed")
-		return
+		switch err {
+		case service.ErrSensitiveContent:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeSensitiveContent, "Content contains sensitive words")
+			return
+		case service.ErrInvalidTag:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Tags must be 1-32 letters, digits or - + . #")
+			return
+		case service.ErrTooManyTags:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "Too many tags")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Update agent failed")
+			return
+		}
 	}
 	if a == nil {
 		response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
@@ -101,3 +138,43 @@ func (h *AgentHandler) UpdateMe(c *gin.Context) {
 
 	response.OK(c, dto.ToAgentPublicResponse(a))
 }
+
+// ListByTag 按技能标签浏览 Agent GET /api/v1/tags/:tag/agents?limit=&offset=，按积分降序
+func (h *AgentHandler) ListByTag(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	tag, agents, total, err := h.userService.ListAgentsByTag(c.Request.Context(), c.Param("tag"), limit, offset)
+	if err != nil {
+		if err == service.ErrTagNotFound {
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Tag not found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List agents failed")
+		return
+	}
+
+	items := make([]dto.AgentPublicResponse, len(agents))
+	for i, a := range agents {
+		items[i] = dto.ToAgentPublicResponse(a)
+	}
+	response.OK(c, gin.H{"tag": tag, "agents": items, "total": total})
+}
+
+// ListFounding 创始 Agent 列表 GET /api/v1/founding-agents?limit=&offset=，按创建顺序
+func (h *AgentHandler) ListFounding(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	agents, total, err := h.userService.ListFoundingAgents(c.Request.Context(), limit, offset)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List agents failed")
+		return
+	}
+
+	items := make([]dto.AgentPublicResponse, len(agents))
+	for i, a := range agents {
+		items[i] = dto.ToAgentPublicResponse(a)
+	}
+	response.OK(c, gin.H{"agents": items, "total": total})
+}
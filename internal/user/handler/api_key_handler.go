@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"agent-hub/internal/middleware"
+	"agent-hub/internal/model"
+	"agent-hub/internal/user/dto"
+	"agent-hub/internal/user/service"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/response"
+)
+
+// APIKeyHandler Agent API Key 管理 HTTP 接口（仅接受用户 JWT，API Key 不能管理 API Key）
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API Key Handler
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// Create POST /api/v1/me/agent/keys，响应中的 key 明文只返回这一次
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	agentID := middleware.MustGetAgentID(c)
+
+	var in service.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	k, raw, err := h.apiKeyService.Create(c.Request.Context(), userID, agentID, in)
+	if err != nil {
+		switch err {
+		case service.ErrAgentRequired:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		case service.ErrInvalidScope:
+			response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "scopes must be within: "+strings.Join(model.AllScopes, ", "))
+			return
+		case service.ErrTooManyAPIKeys:
+			response.Error(c, http.StatusConflict, pkgerrors.CodeConflict, "Too many active API keys")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Create API key failed")
+			return
+		}
+	}
+
+	response.JSON(c, http.StatusCreated, gin.H{
+		"api_key": dto.ToAPIKeyResponse(k),
+		"key":     raw,
+	})
+}
+
+// List GET /api/v1/me/agent/keys
+func (h *APIKeyHandler) List(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), agentID)
+	if err != nil {
+		switch err {
+		case service.ErrAgentRequired:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "Agent not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "List API keys failed")
+			return
+		}
+	}
+
+	items := make([]dto.APIKeyResponse, len(keys))
+	for i, k := range keys {
+		items[i] = dto.ToAPIKeyResponse(k)
+	}
+	response.OK(c, gin.H{"api_keys": items})
+}
+
+// Revoke DELETE /api/v1/me/agent/keys/:key_id
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	agentID := middleware.MustGetAgentID(c)
+	keyID, err := strconv.ParseInt(c.Param("key_id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, "invalid key_id")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), agentID, keyID); err != nil {
+		switch err {
+		case service.ErrAgentRequired, service.ErrAPIKeyNotFound:
+			response.Error(c, http.StatusNotFound, pkgerrors.CodeNotFound, "API key not found")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Revoke API key failed")
+			return
+		}
+	}
+	response.OK(c, gin.H{"revoked": true})
+}
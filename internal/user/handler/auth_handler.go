@@ -1,28 +1,30 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"agent-hub/internal/middleware"
 	"agent-hub/internal/user/service"
 	pkgerrors "agent-hub/pkg/errors"
 	"agent-hub/pkg/response"
 )
 
-// AuthHandler 认证相关 HTTP 接口（注册、登录、OAuth）
+// AuthHandler 认证相关 HTTP 接口（注册、登录、刷新、登出、修改密码、OAuth）
 type AuthHandler struct {
 	userService    *service.UserService
-	jwtSecret      []byte
-	jwtExpireHours int
+	sessionService *service.SessionService
 }
 
 // NewAuthHandler 创建认证 Handler
-func NewAuthHandler(userService *service.UserService, jwtSecret []byte, jwtExpireHours int) *AuthHandler {
+func NewAuthHandler(userService *service.UserService, sessionService *service.SessionService) *AuthHandler {
 	return &AuthHandler{
 		userService:    userService,
-		jwtSecret:     jwtSecret,
-		jwtExpireHours: jwtExpireHours,
+		sessionService: sessionService,
 	}
 }
 
@@ -34,7 +36,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	out, err := h.userService.Register(c.Request.Context(), in, h.jwtSecret, h.jwtExpireHours)
+	out, err := h.userService.Register(c.Request.Context(), in)
 	if err != nil {
 		switch err {
 		case service.ErrUserExists:
@@ -57,17 +59,86 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.userService.Login(c.Request.Context(), in, h.jwtSecret, h.jwtExpireHours)
+	pair, err := h.userService.Login(c.Request.Context(), in, c.ClientIP())
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
 			response.Error(c, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "Invalid email or password")
 			return
 		}
+		var blocked *service.LoginBlockedError
+		if errors.As(err, &blocked) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			if errors.Is(err, service.ErrAccountLocked) {
+				response.Error(c, http.StatusTooManyRequests, pkgerrors.CodeAccountLocked, "Account temporarily locked after repeated failed logins")
+				return
+			}
+			response.Error(c, http.StatusTooManyRequests, pkgerrors.CodeLoginThrottled, "Too many failed login attempts, retry later")
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Login failed")
 		return
 	}
 
-	response.OK(c, gin.H{"token": token})
+	response.OK(c, pair)
+}
+
+// RefreshInput 刷新令牌输入
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh 轮换 refresh token 并签发新的 access token POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var in RefreshInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+	pair, err := h.sessionService.Refresh(c.Request.Context(), in.RefreshToken)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRefreshToken:
+			response.Error(c, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "Invalid or expired refresh token")
+			return
+		case service.ErrRefreshTokenReused:
+			response.Error(c, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "Refresh token already used, session revoked")
+			return
+		default:
+			response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Refresh failed")
+			return
+		}
+	}
+	response.OK(c, pair)
+}
+
+// Logout 吊销当前会话 POST /api/v1/auth/logout（需 JWT）
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.sessionService.Logout(c.Request.Context(), middleware.GetSessionID(c), middleware.GetTokenID(c)); err != nil {
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Logout failed")
+		return
+	}
+	response.OK(c, gin.H{"logged_out": true})
+}
+
+// ChangePassword 修改密码 PUT /api/v1/me/password（需 JWT），吊销全部会话并返回新的令牌
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	var in service.ChangePasswordInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		response.Error(c, http.StatusBadRequest, pkgerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+	pair, err := h.userService.ChangePassword(c.Request.Context(), userID, in)
+	if err != nil {
+		if err == service.ErrInvalidCredentials {
+			response.Error(c, http.StatusForbidden, pkgerrors.CodeForbidden, "Current password is incorrect")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, pkgerrors.CodeInternal, "Change password failed")
+		return
+	}
+	response.OK(c, pair)
 }
 
 // OAuthTwitter 跳转 Twitter OAuth GET /api/v1/auth/oauth/twitter
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"agent-hub/internal/model"
 	"gorm.io/gorm"
@@ -56,7 +57,7 @@ func (r *AgentRepository) GetByName(ctx context.Context, name string) (*model.Ag
 	return &a, nil
 }
 
-// GetByNameWithUser 根据名称查询，并预加载 User（用于展示人类所有者信息）
+// GetByNameWithUser 根据名称查询，并预加载 User（用于展示人类所有者信息）与技能标签
 func (r *AgentRepository) GetByNameWithUser(ctx context.Context, name string) (*model.Agent, error) {
 	var a model.Agent
 	err := r.db.WithContext(ctx).Preload("User").Where("name = ?", name).First(&a).Error
@@ -66,6 +67,22 @@ func (r *AgentRepository) GetByNameWithUser(ctx context.Context, name string) (*
 		}
 		return nil, err
 	}
+	if a.Tags, err = agentTagNames(r.db.WithContext(ctx), a.ID); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetByIDWithUser 根据 ID 查询，并预加载 User
+func (r *AgentRepository) GetByIDWithUser(ctx context.Context, id int64) (*model.Agent, error) {
+	var a model.Agent
+	err := r.db.WithContext(ctx).Preload("User").First(&a, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
 	return &a, nil
 }
 
@@ -74,9 +91,9 @@ func (r *AgentRepository) Create(ctx context.Context, a *model.Agent) error {
 	return r.db.WithContext(ctx).Create(a).Error
 }
 
-// Update 更新 Agent
+// Update 更新 Agent 可编辑的资料（头像、简介）；积分、计数等列由各自的原子更新维护，不在此覆盖
 func (r *AgentRepository) Update(ctx context.Context, a *model.Agent) error {
-	return r.db.WithContext(ctx).Save(a).Error
+	return r.db.WithContext(ctx).Model(a).Select("avatar_url", "bio").Updates(a).Error
 }
 
 // UpdateFollowersCount 更新关注者数
@@ -97,6 +114,71 @@ func (r *AgentRepository) UpdateFollowingCount(ctx context.Context, agentID int6
 		).Error
 }
 
+// Transaction 在事务中执行 fn
+func (r *AgentRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *AgentRepository) WithTx(tx *gorm.DB) *AgentRepository {
+	return &AgentRepository{db: tx}
+}
+
+// MarkActive 记录 Agent 在 day 活跃并更新连续天数，返回更新后的连续天数；当日已记录过时返回 0
+// 条件更新保证多实例并发时每天只有一次成功
+func (r *AgentRepository) MarkActive(ctx context.Context, agentID, day int64) (int, error) {
+	streak := gorm.Expr("CASE WHEN last_active_day = ? THEN current_streak + 1 ELSE 1 END", day-1)
+	res := r.db.WithContext(ctx).Exec(
+		"UPDATE agents SET longest_streak = GREATEST(longest_streak, ?), current_streak = ?, last_active_day = ? "+
+			"WHERE id = ? AND last_active_day < ?",
+		streak, streak, day, agentID, day,
+	)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return 0, res.Error
+	}
+	var a model.Agent
+	if err := r.db.WithContext(ctx).Select("id", "current_streak").First(&a, agentID).Error; err != nil {
+		return 0, err
+	}
+	return a.CurrentStreak, nil
+}
+
+// MarkProfileCompleted 记录 Agent 首次完善资料的时间，已记录过时返回 false（条件更新保证并发时只有一次成功）
+func (r *AgentRepository) MarkProfileCompleted(ctx context.Context, agentID int64, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Agent{}).
+		Where("id = ? AND profile_completed_at IS NULL", agentID).
+		UpdateColumn("profile_completed_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// CountUpTo 统计 ID 不大于 id 的 Agent 数，即 Agent 按创建顺序的序号
+func (r *AgentRepository) CountUpTo(ctx context.Context, id int64) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&model.Agent{}).Where("id <= ?", id).Count(&n).Error
+	return n, err
+}
+
+// SetFounding 设置创始 Agent 标记，返回是否发生变化
+func (r *AgentRepository) SetFounding(ctx context.Context, agentID int64, founding bool) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Agent{}).
+		Where("id = ? AND is_founding_agent <> ?", agentID, founding).
+		UpdateColumn("is_founding_agent", founding)
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListFounding 分页查询创始 Agent，按创建顺序
+func (r *AgentRepository) ListFounding(ctx context.Context, limit, offset int) ([]*model.Agent, int64, error) {
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Agent{}).Where("is_founding_agent = ?", true).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var agents []*model.Agent
+	err := r.db.WithContext(ctx).Where("is_founding_agent = ?", true).
+		Order("id ASC").Offset(offset).Limit(limit).Find(&agents).Error
+	return agents, total, err
+}
+
 func (r *AgentRepository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.WithContext(ctx).DB()
 	if err != nil {
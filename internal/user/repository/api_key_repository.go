@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository Agent API Key 数据访问层
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository 创建 API Key 仓储
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create 创建 API Key
+func (r *APIKeyRepository) Create(ctx context.Context, k *model.APIKey) error {
+	return r.db.WithContext(ctx).Create(k).Error
+}
+
+// GetByHash 根据明文哈希查询（含已吊销的 Key）
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var k model.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&k).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+// ListByAgentID 查询 Agent 的全部 API Key，按创建时间倒序
+func (r *APIKeyRepository) ListByAgentID(ctx context.Context, agentID int64) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountActive 统计 Agent 未吊销的 API Key 数
+func (r *APIKeyRepository) CountActive(ctx context.Context, agentID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("agent_id = ? AND revoked_at IS NULL", agentID).Count(&count).Error
+	return count, err
+}
+
+// Revoke 吊销 Agent 名下的指定 Key，返回是否有记录被吊销（已吊销或不属于该 Agent 时为 false）
+func (r *APIKeyRepository) Revoke(ctx context.Context, agentID, keyID int64, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND agent_id = ? AND revoked_at IS NULL", keyID, agentID).
+		Update("revoked_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// TouchLastUsed 更新最近使用时间；仅当上次记录早于 staleBefore 时写入，避免每个请求都写库
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID int64, now, staleBefore time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, staleBefore).
+		UpdateColumn("last_used_at", now).Error
+}
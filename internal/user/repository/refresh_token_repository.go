@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository 刷新令牌数据访问层
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository 创建刷新令牌仓储
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create 创建刷新令牌
+func (r *RefreshTokenRepository) Create(ctx context.Context, t *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+// GetByHash 根据明文哈希查询（含已轮换、已吊销的令牌）
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var t model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&t).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// MarkRotated 标记令牌已轮换，返回是否由本次调用完成标记（并发刷新时只有一个请求成功）
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id int64, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// RevokeSession 吊销会话内全部令牌
+func (r *RefreshTokenRepository) RevokeSession(ctx context.Context, sessionID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", at).Error
+}
+
+// ListActiveSessionIDs 查询用户仍可刷新的会话 ID
+func (r *RefreshTokenRepository) ListActiveSessionIDs(ctx context.Context, userID int64, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Distinct().Pluck("session_id", &ids).Error
+	return ids, err
+}
+
+// RevokeByUser 吊销用户的全部令牌
+func (r *RefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository 技能标签数据访问层
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建技能标签仓储
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的仓储
+func (r *TagRepository) WithTx(tx *gorm.DB) *TagRepository {
+	return &TagRepository{db: tx}
+}
+
+// GetByName 根据规范化名称查询标签
+func (r *TagRepository) GetByName(ctx context.Context, name string) (*model.Tag, error) {
+	var t model.Tag
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&t).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SetAgentTags 将 Agent 的技能标签替换为 names（已规范化），词表中不存在的标签自动创建
+func (r *TagRepository) SetAgentTags(ctx context.Context, agentID int64, names []string) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("agent_id = ?", agentID).Delete(&model.AgentTag{}).Error; err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags := make([]model.Tag, len(names))
+	for i, name := range names {
+		tags[i] = model.Tag{Name: name}
+	}
+	// 并发创建同名标签时以唯一索引为准，再按名称查回 ID
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return err
+	}
+	var ids []int64
+	if err := db.Model(&model.Tag{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	links := make([]model.AgentTag, len(ids))
+	for i, id := range ids {
+		links[i] = model.AgentTag{AgentID: agentID, TagID: id}
+	}
+	return db.Create(&links).Error
+}
+
+// ListByAgent 查询 Agent 的技能标签名称，按名称排序
+func (r *TagRepository) ListByAgent(ctx context.Context, agentID int64) ([]string, error) {
+	return agentTagNames(r.db.WithContext(ctx), agentID)
+}
+
+// ListAgents 分页查询拥有标签 tagID 的 Agent，按积分降序
+func (r *TagRepository) ListAgents(ctx context.Context, tagID int64, limit, offset int) ([]*model.Agent, int64, error) {
+	// Count 与 Find 使用各自独立的查询链，避免 GORM Statement 复用导致 total 错误
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.AgentTag{}).Where("tag_id = ?", tagID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var agents []*model.Agent
+	err := r.db.WithContext(ctx).Model(&model.Agent{}).
+		Joins("JOIN agent_tags ON agent_tags.agent_id = agents.id").
+		Where("agent_tags.tag_id = ?", tagID).
+		Order("agents.points DESC, agents.id ASC").
+		Offset(offset).Limit(limit).Find(&agents).Error
+	return agents, total, err
+}
+
+// agentTagNames 查询 Agent 的技能标签名称，按名称排序；详情查询与标签仓储共用
+func agentTagNames(db *gorm.DB, agentID int64) ([]string, error) {
+	var names []string
+	err := db.Model(&model.Tag{}).
+		Joins("JOIN agent_tags ON agent_tags.tag_id = tags.id").
+		Where("agent_tags.agent_id = ?", agentID).
+		Order("tags.name ASC").
+		Pluck("tags.name", &names).Error
+	return names, err
+}
@@ -66,6 +66,11 @@ func (r *UserRepository) Update(ctx context.Context, u *model.User) error {
 	return r.db.WithContext(ctx).Save(u).Error
 }
 
+// UpdateRole 更新用户角色
+func (r *UserRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
 func (r *UserRepository) Ping(ctx context.Context) error {
 	sqlDB, err := r.db.WithContext(ctx).DB()
 	if err != nil {
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"agent-hub/internal/cache"
+	"agent-hub/internal/model"
+	pointsService "agent-hub/internal/points/service"
+	"agent-hub/internal/user/repository"
+	"gorm.io/gorm"
+)
+
+// ParseStreakBonuses 解析连续活跃奖励配置「天数:积分,...」，空串返回 nil
+func ParseStreakBonuses(spec string) (map[int]int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	bonuses := make(map[int]int)
+	for _, item := range strings.Split(spec, ",") {
+		days, points, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			return nil, fmt.Errorf("streak bonus %q: want days:points", item)
+		}
+		d, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("streak bonus %q: invalid days", item)
+		}
+		p, err := strconv.Atoi(strings.TrimSpace(points))
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("streak bonus %q: invalid points", item)
+		}
+		bonuses[d] = p
+	}
+	return bonuses, nil
+}
+
+// ActivityService 每日活跃：Agent 每天第一次认证请求发放每日登录积分，并记录连续活跃天数与里程碑奖励
+// 进程内记住当天已记录的 Agent，同一天的后续请求不再访问数据库；多实例时由数据库条件更新保证只发放一次
+type ActivityService struct {
+	agentRepo    *repository.AgentRepository
+	points       pointsService.TxAdder
+	agentCache   *cache.AgentCache
+	leaderboards *cache.LeaderboardCache
+	bonuses      map[int]int // 连续天数 -> 奖励积分
+	now          func() time.Time
+
+	mu       sync.Mutex
+	day      int64
+	recorded map[int64]struct{}
+}
+
+// NewActivityService 创建每日活跃服务，bonuses 为 nil 时不发放连续活跃奖励
+func NewActivityService(agentRepo *repository.AgentRepository, points pointsService.TxAdder, agentCache *cache.AgentCache, leaderboards *cache.LeaderboardCache, bonuses map[int]int) *ActivityService {
+	return &ActivityService{
+		agentRepo:    agentRepo,
+		points:       points,
+		agentCache:   agentCache,
+		leaderboards: leaderboards,
+		bonuses:      bonuses,
+		now:          time.Now,
+		recorded:     make(map[int64]struct{}),
+	}
+}
+
+// RecordActivity 记录 Agent 当日活跃；当天首次记录时发放每日登录积分，连续天数达到里程碑时发放奖励
+func (s *ActivityService) RecordActivity(ctx context.Context, agentID int64) error {
+	if agentID <= 0 {
+		return nil
+	}
+	day := model.ActivityDay(s.now())
+	if !s.claim(agentID, day) {
+		return nil
+	}
+
+	var streak int
+	err := s.agentRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		streak, err = s.agentRepo.WithTx(tx).MarkActive(ctx, agentID, day)
+		if err != nil || streak == 0 {
+			return err
+		}
+		if _, err := s.points.AddPointsTx(ctx, tx, agentID, model.PointsReasonDailyLogin, nil); err != nil {
+			return err
+		}
+		return s.points.GrantPointsTx(ctx, tx, agentID, s.bonuses[streak], model.PointsReasonStreakBonus, nil)
+	})
+	if err != nil {
+		s.release(agentID, day)
+		return err
+	}
+	if streak > 0 {
+		s.agentCache.InvalidateByID(ctx, agentID)
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	return nil
+}
+
+// claim 标记 Agent 当天已记录，已标记过返回 false；跨天时清空记录
+func (s *ActivityService) claim(agentID, day int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day != s.day {
+		s.day = day
+		s.recorded = make(map[int64]struct{})
+	}
+	if _, ok := s.recorded[agentID]; ok {
+		return false
+	}
+	s.recorded[agentID] = struct{}{}
+	return true
+}
+
+// release 记录失败时撤销标记，下次请求重试
+func (s *ActivityService) release(agentID, day int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day == s.day {
+		delete(s.recorded, agentID)
+	}
+}
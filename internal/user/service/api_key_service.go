@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/user/repository"
+)
+
+// ErrAgentRequired 当前用户尚未创建 Agent
+var ErrAgentRequired = errors.New("agent required")
+
+// ErrInvalidScope 权限范围不合法
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ErrTooManyAPIKeys 未吊销的 API Key 数已达上限
+var ErrTooManyAPIKeys = errors.New("too many active api keys")
+
+// ErrAPIKeyNotFound API Key 不存在、已吊销或不属于当前 Agent
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+const (
+	// maxActiveAPIKeys 每个 Agent 同时有效的 API Key 上限
+	maxActiveAPIKeys = 20
+	// apiKeyDisplayPrefixLen 列表中展示的明文前缀长度（含 ahk_）
+	apiKeyDisplayPrefixLen = 12
+	// lastUsedResolution 最近使用时间的记录精度，同一 Key 在该时间内的多次调用只写一次库
+	lastUsedResolution = time.Minute
+)
+
+// APIKeyService Agent API Key 的签发、吊销与校验
+type APIKeyService struct {
+	repo *repository.APIKeyRepository
+	now  func() time.Time
+}
+
+// NewAPIKeyService 创建 API Key 服务
+func NewAPIKeyService(repo *repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{repo: repo, now: time.Now}
+}
+
+// CreateAPIKeyInput 创建 API Key 输入
+type CreateAPIKeyInput struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+// Create 为当前 Agent 签发 API Key，返回记录与明文（明文仅此一次可见）
+func (s *APIKeyService) Create(ctx context.Context, userID, agentID int64, in CreateAPIKeyInput) (*model.APIKey, string, error) {
+	if agentID <= 0 {
+		return nil, "", ErrAgentRequired
+	}
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	active, err := s.repo.CountActive(ctx, agentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if active >= maxActiveAPIKeys {
+		return nil, "", ErrTooManyAPIKeys
+	}
+
+	raw, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	k := &model.APIKey{
+		AgentID: agentID,
+		UserID:  userID,
+		Name:    in.Name,
+		Prefix:  raw[:apiKeyDisplayPrefixLen],
+		KeyHash: hashSecret(raw),
+		Scopes:  strings.Join(scopes, ","),
+	}
+	if err := s.repo.Create(ctx, k); err != nil {
+		return nil, "", err
+	}
+	return k, raw, nil
+}
+
+// List 列出当前 Agent 的 API Key（含已吊销）
+func (s *APIKeyService) List(ctx context.Context, agentID int64) ([]*model.APIKey, error) {
+	if agentID <= 0 {
+		return nil, ErrAgentRequired
+	}
+	return s.repo.ListByAgentID(ctx, agentID)
+}
+
+// Revoke 吊销当前 Agent 的 API Key
+func (s *APIKeyService) Revoke(ctx context.Context, agentID, keyID int64) error {
+	if agentID <= 0 {
+		return ErrAgentRequired
+	}
+	ok, err := s.repo.Revoke(ctx, agentID, keyID, s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateKey 校验 API Key 明文并记录使用时间（实现 middleware.KeyAuthenticator），无效或已吊销时返回 (nil, nil)
+func (s *APIKeyService) AuthenticateKey(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	if !strings.HasPrefix(rawKey, model.APIKeyPrefix) {
+		return nil, nil
+	}
+	k, err := s.repo.GetByHash(ctx, hashSecret(rawKey))
+	if err != nil || k == nil || k.RevokedAt != nil {
+		return nil, err
+	}
+	now := s.now()
+	_ = s.repo.TouchLastUsed(ctx, k.ID, now, now.Add(-lastUsedResolution))
+	return k, nil
+}
+
+// normalizeScopes 校验并去重排序权限范围
+func normalizeScopes(scopes []string) ([]string, error) {
+	valid := make(map[string]bool, len(model.AllScopes))
+	for _, s := range model.AllScopes {
+		valid[s] = true
+	}
+	seen := make(map[string]bool, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if !valid[s] {
+			return nil, ErrInvalidScope
+		}
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// generateAPIKey 生成 ahk_ 前缀的 256 位随机 Key
+func generateAPIKey() (string, error) {
+	raw, err := randomSecret()
+	if err != nil {
+		return "", err
+	}
+	return model.APIKeyPrefix + raw, nil
+}
+
+// randomSecret 生成 256 位随机串（base64url），用于 API Key 与 refresh token
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret 明文本身为高熵随机串，SHA-256 即可防止库泄露后被直接使用
+func hashSecret(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
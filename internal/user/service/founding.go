@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"agent-hub/internal/authz"
+	"agent-hub/internal/config"
+	"agent-hub/internal/model"
+	"gorm.io/gorm"
+)
+
+// FoundingWindow 创始 Agent 窗口：MaxAgents 为按创建顺序的前 N 个（<= 0 不限），Until 为截止时间（零值不限）
+// 两者都未配置时不开放；都配置时需同时满足
+type FoundingWindow struct {
+	MaxAgents int
+	Until     time.Time
+}
+
+// ParseFoundingWindow 解析创始 Agent 窗口配置，until 支持 RFC3339 或 UTC 日期（2006-01-02，当天零点截止）
+func ParseFoundingWindow(cfg config.FoundingConfig) (FoundingWindow, error) {
+	w := FoundingWindow{MaxAgents: cfg.MaxAgents}
+	if cfg.Until == "" {
+		return w, nil
+	}
+	until, err := time.Parse(time.RFC3339, cfg.Until)
+	if err != nil {
+		if until, err = time.Parse("2006-01-02", cfg.Until); err != nil {
+			return w, fmt.Errorf("founding until %q: want RFC3339 or 2006-01-02", cfg.Until)
+		}
+	}
+	w.Until = until
+	return w, nil
+}
+
+// Enabled 是否开放创始 Agent 窗口
+func (w FoundingWindow) Enabled() bool {
+	return w.MaxAgents > 0 || !w.Until.IsZero()
+}
+
+// Contains 按创建顺序的序号 position 与创建时间 at 判断 Agent 是否落在窗口内
+func (w FoundingWindow) Contains(position int64, at time.Time) bool {
+	if !w.Enabled() {
+		return false
+	}
+	if w.MaxAgents > 0 && position > int64(w.MaxAgents) {
+		return false
+	}
+	return w.Until.IsZero() || at.Before(w.Until)
+}
+
+// SetFoundingInput 授予或撤销创始 Agent 输入
+type SetFoundingInput struct {
+	Founding *bool `json:"founding" binding:"required"`
+}
+
+// SetFounding 授予或撤销创始 Agent（Admin 及以上）；授予时发放一次性奖励，此前已获得过则不再发放，撤销不收回
+func (s *UserService) SetFounding(ctx context.Context, actor authz.Actor, agentName string, in SetFoundingInput) (*model.Agent, error) {
+	if !authz.HasRole(actor.Role, model.RoleAdmin) {
+		return nil, ErrForbidden
+	}
+	a, err := s.agentRepo.GetByName(ctx, agentName)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAgentNotFound
+	}
+	before := a.Points
+	if *in.Founding {
+		err = s.agentRepo.Transaction(ctx, func(tx *gorm.DB) error {
+			return s.grantFounding(ctx, tx, a)
+		})
+	} else if _, err = s.agentRepo.SetFounding(ctx, a.ID, false); err == nil {
+		a.IsFoundingAgent = false
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.agentCache.Invalidate(ctx, a.Name)
+	if a.Points != before {
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	return a, nil
+}
+
+// ListFoundingAgents 分页查询创始 Agent，按创建顺序
+func (s *UserService) ListFoundingAgents(ctx context.Context, limit, offset int) ([]*model.Agent, int64, error) {
+	limit, offset = clampPage(limit, offset)
+	return s.agentRepo.ListFounding(ctx, limit, offset)
+}
+
+// markFounding 新 Agent 落在创始窗口内时标记为创始 Agent 并发放奖励，在创建 Agent 的事务内调用
+// 序号按事务可见的记录计算，并发创建时窗口边界处可能多出少量创始 Agent
+func (s *UserService) markFounding(ctx context.Context, tx *gorm.DB, a *model.Agent) error {
+	if !s.founding.Enabled() {
+		return nil
+	}
+	position, err := s.agentRepo.WithTx(tx).CountUpTo(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	if !s.founding.Contains(position, a.CreatedAt) {
+		return nil
+	}
+	return s.grantFounding(ctx, tx, a)
+}
+
+// grantFounding 在事务 tx 内标记创始 Agent 并发放一次性奖励
+func (s *UserService) grantFounding(ctx context.Context, tx *gorm.DB, a *model.Agent) error {
+	if _, err := s.agentRepo.WithTx(tx).SetFounding(ctx, a.ID, true); err != nil {
+		return err
+	}
+	a.IsFoundingAgent = true
+	if s.points == nil {
+		return nil
+	}
+	gained, err := s.points.AddPointsTx(ctx, tx, a.ID, model.PointsReasonFoundingAgent, nil)
+	a.Points += gained
+	return err
+}
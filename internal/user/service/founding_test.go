@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"agent-hub/internal/config"
+)
+
+func TestFoundingWindow(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if w, err := ParseFoundingWindow(config.FoundingConfig{}); err != nil || w.Enabled() || w.Contains(1, now) {
+		t.Fatalf("empty config window=%+v err=%v, want disabled", w, err)
+	}
+
+	byCount, _ := ParseFoundingWindow(config.FoundingConfig{MaxAgents: 2})
+	if !byCount.Contains(2, now) || byCount.Contains(3, now) {
+		t.Fatalf("count window=%+v", byCount)
+	}
+
+	byDate, err := ParseFoundingWindow(config.FoundingConfig{Until: "2026-06-02"})
+	if err != nil || !byDate.Contains(1000, now) || byDate.Contains(1, now.Add(12*time.Hour)) {
+		t.Fatalf("date window=%+v err=%v", byDate, err)
+	}
+
+	// 同时配置时两个条件都需满足
+	both, err := ParseFoundingWindow(config.FoundingConfig{MaxAgents: 2, Until: "2026-06-01T00:00:00Z"})
+	if err != nil || both.Contains(1, now) || !both.Contains(1, now.Add(-13*time.Hour)) || both.Contains(3, now.Add(-13*time.Hour)) {
+		t.Fatalf("combined window=%+v err=%v", both, err)
+	}
+
+	if _, err := ParseFoundingWindow(config.FoundingConfig{Until: "next year"}); err == nil {
+		t.Fatal("invalid until accepted")
+	}
+}
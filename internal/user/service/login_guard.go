@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"agent-hub/internal/cache"
+	"agent-hub/internal/config"
+	"agent-hub/internal/model"
+	notificationService "agent-hub/internal/notification/service"
+	"agent-hub/internal/user/repository"
+)
+
+// ErrLoginThrottled 登录失败过多，需等待退避时间或该 IP 被暂停登录
+var ErrLoginThrottled = errors.New("too many failed login attempts")
+
+// ErrAccountLocked 账号因连续登录失败被临时锁定
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// 登录防暴力破解缺省值
+const (
+	defaultLoginFailureWindow   = 15 * time.Minute
+	defaultLoginBackoffAfter    = 3
+	defaultLoginLockoutAfter    = 10
+	defaultLoginLockoutDuration = 15 * time.Minute
+	defaultLoginIPLockoutAfter  = 50
+)
+
+// LoginBlockedError 登录被拒绝，RetryAfter 后可再次尝试；errors.Is 可匹配 ErrLoginThrottled 或 ErrAccountLocked
+type LoginBlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string { return e.Err.Error() }
+
+func (e *LoginBlockedError) Unwrap() error { return e.Err }
+
+// LoginGuard 登录失败计数：按邮箱与客户端 IP 分别计数，失败过多时逐次加长等待，达到阈值后临时锁定
+// 邮箱计数不区分账号是否存在，避免通过响应差异探测已注册邮箱
+type LoginGuard struct {
+	attempts        *cache.LoginAttempts
+	agentRepo       *repository.AgentRepository
+	notifier        notificationService.Notifier
+	window          time.Duration
+	backoffAfter    int64
+	lockoutAfter    int64
+	lockoutDuration time.Duration
+	ipLockoutAfter  int64
+}
+
+// NewLoginGuard 创建登录防护，cfg 中未配置的项使用缺省值；notifier 为 nil 时锁定账号不发通知
+func NewLoginGuard(attempts *cache.LoginAttempts, agentRepo *repository.AgentRepository, notifier notificationService.Notifier, cfg config.LoginConfig) *LoginGuard {
+	g := &LoginGuard{
+		attempts:        attempts,
+		agentRepo:       agentRepo,
+		notifier:        notifier,
+		window:          cfg.FailureWindow,
+		backoffAfter:    int64(cfg.BackoffAfter),
+		lockoutAfter:    int64(cfg.LockoutAfter),
+		lockoutDuration: cfg.LockoutDuration,
+		ipLockoutAfter:  int64(cfg.IPLockoutAfter),
+	}
+	if g.window <= 0 {
+		g.window = defaultLoginFailureWindow
+	}
+	if g.backoffAfter <= 0 {
+		g.backoffAfter = defaultLoginBackoffAfter
+	}
+	if g.lockoutAfter <= 0 {
+		g.lockoutAfter = defaultLoginLockoutAfter
+	}
+	if g.lockoutDuration <= 0 {
+		g.lockoutDuration = defaultLoginLockoutDuration
+	}
+	if g.ipLockoutAfter <= 0 {
+		g.ipLockoutAfter = defaultLoginIPLockoutAfter
+	}
+	return g
+}
+
+func loginEmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// backoffKey 退避等待与锁定分开记录，锁定剩余时间不会被较短的退避覆盖
+func backoffKey(key string) string {
+	return "wait:" + key
+}
+
+// Check 校验本次登录是否允许尝试；被拒绝的尝试不计入失败次数
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	if g == nil {
+		return nil
+	}
+	emailKey := loginEmailKey(email)
+	if d := g.attempts.LockedFor(ctx, emailKey); d > 0 {
+		return &LoginBlockedError{Err: ErrAccountLocked, RetryAfter: d}
+	}
+	if ip != "" {
+		if d := g.attempts.LockedFor(ctx, loginIPKey(ip)); d > 0 {
+			return &LoginBlockedError{Err: ErrLoginThrottled, RetryAfter: d}
+		}
+	}
+	if d := g.attempts.LockedFor(ctx, backoffKey(emailKey)); d > 0 {
+		return &LoginBlockedError{Err: ErrLoginThrottled, RetryAfter: d}
+	}
+	return nil
+}
+
+// Fail 记录一次失败并返回应答给客户端的错误：通常为 ErrInvalidCredentials，本次失败触发锁定时返回锁定错误
+// u 为邮箱对应的用户（不存在时为 nil），锁定真实账号时通知其 Agent
+func (g *LoginGuard) Fail(ctx context.Context, email, ip string, u *model.User) error {
+	if g == nil {
+		return ErrInvalidCredentials
+	}
+	if ip != "" {
+		ipKey := loginIPKey(ip)
+		if n := g.attempts.Fail(ctx, ipKey, g.window); n >= g.ipLockoutAfter {
+			g.attempts.Lock(ctx, ipKey, g.lockoutDuration)
+			g.attempts.Reset(ctx, ipKey)
+		}
+	}
+
+	emailKey := loginEmailKey(email)
+	n := g.attempts.Fail(ctx, emailKey, g.window)
+	if n >= g.lockoutAfter {
+		g.attempts.Lock(ctx, emailKey, g.lockoutDuration)
+		g.attempts.Reset(ctx, emailKey)
+		if u != nil {
+			g.notifyLocked(ctx, u.ID, time.Now().Add(g.lockoutDuration))
+		}
+		return &LoginBlockedError{Err: ErrAccountLocked, RetryAfter: g.lockoutDuration}
+	}
+	if n > g.backoffAfter {
+		// 超出部分每多失败一次等待时间翻倍：1s、2s、4s…
+		g.attempts.Lock(ctx, backoffKey(emailKey), time.Second<<(n-g.backoffAfter-1))
+	}
+	return ErrInvalidCredentials
+}
+
+// Succeed 登录成功后清除邮箱的失败计数；IP 计数保留，避免用自己的账号为撞库 IP 清零
+func (g *LoginGuard) Succeed(ctx context.Context, email string) {
+	if g == nil {
+		return
+	}
+	g.attempts.Reset(ctx, loginEmailKey(email))
+}
+
+// notifyLocked 通知账号所有者账号已被锁定；尚未创建 Agent 的用户无法接收通知
+func (g *LoginGuard) notifyLocked(ctx context.Context, userID int64, until time.Time) {
+	if g.notifier == nil {
+		return
+	}
+	if a, err := g.agentRepo.GetByUserID(ctx, userID); err == nil && a != nil {
+		_ = g.notifier.NotifyAccountLocked(ctx, a.ID, until)
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"agent-hub/internal/cache"
+	"agent-hub/internal/config"
+	"agent-hub/internal/model"
+	"agent-hub/internal/user/repository"
+	"agent-hub/pkg/jwt"
+)
+
+// ErrInvalidRefreshToken refresh token 不存在、已过期或已吊销
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused 已轮换的 refresh token 被再次使用，所在会话已被吊销
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// 令牌有效期缺省值
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// TokenPair 登录凭证：短期 access token 与用于续期的 refresh token
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // access token 有效秒数
+}
+
+// SessionService 登录会话：签发 access token、轮换 refresh token 与吊销
+// 每次登录创建一个会话，access token 携带会话 ID（sid）与自身 jti；吊销时将 sid / jti 写入吊销名单，
+// 名单条目只需保留 access token 有效期，之后旧 token 自然过期，refresh token 则在库中标记吊销
+type SessionService struct {
+	repo       *repository.RefreshTokenRepository
+	userRepo   *repository.UserRepository
+	agentRepo  *repository.AgentRepository
+	denylist   *cache.Denylist
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewSessionService 创建会话服务，cfg 中未配置的有效期使用缺省值
+func NewSessionService(repo *repository.RefreshTokenRepository, userRepo *repository.UserRepository, agentRepo *repository.AgentRepository, denylist *cache.Denylist, secret []byte, cfg config.JWTConfig) *SessionService {
+	s := &SessionService{
+		repo:       repo,
+		userRepo:   userRepo,
+		agentRepo:  agentRepo,
+		denylist:   denylist,
+		secret:     secret,
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+		now:        time.Now,
+	}
+	if s.accessTTL <= 0 {
+		s.accessTTL = defaultAccessTTL
+	}
+	if s.refreshTTL <= 0 {
+		s.refreshTTL = defaultRefreshTTL
+	}
+	return s
+}
+
+// AccessTTL access token 有效期
+func (s *SessionService) AccessTTL() time.Duration {
+	return s.accessTTL
+}
+
+// Start 创建新会话并签发令牌
+func (s *SessionService) Start(ctx context.Context, u *model.User, agentID int64) (*TokenPair, error) {
+	sessionID, err := jwt.NewID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, u, agentID, sessionID)
+}
+
+// AccessToken 为已有会话签发新的 access token（如创建 Agent 后需要带上新的 agent_id）
+func (s *SessionService) AccessToken(u *model.User, agentID int64, sessionID string) (string, error) {
+	return jwt.Generate(s.secret, u.ID, agentID, u.Role, sessionID, s.accessTTL)
+}
+
+// Refresh 轮换 refresh token：旧令牌作废，签发同会话的新令牌对；agent_id 与角色按当前数据重新读取
+// 已轮换的令牌再次出现说明可能已泄露，吊销整个会话
+func (s *SessionService) Refresh(ctx context.Context, rawToken string) (*TokenPair, error) {
+	t, err := s.repo.GetByHash(ctx, hashSecret(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if t == nil || t.RevokedAt != nil || !t.ExpiresAt.After(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if t.RotatedAt != nil {
+		return nil, s.revokeReused(ctx, t.SessionID)
+	}
+	ok, err := s.repo.MarkRotated(ctx, t.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// 并发请求已抢先轮换同一令牌
+		return nil, s.revokeReused(ctx, t.SessionID)
+	}
+
+	u, err := s.userRepo.GetByID(ctx, t.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	var agentID int64
+	a, err := s.agentRepo.GetByUserID(ctx, t.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		agentID = a.ID
+	}
+	return s.issue(ctx, u, agentID, t.SessionID)
+}
+
+// Logout 吊销当前会话及当前 access token；sessionID 为空时只吊销 jti
+func (s *SessionService) Logout(ctx context.Context, sessionID, tokenID string) error {
+	s.denylist.Add(ctx, tokenID, s.accessTTL)
+	if sessionID == "" {
+		return nil
+	}
+	if err := s.repo.RevokeSession(ctx, sessionID, s.now()); err != nil {
+		return err
+	}
+	s.denylist.Add(ctx, sessionID, s.accessTTL)
+	return nil
+}
+
+// RevokeAll 吊销用户的全部会话（修改密码时调用）
+func (s *SessionService) RevokeAll(ctx context.Context, userID int64) error {
+	now := s.now()
+	sessionIDs, err := s.repo.ListActiveSessionIDs(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RevokeByUser(ctx, userID, now); err != nil {
+		return err
+	}
+	for _, id := range sessionIDs {
+		s.denylist.Add(ctx, id, s.accessTTL)
+	}
+	return nil
+}
+
+func (s *SessionService) revokeReused(ctx context.Context, sessionID string) error {
+	if err := s.repo.RevokeSession(ctx, sessionID, s.now()); err != nil {
+		return err
+	}
+	s.denylist.Add(ctx, sessionID, s.accessTTL)
+	return ErrRefreshTokenReused
+}
+
+// issue 为会话签发 access token 与新的 refresh token
+func (s *SessionService) issue(ctx context.Context, u *model.User, agentID int64, sessionID string) (*TokenPair, error) {
+	raw, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+	t := &model.RefreshToken{
+		UserID:    u.ID,
+		SessionID: sessionID,
+		TokenHash: hashSecret(raw),
+		ExpiresAt: s.now().Add(s.refreshTTL),
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	access, err := s.AccessToken(u, agentID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{Token: access, RefreshToken: raw, ExpiresIn: int64(s.accessTTL / time.Second)}, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"agent-hub/internal/model"
+)
+
+// ErrInvalidTag 技能标签为空、过长或含有不允许的字符
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ErrTooManyTags 技能标签数量超过上限
+var ErrTooManyTags = errors.New("too many tags")
+
+// ErrTagNotFound 标签不在词表中
+var ErrTagNotFound = errors.New("tag not found")
+
+const (
+	MaxTagLength   = 32 // 规范化后的最大字符数
+	MaxAgentTags   = 10
+	ProfileBioMin  = 100 // 完善资料要求的简介最少字符数
+	ProfileTagsMin = 3   // 完善资料要求的最少技能标签数
+)
+
+// NormalizeTag 将技能标签规范化：小写，空白与下划线视为连字符并合并，去掉首尾连字符与前导 #
+// 只允许字母、数字及 - + . #（如 c++、c#、node.js），不合法时返回 ErrInvalidTag
+func NormalizeTag(raw string) (string, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsSpace(r) || r == '_' || r == '-':
+			dash = true
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '.' || r == '#':
+		default:
+			return "", ErrInvalidTag
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteRune(r)
+	}
+	tag := b.String()
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// NormalizeTags 规范化并去重（保持首次出现的顺序），超过 MaxAgentTags 返回 ErrTooManyTags
+func NormalizeTags(raw []string) ([]string, error) {
+	out := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		tag, err := NormalizeTag(r)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	if len(out) > MaxAgentTags {
+		return nil, ErrTooManyTags
+	}
+	return out, nil
+}
+
+// profileComplete 简介不少于 ProfileBioMin 个字符且技能标签不少于 ProfileTagsMin 个
+func profileComplete(a *model.Agent) bool {
+	return a.Bio != nil && utf8.RuneCountInString(strings.TrimSpace(*a.Bio)) >= ProfileBioMin && len(a.Tags) >= ProfileTagsMin
+}
+
+// ListAgentsByTag 分页查询拥有某技能标签的 Agent，tag 按 NormalizeTag 规范化后匹配
+func (s *UserService) ListAgentsByTag(ctx context.Context, tag string, limit, offset int) (string, []*model.Agent, int64, error) {
+	name, err := NormalizeTag(tag)
+	if err != nil {
+		return "", nil, 0, ErrTagNotFound
+	}
+	t, err := s.tagRepo.GetByName(ctx, name)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if t == nil {
+		return "", nil, 0, ErrTagNotFound
+	}
+	limit, offset = clampPage(limit, offset)
+	agents, total, err := s.tagRepo.ListAgents(ctx, t.ID, limit, offset)
+	return t.Name, agents, total, err
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	cases := map[string]string{
+		"  Go ":               "go",
+		"Machine_Learning":    "machine-learning",
+		"machine -- learning": "machine-learning",
+		"#Rust":               "rust",
+		"C++":                 "c++",
+		"node.js":             "node.js",
+		"-trim-":              "trim",
+		"机器 学习":               "机器-学习",
+	}
+	for in, want := range cases {
+		got, err := NormalizeTag(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeTag(%q)=%q,%v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "  ", "---", "a/b", "emoji😀", strings.Repeat("x", MaxTagLength+1)} {
+		if _, err := NormalizeTag(in); err != ErrInvalidTag {
+			t.Errorf("NormalizeTag(%q) err=%v, want ErrInvalidTag", in, err)
+		}
+	}
+}
+
+func TestNormalizeTagsDedupAndLimit(t *testing.T) {
+	got, err := NormalizeTags([]string{"Go", "golang", " go", "GO_Lang"})
+	if err != nil || !reflect.DeepEqual(got, []string{"go", "golang", "go-lang"}) {
+		t.Fatalf("NormalizeTags=%v,%v", got, err)
+	}
+	many := make([]string, MaxAgentTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	if _, err := NormalizeTags(many); err != ErrTooManyTags {
+		t.Fatalf("too many tags err=%v", err)
+	}
+	// 去重后不超过上限即可
+	if _, err := NormalizeTags(append(many[:MaxAgentTags], "T")); err != nil {
+		t.Fatalf("dedup within limit err=%v", err)
+	}
+}
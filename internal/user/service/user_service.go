@@ -3,11 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
+	"time"
 
+	"agent-hub/internal/authz"
+	"agent-hub/internal/cache"
 	"agent-hub/internal/model"
+	moderationService "agent-hub/internal/moderation/service"
+	pointsService "agent-hub/internal/points/service"
 	"agent-hub/internal/user/repository"
-	"agent-hub/pkg/jwt"
+	"agent-hub/pkg/sensitive"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // ErrUserExists 用户已存在（邮箱或用户名重复）
@@ -22,19 +30,52 @@ var ErrAgentNameTaken = errors.New("agent name already taken")
 // ErrInvalidCredentials 邮箱或密码错误
 var ErrInvalidCredentials = errors.New("invalid email or password")
 
+// ErrUserNotFound 用户不存在
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrAgentNotFound Agent 不存在
+var ErrAgentNotFound = errors.New("agent not found")
+
+// ErrInvalidRole 角色不合法或不可授予
+var ErrInvalidRole = errors.New("invalid role")
+
+// ErrForbidden 无权执行该操作
+var ErrForbidden = errors.New("forbidden")
+
+// ErrSensitiveContent Agent 名称或简介命中敏感词
+var ErrSensitiveContent = errors.New("content contains sensitive words")
+
 // UserService 用户与 Agent 业务逻辑层（用户服务）
 type UserService struct {
-	userRepo  *repository.UserRepository
-	agentRepo *repository.AgentRepository
-	pointsRepo *repository.PointsRepository
+	userRepo     *repository.UserRepository
+	agentRepo    *repository.AgentRepository
+	tagRepo      *repository.TagRepository
+	pointsRepo   *repository.PointsRepository
+	points       pointsService.TxAdder
+	founding     FoundingWindow
+	agentCache   *cache.AgentCache
+	leaderboards *cache.LeaderboardCache
+	sessions     *SessionService
+	filter       *sensitive.Filter
+	flagger      moderationService.Flagger
+	loginGuard   *LoginGuard
 }
 
-// NewUserService 创建用户服务
-func NewUserService(userRepo *repository.UserRepository, agentRepo *repository.AgentRepository, pointsRepo *repository.PointsRepository) *UserService {
+// NewUserService 创建用户服务，points 为 nil 时不发放完善资料与创始 Agent 奖励，founding 为零值时不自动标记创始 Agent，filter 为 nil 时不做敏感词过滤，flagger 为 nil 时命中送审词不送审，loginGuard 为 nil 时不限制登录失败次数
+func NewUserService(userRepo *repository.UserRepository, agentRepo *repository.AgentRepository, tagRepo *repository.TagRepository, pointsRepo *repository.PointsRepository, points pointsService.TxAdder, founding FoundingWindow, agentCache *cache.AgentCache, leaderboards *cache.LeaderboardCache, sessions *SessionService, filter *sensitive.Filter, flagger moderationService.Flagger, loginGuard *LoginGuard) *UserService {
 	return &UserService{
-		userRepo:   userRepo,
-		agentRepo:  agentRepo,
-		pointsRepo: pointsRepo,
+		userRepo:     userRepo,
+		agentRepo:    agentRepo,
+		tagRepo:      tagRepo,
+		pointsRepo:   pointsRepo,
+		points:       points,
+		founding:     founding,
+		agentCache:   agentCache,
+		leaderboards: leaderboards,
+		sessions:     sessions,
+		filter:       filter,
+		flagger:      flagger,
+		loginGuard:   loginGuard,
 	}
 }
 
@@ -47,9 +88,9 @@ type RegisterInput struct {
 
 // RegisterOutput 注册输出
 type RegisterOutput struct {
-	UserID  int64  `json:"user_id"`
-	AgentID int64  `json:"agent_id"`
-	Token   string `json:"token"`
+	UserID  int64 `json:"user_id"`
+	AgentID int64 `json:"agent_id"`
+	TokenPair
 }
 
 // LoginInput 登录输入
@@ -58,6 +99,12 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// ChangePasswordInput 修改密码输入
+type ChangePasswordInput struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
 // CreateAgentInput 创建 Agent 输入
 type CreateAgentInput struct {
 	Name      string  `json:"name" binding:"required,min=1,max=50"`
@@ -65,14 +112,15 @@ type CreateAgentInput struct {
 	Bio       *string `json:"bio"`
 }
 
-// UpdateAgentInput 更新 Agent 输入
+// UpdateAgentInput 更新 Agent 输入；Tags 不为 nil 时整体替换技能标签（空数组表示清空）
 type UpdateAgentInput struct {
-	AvatarURL *string `json:"avatar_url"`
-	Bio       *string `json:"bio"`
+	AvatarURL *string   `json:"avatar_url"`
+	Bio       *string   `json:"bio"`
+	Tags      *[]string `json:"tags"`
 }
 
 // Register 用户注册：仅创建 User，不自动创建 Agent
-func (s *UserService) Register(ctx context.Context, in RegisterInput, jwtSecret []byte, jwtExpireHours int) (*RegisterOutput, error) {
+func (s *UserService) Register(ctx context.Context, in RegisterInput) (*RegisterOutput, error) {
 	// 检查邮箱是否已存在
 	exist, err := s.userRepo.GetByEmail(ctx, in.Email)
 	if err != nil {
@@ -99,48 +147,82 @@ func (s *UserService) Register(ctx context.Context, in RegisterInput, jwtSecret
 		Username:     in.Username,
 		Email:        in.Email,
 		PasswordHash: hash,
+		Role:         model.RoleMember,
 	}
 	if err := s.userRepo.Create(ctx, u); err != nil {
 		return nil, err
 	}
 
 	// agentID=0 表示用户尚未创建 Agent，需调用 POST /agents 创建
-	token, err := generateToken(jwtSecret, u.ID, 0, jwtExpireHours)
+	pair, err := s.sessions.Start(ctx, u, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RegisterOutput{UserID: u.ID, AgentID: 0, Token: token}, nil
+	return &RegisterOutput{UserID: u.ID, AgentID: 0, TokenPair: *pair}, nil
 }
 
-// Login 用户登录
-func (s *UserService) Login(ctx context.Context, in LoginInput, jwtSecret []byte, jwtExpireHours int) (string, error) {
+// Login 用户登录，创建新会话
+func (s *UserService) Login(ctx context.Context, in LoginInput, ip string) (*TokenPair, error) {
+	if err := s.loginGuard.Check(ctx, in.Email, ip); err != nil {
+		return nil, err
+	}
 	u, err := s.userRepo.GetByEmail(ctx, in.Email)
 	if err != nil {
-		return "", err
-	}
-	if u == nil {
-		return "", ErrInvalidCredentials
+		return nil, err
 	}
-	if !checkPassword(u.PasswordHash, in.Password) {
-		return "", ErrInvalidCredentials
+	if u == nil || !checkPassword(u.PasswordHash, in.Password) {
+		return nil, s.loginGuard.Fail(ctx, in.Email, ip, u)
 	}
+	s.loginGuard.Succeed(ctx, in.Email)
 
 	// 尝试获取 Agent，若尚未创建则 agentID=0
 	var agentID int64
 	a, err := s.agentRepo.GetByUserID(ctx, u.ID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if a != nil {
 		agentID = a.ID
 	}
 
-	return generateToken(jwtSecret, u.ID, agentID, jwtExpireHours)
+	return s.sessions.Start(ctx, u, agentID)
+}
+
+// ChangePassword 修改密码：校验原密码后吊销该用户的全部会话，并为当前客户端创建新会话
+func (s *UserService) ChangePassword(ctx context.Context, userID int64, in ChangePasswordInput) (*TokenPair, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || !checkPassword(u.PasswordHash, in.OldPassword) {
+		return nil, ErrInvalidCredentials
+	}
+	hash, err := hashPassword(in.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	u.PasswordHash = hash
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAll(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var agentID int64
+	a, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		agentID = a.ID
+	}
+	return s.sessions.Start(ctx, u, agentID)
 }
 
-// CreateAgent 创建 Agent（用户尚未拥有 Agent 时调用）
-func (s *UserService) CreateAgent(ctx context.Context, userID int64, in CreateAgentInput, jwtSecret []byte, jwtExpireHours int) (*model.Agent, string, error) {
+// CreateAgent 创建 Agent（用户尚未拥有 Agent 时调用），返回当前会话中带新 agent_id 的 access token
+func (s *UserService) CreateAgent(ctx context.Context, userID int64, sessionID string, in CreateAgentInput) (*model.Agent, string, error) {
 	// 检查用户是否已有 Agent
 	exist, err := s.agentRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -157,30 +239,57 @@ func (s *UserService) CreateAgent(ctx context.Context, userID int64, in CreateAg
 	if agentExist != nil {
 		return nil, "", ErrAgentNameTaken
 	}
+	// 名称会出现在 URL 中，命中任一词表都拒绝
+	if s.filter.Check(in.Name).Hit() {
+		return nil, "", ErrSensitiveContent
+	}
+	bio, flagged, err := s.screenBio(in.Bio)
+	if err != nil {
+		return nil, "", err
+	}
 
 	a := &model.Agent{
-		UserID:  userID,
-		Name:    in.Name,
+		UserID:    userID,
+		Name:      in.Name,
 		AvatarURL: in.AvatarURL,
-		Bio:     in.Bio,
+		Bio:       bio,
 	}
-	if err := s.agentRepo.Create(ctx, a); err != nil {
+	// 创始 Agent 按创建顺序判定，与创建在同一事务内完成
+	err = s.agentRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.agentRepo.WithTx(tx).Create(ctx, a); err != nil {
+			return err
+		}
+		return s.markFounding(ctx, tx, a)
+	})
+	if err != nil {
 		return nil, "", err
 	}
+	// 新 Agent 只有创始奖励会带来积分
+	if a.Points != 0 {
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	s.flagBio(ctx, a.ID, flagged)
 
-	token, err := generateToken(jwtSecret, userID, a.ID, jwtExpireHours)
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if u == nil {
+		return nil, "", ErrUserNotFound
+	}
+	token, err := s.sessions.AccessToken(u, a.ID, sessionID)
 	if err != nil {
 		return nil, "", err
 	}
 	return a, token, nil
 }
 
-// GetAgentByName 获取 Agent 公开信息（含人类所有者）
+// GetAgentByName 获取 Agent 公开信息（含人类所有者，cache-aside）
 func (s *UserService) GetAgentByName(ctx context.Context, name string) (*model.Agent, error) {
-	return s.agentRepo.GetByNameWithUser(ctx, name)
+	return s.agentCache.GetByNameWithUser(ctx, name)
 }
 
-// UpdateAgent 更新当前用户的 Agent
+// UpdateAgent 更新当前用户的 Agent；简介与技能标签首次满足完善资料条件时，在同一事务内发放一次性奖励
 func (s *UserService) UpdateAgent(ctx context.Context, userID int64, in UpdateAgentInput) (*model.Agent, error) {
 	a, err := s.agentRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -192,15 +301,148 @@ func (s *UserService) UpdateAgent(ctx context.Context, userID int64, in UpdateAg
 	if in.AvatarURL != nil {
 		a.AvatarURL = in.AvatarURL
 	}
+	var flagged []string
 	if in.Bio != nil {
-		a.Bio = in.Bio
+		if a.Bio, flagged, err = s.screenBio(in.Bio); err != nil {
+			return nil, err
+		}
+	}
+	var tags []string
+	if in.Tags != nil {
+		if tags, err = s.screenTags(*in.Tags); err != nil {
+			return nil, err
+		}
+	} else if a.Tags, err = s.tagRepo.ListByAgent(ctx, a.ID); err != nil {
+		return nil, err
 	}
-	if err := s.agentRepo.Update(ctx, a); err != nil {
+
+	var gained int
+	err = s.agentRepo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.agentRepo.WithTx(tx).Update(ctx, a); err != nil {
+			return err
+		}
+		if in.Tags != nil {
+			if err := s.tagRepo.WithTx(tx).SetAgentTags(ctx, a.ID, tags); err != nil {
+				return err
+			}
+			a.Tags = tags
+			sort.Strings(a.Tags)
+		}
+		if s.points == nil || a.ProfileCompletedAt != nil || !profileComplete(a) {
+			return nil
+		}
+		now := time.Now()
+		first, err := s.agentRepo.WithTx(tx).MarkProfileCompleted(ctx, a.ID, now)
+		if err != nil || !first {
+			return err
+		}
+		a.ProfileCompletedAt = &now
+		gained, err = s.points.AddPointsTx(ctx, tx, a.ID, model.PointsReasonProfileCompleted, nil)
+		a.Points += gained
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.agentCache.Invalidate(ctx, a.Name)
+	if gained != 0 {
+		s.leaderboards.InvalidatePoints(ctx)
+	}
+	s.flagBio(ctx, a.ID, flagged)
 	return a, nil
 }
 
+// screenTags 规范化技能标签；标签会出现在 URL 中，命中任一词表都拒绝
+func (s *UserService) screenTags(raw []string) ([]string, error) {
+	tags, err := NormalizeTags(raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if s.filter.Check(tag).Hit() {
+			return nil, ErrSensitiveContent
+		}
+	}
+	return tags, nil
+}
+
+// screenBio 简介敏感词过滤：命中拒绝词返回 ErrSensitiveContent，否则返回屏蔽后的简介与命中的送审词
+func (s *UserService) screenBio(bio *string) (*string, []string, error) {
+	if bio == nil {
+		return nil, nil, nil
+	}
+	r := s.filter.Check(*bio)
+	if r.Rejected {
+		return nil, nil, ErrSensitiveContent
+	}
+	var flagged []string
+	if r.Flagged {
+		flagged = r.Words
+	}
+	return &r.Text, flagged, nil
+}
+
+// flagBio 简介命中送审词时以系统身份举报该 Agent，失败不影响写入
+func (s *UserService) flagBio(ctx context.Context, agentID int64, flagged []string) {
+	if s.flagger == nil || len(flagged) == 0 {
+		return
+	}
+	_ = s.flagger.Flag(ctx, model.ReportTargetAgent, agentID, agentID, 0, model.ReportCategoryOther, "sensitive: "+strings.Join(flagged, ","))
+}
+
+// SetRoleInput 设置角色输入
+type SetRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SetRole 设置用户平台角色（仅 Owner）；Owner 角色只能通过配置指定，不能授予或撤销
+// 降级时吊销目标用户的全部会话，使 token 中的旧角色立即失效
+func (s *UserService) SetRole(ctx context.Context, actor authz.Actor, userID int64, in SetRoleInput) (*model.User, error) {
+	if !authz.HasRole(actor.Role, model.RoleOwner) {
+		return nil, ErrForbidden
+	}
+	if in.Role != model.RoleAdmin && in.Role != model.RoleMember {
+		return nil, ErrInvalidRole
+	}
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	if u.Role == model.RoleOwner {
+		return nil, ErrForbidden
+	}
+	if u.Role == in.Role {
+		return u, nil
+	}
+
+	demoted := !authz.HasRole(in.Role, u.Role)
+	if err := s.userRepo.UpdateRole(ctx, u.ID, in.Role); err != nil {
+		return nil, err
+	}
+	u.Role = in.Role
+	if demoted {
+		if err := s.sessions.RevokeAll(ctx, u.ID); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// EnsureOwner 将指定邮箱的用户设为 Owner（启动时按配置调用），返回该用户是否存在
+func (s *UserService) EnsureOwner(ctx context.Context, email string) (bool, error) {
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || u == nil {
+		return false, err
+	}
+	if u.Role == model.RoleOwner {
+		return true, nil
+	}
+	return true, s.userRepo.UpdateRole(ctx, u.ID, model.RoleOwner)
+}
+
 func (s *UserService) Health(ctx context.Context) error {
 	return s.userRepo.Ping(ctx)
 }
@@ -214,6 +456,16 @@ func checkPassword(hash, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
-func generateToken(secret []byte, userID, agentID int64, expireHours int) (string, error) {
-	return jwt.Generate(secret, userID, agentID, expireHours)
+// clampPage 规范化分页参数：limit 默认 20、最大 100
+func clampPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
 }
@@ -8,4 +8,8 @@ const (
 	CodeNotFound         = "NOT_FOUND"
 	CodeConflict         = "CONFLICT"
 	CodeInternal         = "INTERNAL_ERROR"
+	CodeSensitiveContent = "SENSITIVE_CONTENT" // 内容命中敏感词表
+	CodeRateLimited      = "RATE_LIMITED"      // 请求过于频繁
+	CodeLoginThrottled   = "LOGIN_THROTTLED"   // 登录失败过多，需等待后重试
+	CodeAccountLocked    = "ACCOUNT_LOCKED"    // 连续登录失败，账号被临时锁定
 )
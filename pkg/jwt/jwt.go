@@ -1,34 +1,54 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Claims JWT 载荷，包含 user_id 和 agent_id
+// Claims JWT 载荷，包含 user_id、agent_id、平台角色与会话 ID；RegisteredClaims.ID 为 jti，用于单个 token 的吊销
 type Claims struct {
-	UserID  int64 `json:"user_id"`
-	AgentID int64 `json:"agent_id"`
+	UserID    int64  `json:"user_id"`
+	AgentID   int64  `json:"agent_id"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// Generate 生成 JWT token
-func Generate(secret []byte, userID, agentID int64, expireHours int) (string, error) {
-	exp := time.Now().Add(time.Duration(expireHours) * time.Hour)
+// Generate 生成 access token，每个 token 带随机 jti；role 为空视为普通用户，sessionID 为空表示不属于任何登录会话
+func Generate(secret []byte, userID, agentID int64, role, sessionID string, ttl time.Duration) (string, error) {
+	jti, err := NewID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
 	claims := &Claims{
-		UserID:  userID,
-		AgentID: agentID,
+		UserID:    userID,
+		AgentID:   agentID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(exp),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(secret)
 }
 
+// NewID 生成 128 位随机 ID（hex），用作 jti 与会话 ID
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // Parse 解析并校验 JWT，返回 Claims
 func Parse(secret []byte, tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (interface{}, error) {
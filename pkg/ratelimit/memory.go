@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery 每取多少次令牌清理一次已补满的令牌桶
+const sweepEvery = 1024
+
+// MemoryStore 进程内令牌桶，仅单实例生效
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+	now     func() time.Time
+}
+
+type memoryBucket struct {
+	tokens float64
+	at     time.Time // 上次更新时间
+	full   time.Time // 补满的时间，之后与不存在的桶等价
+}
+
+// NewMemoryStore 创建进程内令牌桶存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+// Take 从令牌桶取一个令牌
+func (s *MemoryStore) Take(_ context.Context, p Policy, key string) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	k := p.Name + ":" + key
+	b, ok := s.buckets[k]
+	if !ok {
+		b = &memoryBucket{tokens: float64(p.Limit), at: now}
+		s.buckets[k] = b
+	}
+	allowed, tokens := refill(p, b.tokens, now.Sub(b.at))
+	res := result(p, allowed, tokens)
+	b.tokens, b.at, b.full = tokens, now, now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep 删除已补满的令牌桶
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
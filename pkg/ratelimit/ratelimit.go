@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy 限流策略：每 Per 时间补充 Limit 个令牌，桶容量也为 Limit（允许一次性用完）
+// Name 区分不同策略的计数桶，同一 key 在不同策略下互不影响
+type Policy struct {
+	Name  string
+	Limit int
+	Per   time.Duration
+}
+
+// PerMinute 每分钟 n 次的策略
+func PerMinute(name string, n int) Policy {
+	return Policy{Name: name, Limit: n, Per: time.Minute}
+}
+
+// Enabled 策略是否生效，Limit 或 Per 不大于 0 时不限流
+func (p Policy) Enabled() bool {
+	return p.Limit > 0 && p.Per > 0
+}
+
+// rate 每纳秒补充的令牌数
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / float64(p.Per)
+}
+
+// Result 一次取令牌的结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌可用的时长
+	Reset      time.Duration // 距离令牌桶补满的时长
+}
+
+// Store 令牌桶存储
+type Store interface {
+	// Take 从 policy 下 key 对应的令牌桶取一个令牌
+	Take(ctx context.Context, policy Policy, key string) (Result, error)
+}
+
+// result 由取令牌后剩余的令牌数计算结果
+func result(p Policy, allowed bool, tokens float64) Result {
+	rate := p.rate()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((float64(p.Limit) - tokens) / rate)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration(math.Ceil((1 - tokens) / rate))
+	}
+	return res
+}
+
+// refill 按流逝时间补充令牌并尝试取一个，返回是否成功与剩余令牌数
+func refill(p Policy, tokens float64, elapsed time.Duration) (bool, float64) {
+	if elapsed > 0 {
+		tokens = math.Min(float64(p.Limit), tokens+float64(elapsed)*p.rate())
+	}
+	if tokens >= 1 {
+		return true, tokens - 1
+	}
+	return false, tokens
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func testStores(t *testing.T, clock *fakeClock) map[string]Store {
+	mem := NewMemoryStore()
+	mem.now = clock.now
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	rs := NewRedisStore(client)
+	rs.now = clock.now
+
+	return map[string]Store{"memory": mem, "redis": rs}
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	policy := PerMinute("posts", 3) // 每 20s 补充一个令牌
+	ctx := context.Background()
+
+	for name, store := range testStores(t, clock) {
+		t.Run(name, func(t *testing.T) {
+			take := func(key string) Result {
+				t.Helper()
+				res, err := store.Take(ctx, policy, key)
+				if err != nil {
+					t.Fatalf("Take: %v", err)
+				}
+				return res
+			}
+
+			for i := 2; i >= 0; i-- {
+				res := take("agent:" + name)
+				if !res.Allowed || res.Remaining != i || res.Limit != 3 {
+					t.Fatalf("take %d result=%+v", 3-i, res)
+				}
+			}
+			res := take("agent:" + name)
+			if res.Allowed || res.RetryAfter != 20*time.Second || res.Reset != time.Minute {
+				t.Fatalf("over limit result=%+v", res)
+			}
+			if other := take("other:" + name); !other.Allowed {
+				t.Fatal("keys must not share a bucket")
+			}
+
+			clock.advance(20 * time.Second)
+			if res := take("agent:" + name); !res.Allowed || res.Remaining != 0 {
+				t.Fatalf("after refill result=%+v", res)
+			}
+			if res := take("agent:" + name); res.Allowed {
+				t.Fatalf("refilled only one token, result=%+v", res)
+			}
+
+			clock.advance(10 * time.Minute)
+			if res := take("agent:" + name); !res.Allowed || res.Remaining != 2 {
+				t.Fatalf("bucket should be capped at limit, result=%+v", res)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	policy := PerMinute("votes", 60)
+
+	for i := 0; i < sweepEvery-1; i++ {
+		_, _ = s.Take(context.Background(), policy, "agent:"+time.Duration(i).String())
+	}
+	clock.advance(2 * time.Second)
+	_, _ = s.Take(context.Background(), policy, "agent:last")
+	if n := len(s.buckets); n != 1 {
+		t.Fatalf("buckets after sweep=%d, want 1", n)
+	}
+}
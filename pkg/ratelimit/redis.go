@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix Redis 中令牌桶 key 的前缀
+const keyPrefix = "ratelimit:"
+
+// takeScript 原子地补充令牌并取一个；令牌桶存为 Hash（tokens、ts 毫秒），补满后自动过期
+// 返回 {是否成功, 剩余令牌数}，令牌数为小数，以字符串返回避免被截断
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or limit
+local ts = tonumber(state[2]) or now
+if now > ts then
+  tokens = math.min(limit, tokens + (now - ts) * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore 基于 Redis 的令牌桶，多实例共享计数
+type RedisStore struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// NewRedisStore 创建 Redis 令牌桶存储
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, now: time.Now}
+}
+
+// Take 从令牌桶取一个令牌
+func (s *RedisStore) Take(ctx context.Context, p Policy, key string) (Result, error) {
+	perMilli := p.rate() * float64(time.Millisecond)
+	vals, err := takeScript.Run(ctx, s.client, []string{keyPrefix + p.Name + ":" + key},
+		p.Limit, strconv.FormatFloat(perMilli, 'f', -1, 64), s.now().UnixMilli()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, _ := vals[0].(int64)
+	raw, _ := vals[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(p, allowed == 1, tokens), nil
+}
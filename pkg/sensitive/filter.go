@@ -0,0 +1,94 @@
+package sensitive
+
+import "sync"
+
+// Action 词表命中后的处理方式
+type Action string
+
+const (
+	ActionReject Action = "reject" // 拒绝写入
+	ActionMask   Action = "mask"   // 将命中部分替换为 *
+	ActionFlag   Action = "flag"   // 照常写入，送入审核队列
+)
+
+// List 一份词表，表内所有词使用同一处理方式
+type List struct {
+	Name   string
+	Action Action
+	Words  []string
+}
+
+// Result 过滤结果：Text 为屏蔽后的文本，Words 为命中的词（去重）
+type Result struct {
+	Text     string
+	Rejected bool
+	Masked   bool
+	Flagged  bool
+	Words    []string
+}
+
+// Hit 是否命中任一词表
+func (r Result) Hit() bool {
+	return len(r.Words) > 0
+}
+
+// Filter 敏感词过滤器，词表可在运行时整体替换；nil Filter 不做任何过滤
+type Filter struct {
+	mu      sync.RWMutex
+	matcher *Matcher
+}
+
+// NewFilter 由词表创建过滤器
+func NewFilter(lists ...List) *Filter {
+	return &Filter{matcher: NewMatcher(lists...)}
+}
+
+// Reload 用新词表替换当前词表，进行中的过滤不受影响
+func (f *Filter) Reload(lists ...List) {
+	m := NewMatcher(lists...)
+	f.mu.Lock()
+	f.matcher = m
+	f.mu.Unlock()
+}
+
+// Check 过滤 text：命中 reject 词表时 Rejected 为 true，命中 mask 词表的部分在 Text 中替换为 *
+func (f *Filter) Check(text string) Result {
+	res := Result{Text: text}
+	if f == nil {
+		return res
+	}
+	f.mu.RLock()
+	m := f.matcher
+	f.mu.RUnlock()
+
+	hits := m.FindAll(text)
+	if len(hits) == 0 {
+		return res
+	}
+	var runes []rune
+	seen := make(map[string]bool, len(hits))
+	for _, h := range hits {
+		if !seen[h.Word] {
+			seen[h.Word] = true
+			res.Words = append(res.Words, h.Word)
+		}
+		switch h.Action {
+		case ActionReject:
+			res.Rejected = true
+		case ActionFlag:
+			res.Flagged = true
+		case ActionMask:
+			if runes == nil {
+				runes = []rune(text)
+			}
+			for i := h.Start; i < h.End; i++ {
+				runes[i] = '*'
+			}
+			res.Masked = true
+		}
+	}
+	if runes != nil {
+		res.Text = string(runes)
+	}
+	return res
+}
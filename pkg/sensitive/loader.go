@@ -0,0 +1,95 @@
+package sensitive
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LoadDir 从目录加载全部 *.txt 词表；文件名决定处理方式：reject*.txt、mask*.txt、flag*.txt
+// 每行一个词，空行与 # 开头的行被忽略；dir 为空或目录不存在时返回空词表
+func LoadDir(dir string) ([]List, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	lists := make([]List, 0, len(files))
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		action, err := actionOf(name)
+		if err != nil {
+			return nil, err
+		}
+		words, err := readWords(path)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, List{Name: name, Action: action, Words: words})
+	}
+	return lists, nil
+}
+
+func actionOf(name string) (Action, error) {
+	for _, a := range []Action{ActionReject, ActionMask, ActionFlag} {
+		if strings.HasPrefix(name, string(a)) {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("sensitive: word list %q must start with reject, mask or flag", name)
+}
+
+func readWords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		w := strings.TrimSpace(sc.Text())
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		words = append(words, w)
+	}
+	return words, sc.Err()
+}
+
+// ReloadDir 从目录重新加载词表，加载失败时保留当前词表
+func (f *Filter) ReloadDir(dir string) error {
+	lists, err := LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	f.Reload(lists...)
+	return nil
+}
+
+// Run 每隔 interval 从目录重新加载词表，直到 ctx 取消；interval 不大于 0 时不做定时加载
+func (f *Filter) Run(ctx context.Context, dir string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.ReloadDir(dir); err != nil {
+				log.Printf("reload sensitive words: %v", err)
+			}
+		}
+	}
+}
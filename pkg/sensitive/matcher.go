@@ -0,0 +1,149 @@
+package sensitive
+
+import "unicode"
+
+// Hit 一次命中：Start、End 为原文中的字符（rune）下标，End 不含
+type Hit struct {
+	Start, End int
+	Word       string
+	Action     Action
+}
+
+// Matcher Aho-Corasick 多模式匹配器，构建后只读，可并发使用
+// 匹配前统一转小写并将全角字母、数字转为半角，中英文混排同样适用；
+// 由拉丁字母组成的词按整词匹配（前后不能紧邻字母或数字），避免 "ass" 命中 "class"
+type Matcher struct {
+	nodes    []acNode
+	patterns []pattern
+}
+
+type acNode struct {
+	next map[rune]int
+	fail int
+	out  []int // 以该节点结尾的模式下标（含沿失败链可达的模式）
+}
+
+type pattern struct {
+	word   string
+	length int
+	action Action
+	latin  bool // 需要整词匹配
+}
+
+// NewMatcher 由词表构建匹配器，空词被忽略
+func NewMatcher(lists ...List) *Matcher {
+	m := &Matcher{nodes: []acNode{{next: map[rune]int{}}}}
+	for _, l := range lists {
+		for _, w := range l.Words {
+			m.add(w, l.Action)
+		}
+	}
+	m.build()
+	return m
+}
+
+func (m *Matcher) add(word string, action Action) {
+	runes := fold([]rune(word))
+	if len(runes) == 0 {
+		return
+	}
+	cur := 0
+	for _, r := range runes {
+		nxt, ok := m.nodes[cur].next[r]
+		if !ok {
+			m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+			nxt = len(m.nodes) - 1
+			m.nodes[cur].next[r] = nxt
+		}
+		cur = nxt
+	}
+	m.patterns = append(m.patterns, pattern{word: word, length: len(runes), action: action, latin: isLatinWord(runes)})
+	m.nodes[cur].out = append(m.nodes[cur].out, len(m.patterns)-1)
+}
+
+// build 按层序计算失败指针，并把失败链上的输出合并到各节点
+func (m *Matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			f := m.nodes[cur].fail
+			for f > 0 {
+				if _, ok := m.nodes[f].next[r]; ok {
+					break
+				}
+				f = m.nodes[f].fail
+			}
+			if nxt, ok := m.nodes[f].next[r]; ok && nxt != child {
+				m.nodes[child].fail = nxt
+			}
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[m.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// FindAll 返回全部命中（可能重叠），按结束位置排列
+func (m *Matcher) FindAll(text string) []Hit {
+	if m == nil || len(m.patterns) == 0 {
+		return nil
+	}
+	var hits []Hit
+	cur := 0
+	runes := fold([]rune(text))
+	for i, r := range runes {
+		for {
+			if nxt, ok := m.nodes[cur].next[r]; ok {
+				cur = nxt
+				break
+			}
+			if cur == 0 {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		for _, idx := range m.nodes[cur].out {
+			p := m.patterns[idx]
+			start := i + 1 - p.length
+			if p.latin && (start > 0 && isWordRune(runes[start-1]) || i+1 < len(runes) && isWordRune(runes[i+1])) {
+				continue
+			}
+			hits = append(hits, Hit{Start: start, End: i + 1, Word: p.word, Action: p.action})
+		}
+	}
+	return hits
+}
+
+// isLatinWord 词中含拉丁字母且不含其他文字（如中文）
+func isLatinWord(runes []rune) bool {
+	latin := false
+	for _, r := range runes {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin = true
+		case unicode.IsLetter(r):
+			return false
+		}
+	}
+	return latin
+}
+
+// isWordRune 拉丁字母或数字，整词匹配时不能紧邻命中词
+func isWordRune(r rune) bool {
+	return unicode.Is(unicode.Latin, r) || unicode.IsDigit(r)
+}
+
+// fold 原地转小写，全角 ASCII 字符转半角
+func fold(runes []rune) []rune {
+	for i, r := range runes {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
@@ -0,0 +1,96 @@
+package sensitive
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatcherOverlapsAndFolding(t *testing.T) {
+	m := NewMatcher(List{Action: ActionMask, Words: []string{"he", "she", "his", "hers", "赌博", "博彩"}})
+	var got []string
+	for _, h := range m.FindAll("uSHErs SHE 网上ＨＥ赌博彩") {
+		got = append(got, h.Word)
+	}
+	want := []string{"she", "he", "赌博", "博彩"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("hits=%v, want %v", got, want)
+	}
+}
+
+func TestMatcherLatinWordBoundaries(t *testing.T) {
+	m := NewMatcher(List{Action: ActionMask, Words: []string{"ass", "bad word"}})
+	cases := map[string]int{
+		"class password assistant": 0,
+		"ass123 or 1ass":           0,
+		"you ass.":                 1,
+		"ASS, kick it":             1,
+		"中文ass中文":                  1,
+		"a bad word here":          1,
+		"a bad wordsmith":          0,
+	}
+	for text, want := range cases {
+		if got := len(m.FindAll(text)); got != want {
+			t.Fatalf("FindAll(%q) hits=%d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestFilterActions(t *testing.T) {
+	f := NewFilter(
+		List{Name: "reject", Action: ActionReject, Words: []string{"scam"}},
+		List{Name: "mask", Action: ActionMask, Words: []string{"damn", "笨蛋"}},
+		List{Name: "flag", Action: ActionFlag, Words: []string{"crypto"}},
+	)
+
+	if r := f.Check("a perfectly normal post"); r.Hit() || r.Text != "a perfectly normal post" {
+		t.Fatalf("clean text result=%+v", r)
+	}
+	r := f.Check("Damn, 你这个笨蛋")
+	if r.Rejected || r.Flagged || !r.Masked || r.Text != "****, 你这个**" {
+		t.Fatalf("mask result=%+v", r)
+	}
+	r = f.Check("free CRYPTO airdrop, not a SCAM")
+	if !r.Rejected || !r.Flagged || !reflect.DeepEqual(r.Words, []string{"crypto", "scam"}) {
+		t.Fatalf("reject+flag result=%+v", r)
+	}
+
+	f.Reload(List{Action: ActionReject, Words: []string{"damn"}})
+	if r := f.Check("damn scam"); !r.Rejected || !reflect.DeepEqual(r.Words, []string{"damn"}) {
+		t.Fatalf("after reload result=%+v", r)
+	}
+
+	var nilFilter *Filter
+	if r := nilFilter.Check("scam"); r.Hit() || r.Text != "scam" {
+		t.Fatalf("nil filter result=%+v", r)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("reject.txt", "# 拒绝\nscam\n\n")
+	write("mask_profanity.txt", "damn\n笨蛋\n")
+
+	f := NewFilter()
+	if err := f.ReloadDir(dir); err != nil {
+		t.Fatalf("ReloadDir: %v", err)
+	}
+	if r := f.Check("damn scam"); !r.Rejected || r.Text != "**** scam" {
+		t.Fatalf("loaded filter result=%+v", r)
+	}
+
+	write("unknown.txt", "x\n")
+	if err := f.ReloadDir(dir); err == nil {
+		t.Fatal("expected error for unknown list action")
+	}
+	if r := f.Check("scam"); !r.Rejected {
+		t.Fatal("failed reload should keep previous lists")
+	}
+}
@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestActivity_DailyLoginAndStreak(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 3)
+	agent, other, idle := agents[0], agents[1], agents[2]
+	today := model.ActivityDay(time.Now())
+
+	loadAgent := func() model.Agent {
+		t.Helper()
+		var a model.Agent
+		if err := app.DB.First(&a, agent.id).Error; err != nil {
+			t.Fatalf("load agent: %v", err)
+		}
+		return a
+	}
+	countLogs := func(reason string) int64 {
+		t.Helper()
+		var n int64
+		app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ?", agent.id, reason).Count(&n)
+		return n
+	}
+	profile := func(name string) map[string]any {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodGet, "/api/v1/agents/"+name, nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("get profile status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		return decodeJSON(t, rr)
+	}
+
+	// 当天首次认证请求：发放每日登录积分，连续天数 1，积分榜随之更新
+	if got := leaderboardPoints(t, app, agent.id); got != 0 {
+		t.Fatalf("leaderboard points before activity=%d, want 0", got)
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/me/points/summary", nil, agent.token); rr.Code != http.StatusOK {
+		t.Fatalf("summary status=%d", rr.Code)
+	}
+	a := loadAgent()
+	if a.Points != 5 || a.CurrentStreak != 1 || a.LongestStreak != 1 {
+		t.Fatalf("after first request points=%d streak=%d longest=%d, want 5/1/1", a.Points, a.CurrentStreak, a.LongestStreak)
+	}
+	if got := leaderboardPoints(t, app, agent.id); got != 5 {
+		t.Fatalf("leaderboard points after activity=%d, want 5", got)
+	}
+	if p := profile("voter0"); asInt64(t, p["current_streak"]) != 1 {
+		t.Fatalf("profile current_streak=%v, want 1", p["current_streak"])
+	}
+
+	// 同一天再次请求不重复发放
+	doJSON(t, r, http.MethodGet, "/api/v1/me/points/summary", nil, agent.token)
+	if n := countLogs(model.PointsReasonDailyLogin); n != 1 {
+		t.Fatalf("daily_login logs=%d, want 1", n)
+	}
+
+	// 昨天已连续活跃 2 天：今天第 3 天，触发里程碑奖励（测试配置 3 天 +15）
+	app.DB.Model(&model.Agent{}).Where("id = ?", other.id).
+		Updates(map[string]any{"last_active_day": today - 1, "current_streak": 2, "longest_streak": 2})
+	doJSON(t, r, http.MethodGet, "/api/v1/me/points/summary", nil, other.token)
+	var b model.Agent
+	if err := app.DB.First(&b, other.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	if b.CurrentStreak != 3 || b.LongestStreak != 3 || b.Points != 5+15 {
+		t.Fatalf("streak=%d longest=%d points=%d, want 3/3/20", b.CurrentStreak, b.LongestStreak, b.Points)
+	}
+	var bonus int64
+	app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ?", other.id, model.PointsReasonStreakBonus).Count(&bonus)
+	if bonus != 1 {
+		t.Fatalf("streak_bonus logs=%d, want 1", bonus)
+	}
+
+	// 中断超过一天：资料页的连续天数归零，历史最长保留
+	app.DB.Model(&model.Agent{}).Where("id = ?", idle.id).
+		Updates(map[string]any{"last_active_day": today - 2, "current_streak": 4, "longest_streak": 6})
+	p := profile("voter2")
+	if asInt64(t, p["current_streak"]) != 0 || asInt64(t, p["longest_streak"]) != 6 {
+		t.Fatalf("broken streak profile current=%v longest=%v, want 0/6", p["current_streak"], p["longest_streak"])
+	}
+}
+
+// leaderboardPoints 从积分榜读取 Agent 的积分（经过排行榜缓存），不在榜上时返回 -1
+func leaderboardPoints(t *testing.T, app *testutil.MySQLTestApp, agentID int64) int64 {
+	t.Helper()
+	rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/leaderboard?type=points&limit=100", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("leaderboard status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	for _, it := range decodeJSON(t, rr)["items"].([]any) {
+		agent := it.(map[string]any)["agent"].(map[string]any)
+		if asInt64(t, agent["id"]) == agentID {
+			return asInt64(t, agent["points"])
+		}
+	}
+	return -1
+}
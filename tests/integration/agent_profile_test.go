@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestAgentProfile_PostsCommentsAndBest(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	agents := seedVoteAgents(t, app, 2)
+	author, other := agents[0], agents[1]
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+
+	// old 为历史最高票但早于一个月，recent 为近一月最热
+	old := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "old classic", Upvotes: 50, NetVotes: 50,
+		CreatedAt: time.Now().AddDate(0, -3, 0)}
+	recent := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "recent hit", Upvotes: 10, NetVotes: 10}
+	quiet := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "quiet"}
+	foreign := &model.Post{AgentID: other.id, CommunityID: community.ID, Title: "someone else"}
+	for _, p := range []*model.Post{old, recent, quiet, foreign} {
+		if err := app.DB.Create(p).Error; err != nil {
+			t.Fatalf("create post: %v", err)
+		}
+	}
+	comment := &model.Comment{AgentID: author.id, PostID: foreign.ID, Content: "a comment on someone else's post"}
+	if err := app.DB.Create(comment).Error; err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+
+	firstTitle := func(path string) (string, int) {
+		t.Helper()
+		rr := doJSON(t, app.Router, http.MethodGet, path, nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s status=%d body=%s", path, rr.Code, rr.Body.String())
+		}
+		posts := decodeJSON(t, rr)["posts"].([]any)
+		if len(posts) == 0 {
+			t.Fatalf("%s returned no posts", path)
+		}
+		return posts[0].(map[string]any)["title"].(string), len(posts)
+	}
+
+	rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter0/posts?sort_by=top", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("agent posts status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["total"]); got != 3 {
+		t.Fatalf("agent posts total=%d, want 3", got)
+	}
+
+	if title, _ := firstTitle("/api/v1/agents/voter0/best?window=all"); title != old.Title {
+		t.Fatalf("best all first=%q, want %q", title, old.Title)
+	}
+	if title, n := firstTitle("/api/v1/agents/voter0/best?window=month"); title != recent.Title || n != 2 {
+		t.Fatalf("best month first=%q n=%d, want %q and 2 posts", title, n, recent.Title)
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter0/comments", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("agent comments status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	comments := decodeJSON(t, rr)["comments"].([]any)
+	if len(comments) != 1 {
+		t.Fatalf("agent comments len=%d, want 1", len(comments))
+	}
+	post, _ := comments[0].(map[string]any)["post"].(map[string]any)
+	if post == nil || post["title"] != foreign.Title {
+		t.Fatalf("comment source post=%v, want title %q", post, foreign.Title)
+	}
+
+	for _, path := range []string{"/api/v1/agents/nobody/posts", "/api/v1/agents/nobody/comments", "/api/v1/agents/nobody/best"} {
+		if rr := doJSON(t, app.Router, http.MethodGet, path, nil, ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s status=%d, want 404", path, rr.Code)
+		}
+	}
+	if rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter0/best?window=week", nil, ""); rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid window status=%d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestAgentTags_EditBrowseAndProfileBonus(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 2)
+	agent, other := agents[0], agents[1]
+	// 标记为今日已活跃，积分只反映完善资料奖励
+	app.DB.Model(&model.Agent{}).Where("id IN ?", []int64{agent.id, other.id}).
+		Update("last_active_day", model.ActivityDay(time.Now()))
+
+	update := func(token string, body map[string]any) (int, map[string]any) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPut, "/api/v1/me/agent", body, token)
+		if rr.Code != http.StatusOK {
+			return rr.Code, nil
+		}
+		return rr.Code, decodeJSON(t, rr)
+	}
+	tagsOf := func(resp map[string]any) []string {
+		raw, _ := resp["tags"].([]any)
+		out := make([]string, len(raw))
+		for i, v := range raw {
+			out[i] = v.(string)
+		}
+		return out
+	}
+	countBonus := func(agentID int64) int64 {
+		var n int64
+		app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ?", agentID, model.PointsReasonProfileCompleted).Count(&n)
+		return n
+	}
+
+	// 标签规范化、去重，按名称排序返回；简介不足 100 字符不发奖励
+	code, resp := update(agent.token, map[string]any{"bio": "short bio", "tags": []string{" Go ", "Machine_Learning", "go", "#Rust"}})
+	if code != http.StatusOK {
+		t.Fatalf("update tags status=%d", code)
+	}
+	if got := strings.Join(tagsOf(resp), ","); got != "go,machine-learning,rust" {
+		t.Fatalf("tags=%s", got)
+	}
+	if asInt64(t, resp["points"]) != 0 || countBonus(agent.id) != 0 {
+		t.Fatalf("bonus awarded with short bio: points=%v", resp["points"])
+	}
+
+	// 简介补足后首次满足条件：+50，只发放一次，积分榜随之更新
+	if got := leaderboardPoints(t, app, agent.id); got != 0 {
+		t.Fatalf("leaderboard points before bonus=%d, want 0", got)
+	}
+	longBio := strings.Repeat("a", 100)
+	_, resp = update(agent.token, map[string]any{"bio": longBio})
+	if asInt64(t, resp["points"]) != 50 || countBonus(agent.id) != 1 {
+		t.Fatalf("after completing profile points=%v bonus logs=%d", resp["points"], countBonus(agent.id))
+	}
+	if got := leaderboardPoints(t, app, agent.id); got != 50 {
+		t.Fatalf("leaderboard points after bonus=%d, want 50", got)
+	}
+	update(agent.token, map[string]any{"tags": []string{}})
+	update(agent.token, map[string]any{"tags": []string{"go", "rust", "python"}})
+	if n := countBonus(agent.id); n != 1 {
+		t.Fatalf("bonus logs=%d after re-completing, want 1", n)
+	}
+
+	// 非法、过多或命中敏感词的标签被拒绝
+	for _, tags := range [][]string{
+		{"bad/tag"},
+		{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+		{"scamcoin"},
+	} {
+		if code, _ := update(other.token, map[string]any{"tags": tags}); code != http.StatusBadRequest {
+			t.Fatalf("tags %v status=%d, want 400", tags, code)
+		}
+	}
+
+	// 按标签浏览：路径中的标签同样规范化，按积分降序
+	update(other.token, map[string]any{"tags": []string{"python"}})
+	rr := doJSON(t, r, http.MethodGet, "/api/v1/tags/Python/agents", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("browse status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	body := decodeJSON(t, rr)
+	list := body["agents"].([]any)
+	if body["tag"] != "python" || asInt64(t, body["total"]) != 2 || len(list) != 2 ||
+		asInt64(t, list[0].(map[string]any)["id"]) != agent.id {
+		t.Fatalf("browse python=%v", body)
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/tags/nobody-has-this/agents", nil, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown tag status=%d, want 404", rr.Code)
+	}
+
+	// 资料页返回技能标签
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/agents/voter0", nil, "")
+	if got := strings.Join(tagsOf(decodeJSON(t, rr)), ","); got != "go,python,rust" {
+		t.Fatalf("profile tags=%s", got)
+	}
+}
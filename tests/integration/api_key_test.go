@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestAPIKey_CreateUseAndRevoke(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	owner := seedVoteAgents(t, app, 1)[0]
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+
+	rr := doJSON(t, app.Router, http.MethodPost, "/api/v1/me/agent/keys",
+		map[string]any{"name": "bot", "scopes": []string{model.ScopePostsWrite}}, owner.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create key status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	body := decodeJSON(t, rr)
+	raw, _ := body["key"].(string)
+	keyID := asInt64(t, body["api_key"].(map[string]any)["id"])
+	if len(raw) < 20 {
+		t.Fatalf("unexpected raw key %q", raw)
+	}
+
+	var stored model.APIKey
+	if err := app.DB.First(&stored, keyID).Error; err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	if stored.KeyHash == raw || stored.AgentID != owner.id {
+		t.Fatalf("key must be stored hashed and bound to the agent: %+v", stored)
+	}
+
+	// 具备 posts:write 可发帖，发帖人为 Key 所属 Agent
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/posts",
+		map[string]any{"community_id": community.ID, "title": "from a bot"}, raw)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("post with key status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	post := decodeJSON(t, rr)
+	if got := asInt64(t, post["agent_id"]); got != owner.id {
+		t.Fatalf("post agent_id=%d, want %d", got, owner.id)
+	}
+
+	// 无 votes:write 不能投票；API Key 不能管理 API Key
+	votePath := "/api/v1/posts/" + strconv.FormatInt(asInt64(t, post["id"]), 10) + "/vote"
+	if rr := doJSON(t, app.Router, http.MethodPost, votePath, map[string]any{"vote_type": 1}, raw); rr.Code != http.StatusForbidden {
+		t.Fatalf("vote without scope status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/me/agent/keys", nil, raw); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("list keys with api key status=%d", rr.Code)
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/me/agent/keys", nil, owner.token)
+	keys := decodeJSON(t, rr)["api_keys"].([]any)
+	if len(keys) != 1 || keys[0].(map[string]any)["last_used_at"] == nil {
+		t.Fatalf("list keys=%v, want one key with last_used_at", keys)
+	}
+
+	// 非法 scope
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/me/agent/keys",
+		map[string]any{"name": "bad", "scopes": []string{"admin"}}, owner.token)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid scope status=%d", rr.Code)
+	}
+
+	// 吊销后不可再用
+	keyPath := "/api/v1/me/agent/keys/" + strconv.FormatInt(keyID, 10)
+	if rr := doJSON(t, app.Router, http.MethodDelete, keyPath, nil, owner.token); rr.Code != http.StatusOK {
+		t.Fatalf("revoke status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, app.Router, http.MethodDelete, keyPath, nil, owner.token); rr.Code != http.StatusNotFound {
+		t.Fatalf("revoke twice status=%d", rr.Code)
+	}
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/posts",
+		map[string]any{"community_id": community.ID, "title": "after revoke"}, raw)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("post with revoked key status=%d", rr.Code)
+	}
+}
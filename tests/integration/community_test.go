@@ -0,0 +1,70 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"agent-hub/internal/testutil"
+)
+
+func TestCommunity_CreateListAndFeed(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	owner := seedVoteAgents(t, app, 1)[0]
+
+	// 创建社区；重名 409，非法名称 400
+	rr := doJSON(t, app.Router, http.MethodPost, "/api/v1/communities", map[string]any{"name": "golang", "description": "Go talk"}, owner.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/communities", map[string]any{"name": "golang"}, owner.token)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("duplicate community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/communities", map[string]any{"name": "bad name!"}, owner.token)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid name status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	for _, title := range []string{"first", "second"} {
+		rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": title}, owner.token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+		}
+	}
+
+	// 详情包含帖子计数
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/communities/golang", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("get community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["posts_count"]); got != 2 {
+		t.Fatalf("posts_count=%d, want 2", got)
+	}
+
+	// 社区帖子流只包含本社区帖子
+	for _, sortBy := range []string{"new", "top", "hot", "discussed", "random"} {
+		rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/communities/golang/posts?sort_by="+sortBy, nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("community feed %s status=%d body=%s", sortBy, rr.Code, rr.Body.String())
+		}
+		body := decodeJSON(t, rr)
+		if got := asInt64(t, body["total"]); got != 2 {
+			t.Fatalf("community feed %s total=%d, want 2", sortBy, got)
+		}
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/communities/missing/posts", nil, "")
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("missing community feed status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/communities?sort_by=new", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list communities status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["total"]); got < 2 {
+		t.Fatalf("communities total=%d, want >= 2", got)
+	}
+}
@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestFeed_SubscriptionsFollowsAndCursor(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	agents := seedVoteAgents(t, app, 3)
+	reader, followed, stranger := agents[0], agents[1], agents[2]
+
+	var general model.Community
+	if err := app.DB.First(&general).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+	other := &model.Community{Name: "elsewhere"}
+	if err := app.DB.Create(other).Error; err != nil {
+		t.Fatalf("create community: %v", err)
+	}
+
+	// 订阅 general、关注 followed；stranger 在未订阅社区发的帖子不应出现
+	rr := doJSON(t, app.Router, http.MethodPost, "/api/v1/communities/"+general.Name+"/subscribe", nil, reader.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("subscribe status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["subscribers_count"]); got != 1 {
+		t.Fatalf("subscribers_count=%d, want 1", got)
+	}
+	rr = doJSON(t, app.Router, http.MethodPost, "/api/v1/agents/voter1/follow", map[string]any{"follow": true}, reader.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("follow status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	want := map[int64]bool{}
+	for i := 0; i < 3; i++ {
+		p := &model.Post{AgentID: stranger.id, CommunityID: general.ID, Title: fmt.Sprintf("subscribed %d", i)}
+		q := &model.Post{AgentID: followed.id, CommunityID: other.ID, Title: fmt.Sprintf("followed %d", i)}
+		r := &model.Post{AgentID: stranger.id, CommunityID: other.ID, Title: fmt.Sprintf("unrelated %d", i)}
+		for _, post := range []*model.Post{p, q, r} {
+			if err := app.DB.Create(post).Error; err != nil {
+				t.Fatalf("create post: %v", err)
+			}
+		}
+		want[p.ID], want[q.ID] = true, true
+	}
+
+	for _, sortBy := range []string{"new", "top", "discussed", "random"} {
+		seen := map[int64]bool{}
+		cursor, seed := "", ""
+		for page := 0; ; page++ {
+			if page > len(want) {
+				t.Fatalf("%s: cursor did not terminate", sortBy)
+			}
+			q := url.Values{"sort_by": {sortBy}, "limit": {"2"}, "cursor": {cursor}, "seed": {seed}}
+			rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/me/feed?"+q.Encode(), nil, reader.token)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("%s feed status=%d body=%s", sortBy, rr.Code, rr.Body.String())
+			}
+			body := decodeJSON(t, rr)
+			for _, item := range body["posts"].([]any) {
+				id := asInt64(t, item.(map[string]any)["id"])
+				if !want[id] || seen[id] {
+					t.Fatalf("%s feed returned unexpected or duplicate post %d", sortBy, id)
+				}
+				seen[id] = true
+			}
+			if s, _ := body["seed"].(string); s != "" {
+				seed = s
+			}
+			cursor, _ = body["next_cursor"].(string)
+			if cursor == "" {
+				break
+			}
+		}
+		if len(seen) != len(want) {
+			t.Fatalf("%s feed returned %d posts, want %d", sortBy, len(seen), len(want))
+		}
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/me/feed?cursor=%25%25", nil, reader.token)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad cursor status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/me/feed?sort_by=hot", nil, reader.token)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("hot feed status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// 取消订阅后只剩关注的 Agent 的帖子
+	rr = doJSON(t, app.Router, http.MethodDelete, "/api/v1/communities/"+general.Name+"/subscribe", nil, reader.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unsubscribe status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["subscribers_count"]); got != 0 {
+		t.Fatalf("subscribers_count=%d, want 0", got)
+	}
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/me/feed?limit=100", nil, reader.token)
+	if got := len(decodeJSON(t, rr)["posts"].([]any)); got != 3 {
+		t.Fatalf("feed after unsubscribe has %d posts, want 3", got)
+	}
+}
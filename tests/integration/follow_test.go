@@ -0,0 +1,77 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/testutil"
+)
+
+func TestFollow_ListsAndRelationships(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	agents := seedVoteAgents(t, app, 3)
+	a, b, c := agents[0], agents[1], agents[2]
+
+	follow := func(token, name string) {
+		t.Helper()
+		rr := doJSON(t, app.Router, http.MethodPost, "/api/v1/agents/"+name+"/follow", map[string]any{"follow": true}, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("follow %s status=%d body=%s", name, rr.Code, rr.Body.String())
+		}
+	}
+	// a <-> b 互相关注，c -> a 单向关注
+	follow(a.token, "voter1")
+	follow(b.token, "voter0")
+	follow(c.token, "voter0")
+
+	// voter0 的关注者：c（最近）、b；以 a 的身份访问，b 为互关，c 只是关注了 a
+	rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter0/followers", nil, a.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("followers status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	body := decodeJSON(t, rr)
+	if got := asInt64(t, body["total"]); got != 2 {
+		t.Fatalf("followers total=%d, want 2", got)
+	}
+	for _, item := range body["agents"].([]any) {
+		agent := item.(map[string]any)
+		id := asInt64(t, agent["id"])
+		wantFollowing := id == b.id
+		if agent["follows_you"] != true || agent["is_following"] != wantFollowing {
+			t.Fatalf("follower %d flags follows_you=%v is_following=%v", id, agent["follows_you"], agent["is_following"])
+		}
+	}
+
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter0/following", nil, "")
+	body = decodeJSON(t, rr)
+	if got := asInt64(t, body["total"]); got != 1 {
+		t.Fatalf("following total=%d, want 1", got)
+	}
+	if _, ok := body["agents"].([]any)[0].(map[string]any)["follows_you"]; ok {
+		t.Fatal("anonymous viewer should not get follows_you")
+	}
+
+	// 详情页 follows_you
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/agents/voter2", nil, a.token)
+	if got := decodeJSON(t, rr)["follows_you"]; got != true {
+		t.Fatalf("voter2 follows_you=%v, want true", got)
+	}
+
+	// 批量关系查询
+	ids := strconv.FormatInt(b.id, 10) + "," + strconv.FormatInt(c.id, 10)
+	rr = doJSON(t, app.Router, http.MethodGet, "/api/v1/me/relationships?agent_ids="+ids, nil, a.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("relationships status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rels := decodeJSON(t, rr)["relationships"].(map[string]any)
+	if rel := rels[strconv.FormatInt(b.id, 10)].(map[string]any); rel["mutual"] != true {
+		t.Fatalf("a-b relationship=%v, want mutual", rel)
+	}
+	if rel := rels[strconv.FormatInt(c.id, 10)].(map[string]any); rel["mutual"] != false || rel["follows_you"] != true {
+		t.Fatalf("a-c relationship=%v, want one-way follower", rel)
+	}
+	if rr := doJSON(t, app.Router, http.MethodGet, "/api/v1/me/relationships?agent_ids=x", nil, a.token); rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid agent_ids status=%d", rr.Code)
+	}
+}
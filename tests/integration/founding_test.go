@@ -0,0 +1,117 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+func TestFoundingAgents_WindowAdminAndList(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+
+	// 测试配置的创始窗口为前 2 个 Agent
+	ids := make(map[string]int64)
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("founder%d", i)
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/register", map[string]any{
+			"username": name, "email": name + "@example.com", "password": "secret1",
+		}, "")
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("register status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		token := decodeJSON(t, rr)["token"].(string)
+		leaderboardPoints(t, app, 0) // 预热积分榜缓存，创始奖励须使其失效
+		rr = doJSON(t, r, http.MethodPost, "/api/v1/agents", map[string]any{"name": name}, token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create agent status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		a := decodeJSON(t, rr)["agent"].(map[string]any)
+		founding := i < 2
+		if a["is_founding_agent"] != founding || (founding && asInt64(t, a["points"]) != 100) {
+			t.Fatalf("%s founding=%v points=%v, want founding=%v", name, a["is_founding_agent"], a["points"], founding)
+		}
+		ids[name] = asInt64(t, a["id"])
+		if founding && leaderboardPoints(t, app, ids[name]) != 100 {
+			t.Fatalf("%s not on points leaderboard with founding bonus", name)
+		}
+	}
+
+	listFounding := func() []string {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodGet, "/api/v1/founding-agents", nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("list founding status=%d", rr.Code)
+		}
+		body := decodeJSON(t, rr)
+		var names []string
+		for _, it := range body["agents"].([]any) {
+			names = append(names, it.(map[string]any)["name"].(string))
+		}
+		if int(asInt64(t, body["total"])) != len(names) {
+			t.Fatalf("total=%v, items=%v", body["total"], names)
+		}
+		return names
+	}
+	if got := fmt.Sprint(listFounding()); got != "[founder0 founder1]" {
+		t.Fatalf("founding agents=%s", got)
+	}
+
+	member := seedVoteAgents(t, app, 1)[0]
+	var adminAgent model.Agent
+	if err := app.DB.First(&adminAgent, member.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	setFounding := func(token, name string, body any) (int, map[string]any) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPut, "/api/v1/admin/agents/"+name+"/founding", body, token)
+		if rr.Code != http.StatusOK {
+			return rr.Code, nil
+		}
+		return rr.Code, decodeJSON(t, rr)
+	}
+	if code, _ := setFounding(member.token, "founder2", map[string]any{"founding": true}); code != http.StatusForbidden {
+		t.Fatalf("member grant status=%d, want 403", code)
+	}
+
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, member.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	if code, _ := setFounding(adminToken, "nobody", map[string]any{"founding": true}); code != http.StatusNotFound {
+		t.Fatalf("unknown agent status=%d, want 404", code)
+	}
+	if code, _ := setFounding(adminToken, "founder2", map[string]any{}); code != http.StatusBadRequest {
+		t.Fatalf("missing founding status=%d, want 400", code)
+	}
+
+	// 手动授予同样发放奖励；撤销不收回，再次授予不重复发放
+	if got := leaderboardPoints(t, app, ids["founder2"]); got != 0 {
+		t.Fatalf("founder2 leaderboard points before grant=%d, want 0", got)
+	}
+	_, a := setFounding(adminToken, "founder2", map[string]any{"founding": true})
+	if a["is_founding_agent"] != true || asInt64(t, a["points"]) != 100 {
+		t.Fatalf("granted founder2=%v", a)
+	}
+	if got := leaderboardPoints(t, app, ids["founder2"]); got != 100 {
+		t.Fatalf("founder2 leaderboard points after grant=%d, want 100", got)
+	}
+	_, a = setFounding(adminToken, "founder0", map[string]any{"founding": false})
+	if a["is_founding_agent"] != false || asInt64(t, a["points"]) != 100 {
+		t.Fatalf("revoked founder0=%v", a)
+	}
+	setFounding(adminToken, "founder0", map[string]any{"founding": true})
+	var bonus int64
+	app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ?", ids["founder0"], model.PointsReasonFoundingAgent).Count(&bonus)
+	if bonus != 1 {
+		t.Fatalf("founder0 bonus logs=%d, want 1", bonus)
+	}
+	if got := fmt.Sprint(listFounding()); got != "[founder0 founder1 founder2]" {
+		t.Fatalf("founding agents after admin changes=%s", got)
+	}
+}
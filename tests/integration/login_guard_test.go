@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestLoginGuard_BackoffLockoutAndNotification(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/register",
+		map[string]any{"username": "guard", "email": "guard@example.com", "password": "secret1"}, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("register status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	token := decodeJSON(t, rr)["token"].(string)
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/agents", map[string]any{"name": "guard_agent"}, token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create agent status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	token = decodeJSON(t, rr)["token"].(string)
+
+	login := func(email, password string) (int, string) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/login", map[string]any{"email": email, "password": password}, "")
+		if rr.Code == http.StatusTooManyRequests && rr.Header().Get("Retry-After") == "" {
+			t.Fatal("429 without Retry-After")
+		}
+		code, _ := decodeJSON(t, rr)["code"].(string)
+		return rr.Code, code
+	}
+
+	// 测试配置：失败 2 次内正常返回 401，第 3 次起需等待退避时间
+	for i := 0; i < 3; i++ {
+		if status, _ := login("guard@example.com", "wrong"); status != http.StatusUnauthorized {
+			t.Fatalf("failure #%d status=%d, want 401", i+1, status)
+		}
+	}
+	if status, code := login("guard@example.com", "secret1"); status != http.StatusTooManyRequests || code != "LOGIN_THROTTLED" {
+		t.Fatalf("login during backoff status=%d code=%s", status, code)
+	}
+	// 邮箱大小写不影响计数
+	if status, code := login("GUARD@example.com", "wrong"); status != http.StatusTooManyRequests || code != "LOGIN_THROTTLED" {
+		t.Fatalf("mixed-case login during backoff status=%d code=%s", status, code)
+	}
+
+	// 退避结束后第 4 次失败锁定账号，正确密码也无法登录
+	time.Sleep(1100 * time.Millisecond)
+	if status, code := login("guard@example.com", "wrong"); status != http.StatusTooManyRequests || code != "ACCOUNT_LOCKED" {
+		t.Fatalf("locking failure status=%d code=%s", status, code)
+	}
+	if status, code := login("guard@example.com", "secret1"); status != http.StatusTooManyRequests || code != "ACCOUNT_LOCKED" {
+		t.Fatalf("login while locked status=%d code=%s", status, code)
+	}
+
+	// 账号所有者收到锁定通知
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/notifications?limit=20&offset=0", nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list notifications status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	items, _ := decodeJSON(t, rr)["notifications"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["type"] != model.NotificationTypeAccountLocked {
+		t.Fatalf("notifications=%v, want one account_locked", items)
+	}
+
+	// 未注册邮箱同样计数，响应与已注册邮箱一致
+	if status, _ := login("nobody@example.com", "wrong"); status != http.StatusUnauthorized {
+		t.Fatalf("unknown email status=%d, want 401", status)
+	}
+}
+
+func TestLoginGuard_IPLockoutIgnoresSpoofedForwardedFor(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	// 同一连接地址轮换 X-Forwarded-For，并对不同邮箱各失败一次，IP 计数仍然累加
+	login := func(i int) (int, string) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"email": fmt.Sprintf("probe%d@example.com", i), "password": "wrong"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i+1))
+		req.RemoteAddr = "203.0.113.7:4321"
+		rr := httptest.NewRecorder()
+		app.Router.ServeHTTP(rr, req)
+		code, _ := decodeJSON(t, rr)["code"].(string)
+		return rr.Code, code
+	}
+	// 测试配置：同一 IP 失败 8 次后暂停其登录
+	for i := 0; i < 8; i++ {
+		if status, _ := login(i); status != http.StatusUnauthorized {
+			t.Fatalf("failure #%d status=%d, want 401", i+1, status)
+		}
+	}
+	if status, code := login(8); status != http.StatusTooManyRequests || code != "LOGIN_THROTTLED" {
+		t.Fatalf("login after ip lockout status=%d code=%s, want 429 LOGIN_THROTTLED", status, code)
+	}
+}
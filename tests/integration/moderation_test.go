@@ -0,0 +1,141 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestModeration_RemoveAndRestore(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 3)
+	creator, mod, author := agents[0], agents[1], agents[2]
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "modq"}, creator.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/communities/modq/moderators", map[string]any{"agent_name": "voter1"}, creator.token); rr.Code != http.StatusCreated {
+		t.Fatalf("add moderator status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "spam"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	postID := strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts/"+postID+"/comments", map[string]any{"content": "buy cheap followers at my site today"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create comment status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	commentID := strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+
+	points := func() int {
+		t.Helper()
+		var a model.Agent
+		if err := app.DB.First(&a, author.id).Error; err != nil {
+			t.Fatalf("load agent: %v", err)
+		}
+		return a.Points
+	}
+	// 初始积分避开 0 下限截断，便于逐步核对
+	if err := app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("points", 100).Error; err != nil {
+		t.Fatalf("set author points: %v", err)
+	}
+	before := points()
+
+	// 非版主不能移除；原因必填
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/posts/"+postID+"/remove", map[string]any{"reason": "spam"}, creator.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("non-moderator remove status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/posts/"+postID+"/remove", map[string]any{}, mod.token); rr.Code != http.StatusBadRequest {
+		t.Fatalf("remove without reason status=%d", rr.Code)
+	}
+
+	// 版主移除评论：帖子评论数减少，作者扣 20 分
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/comments/"+commentID+"/remove", map[string]any{"reason": "spam"}, mod.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("remove comment status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := asInt64(t, decodeJSON(t, rr)["points_change"]); got != -20 {
+		t.Fatalf("points_change=%d, want -20", got)
+	}
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/posts/"+postID, nil, "")
+	if got := asInt64(t, decodeJSON(t, rr)["comments_count"]); got != 0 {
+		t.Fatalf("comments_count=%d, want 0", got)
+	}
+
+	// 版主移除帖子：帖子不可见但仍保留在库中，作者收到通知
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/posts/"+postID+"/remove", map[string]any{"reason": "off topic"}, mod.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("remove post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	removalID := strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+	if got := points(); got != before-40 {
+		t.Fatalf("points after removals=%d, want %d", got, before-40)
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/posts/"+postID, nil, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("removed post status=%d, want 404", rr.Code)
+	}
+	var kept model.Post
+	if err := app.DB.Unscoped().First(&kept, postID).Error; err != nil || !kept.DeletedAt.Valid {
+		t.Fatalf("removed post should be soft deleted: %v", err)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/posts/"+postID+"/remove", map[string]any{"reason": "again"}, mod.token); rr.Code != http.StatusNotFound {
+		t.Fatalf("remove twice status=%d", rr.Code)
+	}
+	var notified int64
+	app.DB.Model(&model.Notification{}).Where("agent_id = ? AND type = ?", author.id, model.NotificationTypeContentRemoved).Count(&notified)
+	if notified != 2 {
+		t.Fatalf("removal notifications=%d, want 2", notified)
+	}
+
+	// 审计列表：版主可查本社区，不指定社区需 Admin
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/removals?community=modq&status=active", nil, mod.token)
+	if rr.Code != http.StatusOK || asInt64(t, decodeJSON(t, rr)["total"]) != 2 {
+		t.Fatalf("list removals status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/moderation/removals", nil, mod.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("list all removals as moderator status=%d", rr.Code)
+	}
+
+	// 恢复帖子：重新可见并冲正扣分，重复恢复返回 409
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/removals/"+removalID+"/restore", nil, mod.token)
+	if rr.Code != http.StatusOK || decodeJSON(t, rr)["restored_at"] == nil {
+		t.Fatalf("restore status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/posts/"+postID, nil, ""); rr.Code != http.StatusOK {
+		t.Fatalf("restored post status=%d", rr.Code)
+	}
+	if got := points(); got != before-20 {
+		t.Fatalf("points after restore=%d, want %d", got, before-20)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/removals/"+removalID+"/restore", nil, mod.token); rr.Code != http.StatusConflict {
+		t.Fatalf("restore twice status=%d", rr.Code)
+	}
+
+	// 余额不足时扣分按实际扣除记录，恢复只返还实际扣除的积分
+	if err := app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("points", 5).Error; err != nil {
+		t.Fatalf("set author points: %v", err)
+	}
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/posts/"+postID+"/remove", map[string]any{"reason": "spam again"}, mod.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("remove again status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	body := decodeJSON(t, rr)
+	if got := asInt64(t, body["points_change"]); got != -5 {
+		t.Fatalf("clamped points_change=%d, want -5", got)
+	}
+	removalID = strconv.FormatInt(asInt64(t, body["id"]), 10)
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/removals/"+removalID+"/restore", nil, mod.token); rr.Code != http.StatusOK {
+		t.Fatalf("restore again status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := points(); got != 5 {
+		t.Fatalf("points after clamped restore=%d, want 5", got)
+	}
+}
@@ -0,0 +1,100 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+func TestPoints_HistoryAndSummary(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agent := seedVoteAgents(t, app, 1)[0]
+
+	today := time.Now().UTC().Truncate(24 * time.Hour).Add(time.Minute)
+	lastWeek := today.AddDate(0, 0, -7)
+	logs := []model.PointsLog{
+		{AgentID: agent.id, PointsChange: 100, Reason: model.PointsReasonAgentRegistered, CreatedAt: lastWeek},
+		{AgentID: agent.id, PointsChange: 10, Reason: model.PointsReasonPostCreated, CreatedAt: lastWeek},
+		{AgentID: agent.id, PointsChange: 10, Reason: model.PointsReasonPostCreated, CreatedAt: today},
+		{AgentID: agent.id, PointsChange: 10, Reason: model.PointsReasonPostCreated, CreatedAt: today},
+		{AgentID: agent.id, PointsChange: 5, Reason: model.PointsReasonCommentCreated, CreatedAt: today},
+		{AgentID: agent.id, PointsChange: -1, Reason: model.PointsReasonContentDownvoted, CreatedAt: today},
+	}
+	if err := app.DB.Create(&logs).Error; err != nil {
+		t.Fatalf("seed points logs: %v", err)
+	}
+	// 标记为今日已活跃，避免请求触发每日登录积分干扰核对
+	app.DB.Model(&model.Agent{}).Where("id = ?", agent.id).
+		Updates(map[string]any{"points": 134, "last_active_day": model.ActivityDay(time.Now())})
+
+	history := func(query string) (int, []any) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodGet, "/api/v1/me/points/history"+query, nil, agent.token)
+		if rr.Code != http.StatusOK {
+			return rr.Code, nil
+		}
+		body := decodeJSON(t, rr)
+		items := body["history"].([]any)
+		if int(asInt64(t, body["total"])) < len(items) {
+			t.Fatalf("total %v < page size %d", body["total"], len(items))
+		}
+		return rr.Code, items
+	}
+
+	if _, items := history(""); len(items) != 6 {
+		t.Fatalf("history len=%d, want 6", len(items))
+	}
+	_, items := history("?reason=post_created&limit=1")
+	if len(items) != 1 || items[0].(map[string]any)["reason"] != model.PointsReasonPostCreated {
+		t.Fatalf("filtered history=%v", items)
+	}
+	from := today.Format("2006-01-02")
+	if _, items := history("?from=" + from); len(items) != 4 {
+		t.Fatalf("history since today len=%d, want 4", len(items))
+	}
+	if _, items := history("?to=" + lastWeek.Format("2006-01-02")); len(items) != 2 {
+		t.Fatalf("history until last week len=%d, want 2", len(items))
+	}
+	if code, _ := history("?from=yesterday"); code != http.StatusBadRequest {
+		t.Fatalf("invalid from status=%d, want 400", code)
+	}
+	if code, _ := history("?from=" + from + "&to=" + lastWeek.Format("2006-01-02")); code != http.StatusBadRequest {
+		t.Fatalf("inverted range status=%d, want 400", code)
+	}
+
+	rr := doJSON(t, r, http.MethodGet, "/api/v1/me/points/summary", nil, agent.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("summary status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	summary := decodeJSON(t, rr)
+	if asInt64(t, summary["balance"]) != 134 {
+		t.Fatalf("balance=%v, want 134", summary["balance"])
+	}
+	todayBy := map[string]map[string]any{}
+	for _, e := range summary["today"].([]any) {
+		m := e.(map[string]any)
+		todayBy[m["reason"].(string)] = m
+	}
+	post := todayBy[model.PointsReasonPostCreated]
+	if post == nil || asInt64(t, post["points"]) != 20 || asInt64(t, post["daily_cap"]) != 50 || asInt64(t, post["remaining"]) != 30 {
+		t.Fatalf("today post_created=%v, want 20/50 remaining 30", post)
+	}
+	if login := todayBy[model.PointsReasonDailyLogin]; login == nil || asInt64(t, login["points"]) != 0 || asInt64(t, login["remaining"]) != 5 {
+		t.Fatalf("today daily_login=%v, want 0 remaining 5", login)
+	}
+	if down := todayBy[model.PointsReasonContentDownvoted]; down == nil || asInt64(t, down["points"]) != -1 || down["remaining"] != nil {
+		t.Fatalf("today content_downvoted=%v, want -1 without cap", down)
+	}
+	lifetime := map[string]int64{}
+	for _, e := range summary["lifetime"].([]any) {
+		m := e.(map[string]any)
+		lifetime[m["reason"].(string)] = asInt64(t, m["points"])
+	}
+	if lifetime[model.PointsReasonPostCreated] != 30 || lifetime[model.PointsReasonAgentRegistered] != 100 || len(lifetime) != 4 {
+		t.Fatalf("lifetime=%v", lifetime)
+	}
+}
@@ -0,0 +1,109 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+func TestPointsRules_ConfigureAndDryRun(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 2)
+	admin, author := agents[0], agents[1]
+
+	var adminAgent model.Agent
+	if err := app.DB.First(&adminAgent, admin.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, admin.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	// 作者标记为今日已活跃，积分只反映发帖
+	app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("last_active_day", model.ActivityDay(time.Now()))
+
+	// 仅 Admin 可管理规则；未知 reason 拒绝
+	rule := map[string]any{"reason": model.PointsReasonPostCreated, "points": 25, "daily_cap": 100}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/points/rules", rule, author.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("member create rule status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/points/rules", map[string]any{"reason": "vote_reversed", "points": 1}, adminToken); rr.Code != http.StatusBadRequest {
+		t.Fatalf("unknown reason status=%d", rr.Code)
+	}
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/points/rules", rule, adminToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create rule status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if created := decodeJSON(t, rr); created["active"] != true || asInt64(t, created["id"]) == 0 {
+		t.Fatalf("created rule=%v, want active", created)
+	}
+
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/admin/points/rules", nil, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list rules status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var postVersions, activePost int
+	for _, item := range decodeJSON(t, rr)["rules"].([]any) {
+		m := item.(map[string]any)
+		if m["reason"] != model.PointsReasonPostCreated {
+			continue
+		}
+		postVersions++
+		if m["active"] == true {
+			activePost++
+			if asInt64(t, m["points"]) != 25 {
+				t.Fatalf("active post_created rule=%v, want 25 points", m)
+			}
+		}
+	}
+	if postVersions != 2 || activePost != 1 {
+		t.Fatalf("post_created versions=%d active=%d, want 2 versions with 1 active", postVersions, activePost)
+	}
+
+	// 新规则立即用于实时计分
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "rules"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{
+		"community_id": communityID, "title": "Tuning the points economy", "content": "Notes on how rule changes shift agent balances over time.",
+	}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var stored model.Agent
+	if err := app.DB.First(&stored, author.id).Error; err != nil {
+		t.Fatalf("load author: %v", err)
+	}
+	if stored.Points != 25 {
+		t.Fatalf("author points=%d, want 25", stored.Points)
+	}
+
+	// 试算：发帖不再计分时作者余额回到 0，数据不变
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/admin/points/rules/dry-run", map[string]any{
+		"rules": []map[string]any{{"reason": model.PointsReasonPostCreated, "points": 0}},
+	}, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("dry run status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	res := decodeJSON(t, rr)
+	changed := res["agents"].([]any)
+	if asInt64(t, res["agents_changed"]) != 1 || len(changed) != 1 {
+		t.Fatalf("dry run result=%v, want one changed agent", res)
+	}
+	got := changed[0].(map[string]any)
+	if asInt64(t, got["agent_id"]) != author.id || asInt64(t, got["baseline"]) != 25 || asInt64(t, got["proposed"]) != 0 || asInt64(t, got["delta"]) != -25 {
+		t.Fatalf("dry run agent=%v, want baseline 25 proposed 0", got)
+	}
+	if err := app.DB.First(&stored, author.id).Error; err != nil || stored.Points != 25 {
+		t.Fatalf("dry run must not change points: %d %v", stored.Points, err)
+	}
+}
@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+func TestQuality_WithholdPointsAndFlagDuplicates(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 2)
+	admin, author := agents[0], agents[1]
+
+	var adminAgent model.Agent
+	if err := app.DB.First(&adminAgent, admin.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, admin.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "quality"}, admin.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+
+	points := func() int {
+		t.Helper()
+		var a model.Agent
+		if err := app.DB.First(&a, author.id).Error; err != nil {
+			t.Fatalf("load agent: %v", err)
+		}
+		return a.Points
+	}
+	createPost := func(title, content string) int64 {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": title, "content": content}, author.token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		return asInt64(t, decodeJSON(t, rr)["id"])
+	}
+
+	// 正常内容加分
+	before := points()
+	postID := createPost("Karma decay", "Ranking agents by karma alone rewards volume over insight. I think recent upvotes should weigh more.")
+	if got := points(); got <= before {
+		t.Fatalf("points after normal post=%d, want > %d", got, before)
+	}
+
+	// 近似重复：照常发布但不加分，并以系统身份进入审核队列
+	before = points()
+	dupID := createPost("karma decay!", "Ranking agents by karma alone rewards volume over insight. I think recent upvotes should weigh more!!")
+	if got := points(); got != before {
+		t.Fatalf("points after duplicate=%d, want %d", got, before)
+	}
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?target_type=post", nil, adminToken)
+	reports := decodeJSON(t, rr)["reports"].([]any)
+	if len(reports) != 1 {
+		t.Fatalf("reports=%v, want 1 system report", reports)
+	}
+	report := reports[0].(map[string]any)
+	if asInt64(t, report["target_id"]) != dupID || asInt64(t, report["reporter_agent_id"]) != 0 || report["category"] != model.ReportCategoryLowQuality {
+		t.Fatalf("system report=%v", report)
+	}
+
+	// 编辑帖子同样查重：小改不与帖子自身比较，改成与其他帖子重复的内容则送审
+	updatePost := func(id int64, content string) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPut, "/api/v1/posts/"+strconv.FormatInt(id, 10), map[string]any{"content": content}, author.token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("update post status=%d body=%s", rr.Code, rr.Body.String())
+		}
+	}
+	otherID := createPost("Vector index", "Has anyone benchmarked the new vector index? Queries got slower after the upgrade for me.")
+	updatePost(otherID, "Has anyone benchmarked the new vector index? Queries got much slower after the upgrade for me!")
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?target_type=post", nil, adminToken)
+	if got := len(decodeJSON(t, rr)["reports"].([]any)); got != 1 {
+		t.Fatalf("reports after editing own post=%d, want 1", got)
+	}
+	updatePost(otherID, "Ranking agents by karma alone rewards volume over insight. I think recent upvotes should weigh more.")
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?target_type=post", nil, adminToken)
+	found := false
+	for _, item := range decodeJSON(t, rr)["reports"].([]any) {
+		if asInt64(t, item.(map[string]any)["target_id"]) == otherID {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("edited duplicate post %d was not flagged", otherID)
+	}
+
+	// 大量重复的评论：不加分，也不送审
+	before = points()
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts/"+strconv.FormatInt(postID, 10)+"/comments",
+		map[string]any{"content": "great post great post great post great post"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create comment status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := points(); got != before {
+		t.Fatalf("points after repetitive comment=%d, want %d", got, before)
+	}
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?target_type=comment", nil, adminToken)
+	if got := len(decodeJSON(t, rr)["reports"].([]any)); got != 0 {
+		t.Fatalf("comment reports=%d, want 0", got)
+	}
+}
@@ -0,0 +1,60 @@
+package integration_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	rankingRepo "agent-hub/internal/ranking/repository"
+	"agent-hub/internal/testutil"
+)
+
+func TestRankingRepository_SampleActivePostIDs(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	ctx := context.Background()
+	repo := rankingRepo.NewRankingRepository(app.DB)
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+	agent := seedVoteAgents(t, app, 1)[0]
+	old := time.Now().AddDate(0, -1, 0)
+	posts := make([]*model.Post, 4)
+	for i := range posts {
+		posts[i] = &model.Post{AgentID: agent.id, CommunityID: community.ID, Title: "old", CreatedAt: old, UpdatedAt: old}
+		if err := app.DB.Create(posts[i]).Error; err != nil {
+			t.Fatalf("create post: %v", err)
+		}
+	}
+	// 保留自定义的 updated_at
+	app.DB.Model(&model.Post{}).Where("id IN ?", []int64{posts[0].ID, posts[1].ID, posts[2].ID, posts[3].ID}).UpdateColumn("updated_at", old)
+	since := time.Now().Add(-time.Hour)
+
+	// 窗口内无活动：退回最新的 n 篇
+	ids, err := repo.SampleActivePostIDs(ctx, since, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) != 2 || ids[0] != posts[2].ID || ids[1] != posts[3].ID {
+		t.Fatalf("fallback sample=%v, want newest two posts", ids)
+	}
+
+	// 被评论的旧帖算作活跃；已删除帖子的评论不计
+	for _, p := range []*model.Post{posts[0], posts[1]} {
+		if err := app.DB.Create(&model.Comment{AgentID: agent.id, PostID: p.ID, Content: "recent"}).Error; err != nil {
+			t.Fatalf("create comment: %v", err)
+		}
+	}
+	app.DB.Delete(&model.Post{}, posts[1].ID)
+	ids, err = repo.SampleActivePostIDs(ctx, since, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != posts[0].ID {
+		t.Fatalf("active sample=%v, want [%d]", ids, posts[0].ID)
+	}
+}
@@ -0,0 +1,104 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+func TestRBAC_ModeratorsAndRoles(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 3)
+	creator, mod, member := agents[0], agents[1], agents[2]
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "rbac"}, creator.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+
+	// 只有社区创建者（或 Admin）能任命版主
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/communities/rbac/moderators", map[string]any{"agent_name": "voter2"}, member.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("member add moderator status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/communities/rbac/moderators", map[string]any{"agent_name": "voter1"}, creator.token); rr.Code != http.StatusCreated {
+		t.Fatalf("add moderator status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/communities/rbac/moderators", nil, "")
+	mods := decodeJSON(t, rr)["moderators"].([]any)
+	if len(mods) != 1 || asInt64(t, mods[0].(map[string]any)["agent_id"]) != mod.id {
+		t.Fatalf("moderators=%v, want [voter1]", mods)
+	}
+
+	createPost := func(token string) string {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "hello"}, token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		return "/api/v1/posts/" + strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+	}
+
+	// 版主不能编辑或直接删除他人的帖子，须经治理接口移除（留审计记录）
+	postPath := createPost(member.token)
+	if rr := doJSON(t, r, http.MethodPut, postPath, map[string]any{"title": "edited"}, mod.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("moderator edit status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodDelete, postPath, nil, creator.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("non-moderator delete status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodDelete, postPath, nil, mod.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("moderator plain delete status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation"+postPath[len("/api/v1"):]+"/remove", map[string]any{"reason": "spam"}, mod.token); rr.Code != http.StatusCreated {
+		t.Fatalf("moderator remove status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, r, http.MethodDelete, createPost(member.token), nil, member.token); rr.Code != http.StatusNoContent {
+		t.Fatalf("author delete status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// 角色任免仅限 Owner；Admin 也不能直接删除他人的帖子
+	var memberAgent model.Agent
+	if err := app.DB.First(&memberAgent, member.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	rolePath := "/api/v1/admin/users/" + strconv.FormatInt(memberAgent.UserID, 10) + "/role"
+	if rr := doJSON(t, r, http.MethodPut, rolePath, map[string]any{"role": "admin"}, creator.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("member set role status=%d", rr.Code)
+	}
+
+	var creatorAgent model.Agent
+	if err := app.DB.First(&creatorAgent, creator.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	app.DB.Model(&model.User{}).Where("id = ?", creatorAgent.UserID).Update("role", model.RoleOwner)
+	ownerToken, err := jwt.Generate(app.JWTSecret, creatorAgent.UserID, creator.id, model.RoleOwner, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	if rr := doJSON(t, r, http.MethodPut, rolePath, map[string]any{"role": "owner"}, ownerToken); rr.Code != http.StatusBadRequest {
+		t.Fatalf("grant owner status=%d", rr.Code)
+	}
+	rr = doJSON(t, r, http.MethodPut, rolePath, map[string]any{"role": "admin"}, ownerToken)
+	if rr.Code != http.StatusOK || decodeJSON(t, rr)["role"] != model.RoleAdmin {
+		t.Fatalf("grant admin status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	adminToken, err := jwt.Generate(app.JWTSecret, memberAgent.UserID, member.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	postPath = createPost(mod.token)
+	if rr := doJSON(t, r, http.MethodDelete, postPath, nil, adminToken); rr.Code != http.StatusForbidden {
+		t.Fatalf("admin plain delete status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	ownerPath := "/api/v1/admin/users/" + strconv.FormatInt(creatorAgent.UserID, 10) + "/role"
+	if rr := doJSON(t, r, http.MethodPut, ownerPath, map[string]any{"role": "member"}, ownerToken); rr.Code != http.StatusForbidden {
+		t.Fatalf("demote owner status=%d", rr.Code)
+	}
+}
@@ -0,0 +1,182 @@
+package integration_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"agent-hub/internal/model"
+	moderationRepo "agent-hub/internal/moderation/repository"
+	moderationService "agent-hub/internal/moderation/service"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+type reportResult struct {
+	code int
+	id   string
+}
+
+func TestReports_QueueAndResolve(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 4)
+	creator, mod, author, reporter := agents[0], agents[1], agents[2], agents[3]
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "reports"}, creator.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/communities/reports/moderators", map[string]any{"agent_name": "voter1"}, creator.token); rr.Code != http.StatusCreated {
+		t.Fatalf("add moderator status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "farm"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	postID := asInt64(t, decodeJSON(t, rr)["id"])
+
+	report := func(token, targetType string, targetID int64, category string) *reportResult {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/reports",
+			map[string]any{"target_type": targetType, "target_id": targetID, "category": category}, token)
+		out := &reportResult{code: rr.Code}
+		if rr.Code == http.StatusCreated {
+			out.id = strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+		}
+		return out
+	}
+
+	// 同一举报者对同一对象只能举报一次；不能举报自己；分类须合法
+	postReport := report(reporter.token, "post", postID, "spam")
+	if postReport.code != http.StatusCreated {
+		t.Fatalf("report post status=%d", postReport.code)
+	}
+	if got := report(reporter.token, "post", postID, "abuse").code; got != http.StatusConflict {
+		t.Fatalf("duplicate report status=%d, want 409", got)
+	}
+	if got := report(author.token, "post", postID, "spam").code; got != http.StatusBadRequest {
+		t.Fatalf("self report status=%d, want 400", got)
+	}
+	if got := report(creator.token, "post", postID, "boring").code; got != http.StatusBadRequest {
+		t.Fatalf("invalid category status=%d, want 400", got)
+	}
+	if got := report(creator.token, "post", postID, "low_quality").code; got != http.StatusCreated {
+		t.Fatalf("second reporter status=%d", got)
+	}
+	agentReport := report(reporter.token, "agent", author.id, "point_farming")
+	if agentReport.code != http.StatusCreated {
+		t.Fatalf("report agent status=%d", agentReport.code)
+	}
+
+	// 版主只能看到本社区的队列
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?community=reports&status=open", nil, mod.token)
+	if rr.Code != http.StatusOK || asInt64(t, decodeJSON(t, rr)["total"]) != 2 {
+		t.Fatalf("moderator queue status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports", nil, mod.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("moderator global queue status=%d", rr.Code)
+	}
+
+	// 版主可结案但不能处罚；结案后同一对象的其余举报一并关闭
+	resolvePath := "/api/v1/moderation/reports/" + postReport.id + "/resolve"
+	if rr := doJSON(t, r, http.MethodPost, resolvePath, map[string]any{"status": "actioned", "action": "reset_points"}, mod.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("moderator sanction status=%d", rr.Code)
+	}
+	rr = doJSON(t, r, http.MethodPost, resolvePath, map[string]any{"status": "dismissed", "note": "not spam"}, mod.token)
+	if rr.Code != http.StatusOK || decodeJSON(t, rr)["status"] != model.ReportStatusDismissed {
+		t.Fatalf("resolve status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?community=reports&status=open", nil, mod.token)
+	if got := asInt64(t, decodeJSON(t, rr)["total"]); got != 0 {
+		t.Fatalf("open reports after resolve=%d, want 0", got)
+	}
+	if rr := doJSON(t, r, http.MethodPost, resolvePath, map[string]any{"status": "actioned"}, mod.token); rr.Code != http.StatusConflict {
+		t.Fatalf("resolve twice status=%d", rr.Code)
+	}
+
+	// Admin 核实刷分：积分清零并封禁
+	var authorAgent, adminAgent model.Agent
+	app.DB.First(&authorAgent, author.id)
+	app.DB.First(&adminAgent, creator.id)
+	app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("points", 50)
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, creator.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/reports/"+agentReport.id+"/resolve",
+		map[string]any{"status": "actioned", "action": "reset_points", "note": "vote ring"}, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("reset points status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	app.DB.First(&authorAgent, author.id)
+	if authorAgent.Points != 0 {
+		t.Fatalf("points after reset=%d, want 0", authorAgent.Points)
+	}
+	var logCount int64
+	app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ? AND points_change = ?", author.id, model.PointsReasonPointsReset, -50).Count(&logCount)
+	if logCount != 1 {
+		t.Fatalf("reset log count=%d, want 1", logCount)
+	}
+
+	banReport := report(mod.token, "agent", author.id, "abuse")
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/moderation/reports/"+banReport.id+"/resolve",
+		map[string]any{"status": "actioned", "action": "suspend", "suspend_hours": 8761}, adminToken); rr.Code != http.StatusBadRequest {
+		t.Fatalf("suspend beyond max hours status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/moderation/reports/"+banReport.id+"/resolve",
+		map[string]any{"status": "actioned", "action": "suspend", "suspend_hours": 24}, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("suspend status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var sus model.AgentSuspension
+	if err := app.DB.Where("agent_id = ?", author.id).First(&sus).Error; err != nil || sus.EndsAt == nil {
+		t.Fatalf("suspension should be recorded with an end time: %v", err)
+	}
+}
+
+func TestSystemFlagger_MergesAndReopens(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	ctx := context.Background()
+	flagger := moderationService.NewSystemFlagger(moderationRepo.NewReportRepository(app.DB))
+	load := func() model.Report {
+		t.Helper()
+		var reports []model.Report
+		if err := app.DB.Where("reporter_agent_id = ? AND target_type = ? AND target_id = ?",
+			model.SystemReporterID, model.ReportTargetPost, 42).Find(&reports).Error; err != nil {
+			t.Fatalf("load reports: %v", err)
+		}
+		if len(reports) != 1 {
+			t.Fatalf("system reports=%d, want 1", len(reports))
+		}
+		return reports[0]
+	}
+
+	// 待处理时，敏感词与质量检测的原因合并到同一条举报
+	if err := flagger.Flag(ctx, model.ReportTargetPost, 42, 7, 1, model.ReportCategoryLowQuality, "duplicate"); err != nil {
+		t.Fatalf("flag: %v", err)
+	}
+	if err := flagger.Flag(ctx, model.ReportTargetPost, 42, 7, 1, model.ReportCategoryOther, "sensitive: foo"); err != nil {
+		t.Fatalf("flag: %v", err)
+	}
+	r := load()
+	if r.Status != model.ReportStatusOpen || r.Detail == nil || !strings.Contains(*r.Detail, "duplicate") || !strings.Contains(*r.Detail, "sensitive: foo") {
+		t.Fatalf("merged report=%+v detail=%v", r, r.Detail)
+	}
+
+	// 驳回后再次命中：重新进入待处理队列
+	if err := app.DB.Model(&model.Report{}).Where("id = ?", r.ID).Update("status", model.ReportStatusDismissed).Error; err != nil {
+		t.Fatalf("dismiss: %v", err)
+	}
+	if err := flagger.Flag(ctx, model.ReportTargetPost, 42, 7, 1, model.ReportCategoryOther, "sensitive: bar"); err != nil {
+		t.Fatalf("flag: %v", err)
+	}
+	r = load()
+	if r.Status != model.ReportStatusOpen || r.Category != model.ReportCategoryOther || r.Detail == nil || *r.Detail != "sensitive: bar" {
+		t.Fatalf("reopened report=%+v detail=%v", r, r.Detail)
+	}
+}
@@ -0,0 +1,93 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	pkgerrors "agent-hub/pkg/errors"
+	"agent-hub/pkg/jwt"
+)
+
+// 测试应用的词表：scamcoin 拒绝、darn 屏蔽、airdrop 送审
+func TestSensitive_RejectMaskAndFlag(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 2)
+	admin, author := agents[0], agents[1]
+
+	var adminAgent model.Agent
+	if err := app.DB.First(&adminAgent, admin.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, admin.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "words"}, admin.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+
+	// 拒绝：帖子、社区名、Agent 名称与简介
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "Buy ScamCoin now"}, author.token)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("reject post status=%d", rr.Code)
+	}
+	if code := decodeJSON(t, rr)["error"].(map[string]any)["code"]; code != pkgerrors.CodeSensitiveContent {
+		t.Fatalf("error code=%v, want %s", code, pkgerrors.CodeSensitiveContent)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "darn_club"}, author.token); rr.Code != http.StatusBadRequest {
+		t.Fatalf("community name status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPut, "/api/v1/me/agent", map[string]any{"bio": "scamcoin maxi"}, author.token); rr.Code != http.StatusBadRequest {
+		t.Fatalf("bio status=%d", rr.Code)
+	}
+
+	// 屏蔽：命中部分替换为 *
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "Darn good idea"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	post := decodeJSON(t, rr)
+	if post["title"] != "**** good idea" {
+		t.Fatalf("masked title=%v", post["title"])
+	}
+	rr = doJSON(t, r, http.MethodPut, "/api/v1/me/agent", map[string]any{"bio": "darn fine agent"}, author.token)
+	if rr.Code != http.StatusOK || decodeJSON(t, rr)["bio"] != "**** fine agent" {
+		t.Fatalf("masked bio status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// 送审：照常写入，以系统身份进入审核队列
+	postPath := "/api/v1/posts/" + strconv.FormatInt(asInt64(t, post["id"]), 10)
+	rr = doJSON(t, r, http.MethodPost, postPath+"/comments", map[string]any{"content": "Join the AIRDROP before it ends tonight"}, author.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create comment status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	commentID := asInt64(t, decodeJSON(t, rr)["id"])
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/moderation/reports?target_type=comment", nil, adminToken)
+	reports := decodeJSON(t, rr)["reports"].([]any)
+	if len(reports) != 1 {
+		t.Fatalf("reports=%v, want 1 system report", reports)
+	}
+	report := reports[0].(map[string]any)
+	if asInt64(t, report["target_id"]) != commentID || asInt64(t, report["reporter_agent_id"]) != 0 || report["category"] != model.ReportCategoryOther {
+		t.Fatalf("system report=%v", report)
+	}
+
+	// 新 Agent 的名称命中任一词表都拒绝
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/auth/register",
+		map[string]any{"username": "fresh", "email": "fresh@example.com", "password": "secret1"}, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("register status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	fresh := decodeJSON(t, rr)["token"].(string)
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/agents", map[string]any{"name": "airdrop_bot"}, fresh); rr.Code != http.StatusBadRequest {
+		t.Fatalf("agent name status=%d", rr.Code)
+	}
+}
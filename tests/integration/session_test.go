@@ -0,0 +1,126 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+)
+
+type refreshResult struct {
+	code           int
+	token, refresh string
+}
+
+func TestSession_RefreshLogoutAndPasswordChange(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/register",
+		map[string]any{"username": "sess", "email": "sess@example.com", "password": "secret1"}, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("register status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	reg := decodeJSON(t, rr)
+	if reg["refresh_token"] == "" || asInt64(t, reg["expires_in"]) <= 0 {
+		t.Fatalf("register should return refresh_token and expires_in: %v", reg)
+	}
+
+	login := func(password string) (string, string) {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/login",
+			map[string]any{"email": "sess@example.com", "password": password}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("login status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		body := decodeJSON(t, rr)
+		return body["token"].(string), body["refresh_token"].(string)
+	}
+	refresh := func(refreshToken string) refreshResult {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/refresh", map[string]any{"refresh_token": refreshToken}, "")
+		out := refreshResult{code: rr.Code}
+		if rr.Code == http.StatusOK {
+			body := decodeJSON(t, rr)
+			out.token, out.refresh = body["token"].(string), body["refresh_token"].(string)
+		}
+		return out
+	}
+	meStatus := func(token string) int {
+		return doJSON(t, r, http.MethodGet, "/api/v1/me/relationships?agent_ids=1", nil, token).Code
+	}
+
+	// 创建 Agent 后刷新得到的 access token 带上新的 agent_id
+	access, refreshToken := login("secret1")
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/agents", map[string]any{"name": "sess_agent"}, access)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create agent status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rotated := refresh(refreshToken)
+	if rotated.code != http.StatusOK || rotated.refresh == refreshToken {
+		t.Fatalf("refresh status=%d, want rotated token", rotated.code)
+	}
+	rr = doJSON(t, r, http.MethodPut, "/api/v1/me/agent", map[string]any{"bio": "refreshed"}, rotated.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update agent with refreshed token status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// 重放已轮换的 refresh token：吊销整个会话
+	if got := refresh(refreshToken).code; got != http.StatusUnauthorized {
+		t.Fatalf("reused refresh status=%d, want 401", got)
+	}
+	if got := refresh(rotated.refresh).code; got != http.StatusUnauthorized {
+		t.Fatalf("refresh after reuse status=%d, want 401", got)
+	}
+	if got := meStatus(rotated.token); got != http.StatusUnauthorized {
+		t.Fatalf("access token after reuse status=%d, want 401", got)
+	}
+
+	// 登出只影响当前会话
+	accessA, refreshA := login("secret1")
+	accessB, refreshB := login("secret1")
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/auth/logout", nil, accessA); rr.Code != http.StatusOK {
+		t.Fatalf("logout status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := meStatus(accessA); got != http.StatusUnauthorized {
+		t.Fatalf("access after logout status=%d, want 401", got)
+	}
+	if got := refresh(refreshA).code; got != http.StatusUnauthorized {
+		t.Fatalf("refresh after logout status=%d, want 401", got)
+	}
+	if got := meStatus(accessB); got != http.StatusOK {
+		t.Fatalf("other session status=%d, want 200", got)
+	}
+
+	// 修改密码吊销全部会话，返回的新令牌可用
+	rr = doJSON(t, r, http.MethodPut, "/api/v1/me/password",
+		map[string]any{"old_password": "wrong", "new_password": "secret2"}, accessB)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("wrong old password status=%d", rr.Code)
+	}
+	rr = doJSON(t, r, http.MethodPut, "/api/v1/me/password",
+		map[string]any{"old_password": "secret1", "new_password": "secret2"}, accessB)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("change password status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	fresh := decodeJSON(t, rr)["token"].(string)
+	if got := meStatus(accessB); got != http.StatusUnauthorized {
+		t.Fatalf("old access after password change status=%d, want 401", got)
+	}
+	if got := refresh(refreshB).code; got != http.StatusUnauthorized {
+		t.Fatalf("old refresh after password change status=%d, want 401", got)
+	}
+	if got := meStatus(fresh); got != http.StatusOK {
+		t.Fatalf("new access status=%d, want 200", got)
+	}
+	login("secret2")
+
+	var stored model.RefreshToken
+	if err := app.DB.Where("user_id = ?", asInt64(t, reg["user_id"])).First(&stored).Error; err != nil {
+		t.Fatalf("load refresh token: %v", err)
+	}
+	if stored.TokenHash == reg["refresh_token"] {
+		t.Fatal("refresh token must be stored hashed")
+	}
+}
@@ -0,0 +1,131 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+func TestSuspension_ScopesAndExpiry(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+	r := app.Router
+	agents := seedVoteAgents(t, app, 3)
+	admin, target, other := agents[0], agents[1], agents[2]
+
+	var adminAgent model.Agent
+	if err := app.DB.First(&adminAgent, admin.id).Error; err != nil {
+		t.Fatalf("load agent: %v", err)
+	}
+	app.DB.Model(&model.User{}).Where("id = ?", adminAgent.UserID).Update("role", model.RoleAdmin)
+	adminToken, err := jwt.Generate(app.JWTSecret, adminAgent.UserID, admin.id, model.RoleAdmin, "", app.AccessTTL)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	rr := doJSON(t, r, http.MethodPost, "/api/v1/communities", map[string]any{"name": "susp"}, other.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create community status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	communityID := asInt64(t, decodeJSON(t, rr)["id"])
+	rr = doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "hi"}, other.token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create post status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	votePath := "/api/v1/posts/" + strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10) + "/vote"
+
+	suspend := func(scope string, hours int) string {
+		t.Helper()
+		rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/agents/voter1/suspensions",
+			map[string]any{"scope": scope, "reason": "abuse", "hours": hours}, adminToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("suspend %s status=%d body=%s", scope, rr.Code, rr.Body.String())
+		}
+		return strconv.FormatInt(asInt64(t, decodeJSON(t, rr)["id"]), 10)
+	}
+	postStatus := func() int {
+		return doJSON(t, r, http.MethodPost, "/api/v1/posts", map[string]any{"community_id": communityID, "title": "again"}, target.token).Code
+	}
+	profile := func() map[string]any {
+		t.Helper()
+		return decodeJSON(t, doJSON(t, r, http.MethodGet, "/api/v1/agents/voter1", nil, ""))
+	}
+
+	// 仅 Admin 可封禁，且不能封禁自己
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/agents/voter0/suspensions", map[string]any{"scope": "ban", "reason": "x"}, other.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("member suspend status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/agents/voter0/suspensions", map[string]any{"scope": "ban", "reason": "x"}, adminToken); rr.Code != http.StatusForbidden {
+		t.Fatalf("self suspend status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/admin/agents/voter1/suspensions", map[string]any{"scope": "ban", "reason": "x", "hours": 8761}, adminToken); rr.Code != http.StatusBadRequest {
+		t.Fatalf("suspend beyond max hours status=%d", rr.Code)
+	}
+	// 解除同样受角色约束：不能解除自己的封禁
+	selfSus := &model.AgentSuspension{AgentID: admin.id, Scope: model.SuspensionScopeNoVote, Reason: "x", StartsAt: time.Now(), CreatedByUserID: adminAgent.UserID}
+	if err := app.DB.Create(selfSus).Error; err != nil {
+		t.Fatalf("create suspension: %v", err)
+	}
+	if rr := doJSON(t, r, http.MethodDelete, "/api/v1/admin/suspensions/"+strconv.FormatInt(selfSus.ID, 10), nil, adminToken); rr.Code != http.StatusForbidden {
+		t.Fatalf("self lift status=%d", rr.Code)
+	}
+
+	// 禁止投票：仍可发帖
+	noVote := suspend(model.SuspensionScopeNoVote, 24)
+	if rr := doJSON(t, r, http.MethodPost, votePath, map[string]any{"vote_type": 1}, target.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("vote while no_vote status=%d", rr.Code)
+	}
+	if got := postStatus(); got != http.StatusCreated {
+		t.Fatalf("post while no_vote status=%d", got)
+	}
+	if p := profile(); p["suspended"] != true || p["suspension_scope"] != model.SuspensionScopeNoVote || p["suspended_until"] == nil {
+		t.Fatalf("profile while no_vote=%v", p)
+	}
+
+	// 只读：禁止发帖与关注；解除后恢复
+	readOnly := suspend(model.SuspensionScopeReadOnly, 0)
+	if got := postStatus(); got != http.StatusForbidden {
+		t.Fatalf("post while read_only status=%d", got)
+	}
+	if rr := doJSON(t, r, http.MethodPost, "/api/v1/agents/voter2/follow", map[string]any{"follow": true}, target.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("follow while read_only status=%d", rr.Code)
+	}
+	if p := profile(); p["suspension_scope"] != model.SuspensionScopeReadOnly || p["suspended_until"] != nil {
+		t.Fatalf("profile should show the strictest suspension: %v", p)
+	}
+	if rr := doJSON(t, r, http.MethodDelete, "/api/v1/admin/suspensions/"+readOnly, nil, adminToken); rr.Code != http.StatusOK {
+		t.Fatalf("lift status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := postStatus(); got != http.StatusCreated {
+		t.Fatalf("post after lift status=%d", got)
+	}
+	if rr := doJSON(t, r, http.MethodDelete, "/api/v1/admin/suspensions/"+readOnly, nil, adminToken); rr.Code != http.StatusConflict {
+		t.Fatalf("lift twice status=%d", rr.Code)
+	}
+
+	// 完全封禁：任何认证请求都被拒绝；到期后自动恢复
+	ban := suspend(model.SuspensionScopeBan, 1)
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/me/feed", nil, target.token); rr.Code != http.StatusForbidden {
+		t.Fatalf("feed while banned status=%d", rr.Code)
+	}
+	past := time.Now().Add(-time.Minute)
+	app.DB.Model(&model.AgentSuspension{}).Where("id IN ?", []string{ban, noVote}).Update("ends_at", past)
+	if rr := doJSON(t, r, http.MethodGet, "/api/v1/me/feed", nil, target.token); rr.Code != http.StatusOK {
+		t.Fatalf("feed after expiry status=%d", rr.Code)
+	}
+	if rr := doJSON(t, r, http.MethodPost, votePath, map[string]any{"vote_type": 1}, target.token); rr.Code != http.StatusOK {
+		t.Fatalf("vote after expiry status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if p := profile(); p["suspended"] != nil {
+		t.Fatalf("profile after expiry=%v", p)
+	}
+
+	rr = doJSON(t, r, http.MethodGet, "/api/v1/admin/agents/voter1/suspensions", nil, adminToken)
+	if rr.Code != http.StatusOK || len(decodeJSON(t, rr)["suspensions"].([]any)) != 3 {
+		t.Fatalf("list suspensions status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
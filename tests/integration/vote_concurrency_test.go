@@ -0,0 +1,189 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"agent-hub/internal/model"
+	"agent-hub/internal/testutil"
+	"agent-hub/pkg/jwt"
+)
+
+// voteAgent 并发测试中的投票者
+type voteAgent struct {
+	id    int64
+	token string
+}
+
+// seedVoteAgents 直接写库创建 n 个带 Agent 的用户并签发 token，跳过注册接口以缩短测试时间
+func seedVoteAgents(t *testing.T, app *testutil.MySQLTestApp, n int) []voteAgent {
+	t.Helper()
+	agents := make([]voteAgent, n)
+	for i := 0; i < n; i++ {
+		u := &model.User{
+			Username:     fmt.Sprintf("voter%d", i),
+			Email:        fmt.Sprintf("voter%d@example.com", i),
+			PasswordHash: "x",
+		}
+		if err := app.DB.Create(u).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		a := &model.Agent{UserID: u.ID, Name: fmt.Sprintf("voter%d", i)}
+		if err := app.DB.Create(a).Error; err != nil {
+			t.Fatalf("create agent: %v", err)
+		}
+		token, err := jwt.Generate(app.JWTSecret, u.ID, a.ID, model.RoleMember, "", app.AccessTTL)
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		agents[i] = voteAgent{id: a.ID, token: token}
+	}
+	return agents
+}
+
+// vote 发起投票请求（可在任意 goroutine 调用，不使用 t.Fatal）
+func vote(r http.Handler, path string, voteType int, token string) (int, string) {
+	body, _ := json.Marshal(map[string]any{"vote_type": voteType})
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr.Code, rr.Body.String()
+}
+
+// hammer 每个投票者开 perAgent 个 goroutine 同时投票，赞踩交替，覆盖「新投票」「改票」「重复投票」的并发交错
+func hammer(t *testing.T, r http.Handler, path string, agents []voteAgent, perAgent int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan string, len(agents)*perAgent)
+	start := make(chan struct{})
+	for _, a := range agents {
+		for j := 0; j < perAgent; j++ {
+			voteType := model.VoteTypeUpvote
+			if j%2 == 1 {
+				voteType = model.VoteTypeDownvote
+			}
+			wg.Add(1)
+			go func(token string, voteType int) {
+				defer wg.Done()
+				<-start
+				if code, body := vote(r, path, voteType, token); code != http.StatusOK {
+					errs <- fmt.Sprintf("status=%d body=%s", code, body)
+				}
+			}(a.token, voteType)
+		}
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Errorf("vote %s: %s", path, e)
+	}
+}
+
+// voteTally 按 votes 表统计某目标的赞/踩数，并检查每个 Agent 至多一条记录
+func voteTally(t *testing.T, app *testutil.MySQLTestApp, targetID int64, targetType string) (ups, downs int) {
+	t.Helper()
+	var votes []model.Vote
+	if err := app.DB.Where("target_id = ? AND target_type = ?", targetID, targetType).Find(&votes).Error; err != nil {
+		t.Fatalf("load votes: %v", err)
+	}
+	seen := make(map[int64]bool, len(votes))
+	for _, v := range votes {
+		if seen[v.AgentID] {
+			t.Fatalf("agent %d has duplicate %s votes on %d", v.AgentID, targetType, targetID)
+		}
+		seen[v.AgentID] = true
+		if v.VoteType == model.VoteTypeUpvote {
+			ups++
+		} else {
+			downs++
+		}
+	}
+	return ups, downs
+}
+
+func TestVote_ConcurrentCountersMatchVotes(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+
+	const voters, perAgent = 20, 6
+	agents := seedVoteAgents(t, app, voters+1)
+	author, voterAgents := agents[0], agents[1:]
+	// 作者初始积分足够高，避免扣分时被 0 下限截断，便于与积分日志对账
+	const authorBasePoints = 1000
+	if err := app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("points", authorBasePoints).Error; err != nil {
+		t.Fatalf("set author points: %v", err)
+	}
+
+	post := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "concurrency"}
+	if err := app.DB.Create(post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	comment := &model.Comment{AgentID: author.id, PostID: post.ID, Content: "a comment that collects concurrent votes"}
+	if err := app.DB.Create(comment).Error; err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+
+	hammer(t, app.Router, "/api/v1/posts/"+strconv.FormatInt(post.ID, 10)+"/vote", voterAgents, perAgent)
+	hammer(t, app.Router, "/api/v1/comments/"+strconv.FormatInt(comment.ID, 10)+"/vote", voterAgents, perAgent)
+
+	// 帖子计数与 votes 表一致
+	{
+		ups, downs := voteTally(t, app, post.ID, model.VoteTargetPost)
+		if ups+downs != voters {
+			t.Fatalf("post votes rows=%d, want %d", ups+downs, voters)
+		}
+		var got model.Post
+		if err := app.DB.First(&got, post.ID).Error; err != nil {
+			t.Fatalf("reload post: %v", err)
+		}
+		if got.Upvotes != ups || got.Downvotes != downs || got.NetVotes != ups-downs {
+			t.Fatalf("post counters up=%d down=%d net=%d, votes table up=%d down=%d",
+				got.Upvotes, got.Downvotes, got.NetVotes, ups, downs)
+		}
+	}
+
+	// 评论计数与 votes 表一致
+	{
+		ups, downs := voteTally(t, app, comment.ID, model.VoteTargetComment)
+		if ups+downs != voters {
+			t.Fatalf("comment votes rows=%d, want %d", ups+downs, voters)
+		}
+		var got model.Comment
+		if err := app.DB.First(&got, comment.ID).Error; err != nil {
+			t.Fatalf("reload comment: %v", err)
+		}
+		if got.Upvotes != ups || got.Downvotes != downs || got.NetVotes != ups-downs {
+			t.Fatalf("comment counters up=%d down=%d net=%d, votes table up=%d down=%d",
+				got.Upvotes, got.Downvotes, got.NetVotes, ups, downs)
+		}
+	}
+
+	// 作者积分与积分日志一致（积分变动与投票在同一事务内提交）
+	{
+		var logSum int
+		if err := app.DB.Model(&model.PointsLog{}).Select("COALESCE(SUM(points_change), 0)").
+			Where("agent_id = ?", author.id).Scan(&logSum).Error; err != nil {
+			t.Fatalf("sum points logs: %v", err)
+		}
+		var got model.Agent
+		if err := app.DB.First(&got, author.id).Error; err != nil {
+			t.Fatalf("reload author: %v", err)
+		}
+		if got.Points != authorBasePoints+logSum {
+			t.Fatalf("author points=%d, want %d+%d from points_logs", got.Points, authorBasePoints, logSum)
+		}
+	}
+}
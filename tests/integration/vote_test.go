@@ -0,0 +1,256 @@
+package integration_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"agent-hub/internal/model"
+	pointsService "agent-hub/internal/points/service"
+	"agent-hub/internal/testutil"
+)
+
+func TestVote_RetractAndToggleReversePoints(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+	agents := seedVoteAgents(t, app, 2)
+	author, voter := agents[0], agents[1]
+	// 初始积分避开 0 下限截断，便于逐步核对
+	const base = 100
+	if err := app.DB.Model(&model.Agent{}).Where("id = ?", author.id).Update("points", base).Error; err != nil {
+		t.Fatalf("set author points: %v", err)
+	}
+
+	post := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "retract me"}
+	if err := app.DB.Create(post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	path := "/api/v1/posts/" + strconv.FormatInt(post.ID, 10) + "/vote"
+
+	authorPoints := func() int {
+		t.Helper()
+		var a model.Agent
+		if err := app.DB.First(&a, author.id).Error; err != nil {
+			t.Fatalf("reload author: %v", err)
+		}
+		return a.Points
+	}
+	netVotes := func(rr map[string]any) int64 {
+		t.Helper()
+		return asInt64(t, rr["net_votes"])
+	}
+
+	// 赞 -> 踩 -> 赞：积分只反映当前这一票，不随切换累积
+	for _, step := range []struct {
+		voteType   int
+		wantNet    int64
+		wantPoints int
+	}{
+		{model.VoteTypeUpvote, 1, base + 1},
+		{model.VoteTypeDownvote, -1, base - 1},
+		{model.VoteTypeUpvote, 1, base + 1},
+	} {
+		rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": step.voteType}, voter.token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("vote %d status=%d body=%s", step.voteType, rr.Code, rr.Body.String())
+		}
+		if got := netVotes(decodeJSON(t, rr)); got != step.wantNet {
+			t.Fatalf("vote %d net_votes=%d, want %d", step.voteType, got, step.wantNet)
+		}
+		if got := authorPoints(); got != step.wantPoints {
+			t.Fatalf("after vote %d author points=%d, want %d", step.voteType, got, step.wantPoints)
+		}
+	}
+
+	// vote_type=0 撤销
+	{
+		rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": 0}, voter.token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("retract status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		if got := netVotes(decodeJSON(t, rr)); got != 0 {
+			t.Fatalf("retract net_votes=%d, want 0", got)
+		}
+		if got := authorPoints(); got != base {
+			t.Fatalf("after retract author points=%d, want %d", got, base)
+		}
+		var count int64
+		app.DB.Model(&model.Vote{}).Where("target_id = ? AND target_type = ?", post.ID, model.VoteTargetPost).Count(&count)
+		if count != 0 {
+			t.Fatalf("vote row not deleted, count=%d", count)
+		}
+		var reversals int64
+		app.DB.Model(&model.PointsLog{}).Where("agent_id = ? AND reason = ?", author.id, model.PointsReasonVoteReversed).Count(&reversals)
+		if reversals == 0 {
+			t.Fatal("expected vote_reversed compensating points logs")
+		}
+	}
+
+	// DELETE 撤销：再次撤销为幂等操作
+	{
+		rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": model.VoteTypeUpvote}, voter.token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("re-vote status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		for i := 0; i < 2; i++ {
+			rr = doJSON(t, app.Router, http.MethodDelete, path, nil, voter.token)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("delete vote status=%d body=%s", rr.Code, rr.Body.String())
+			}
+			if got := netVotes(decodeJSON(t, rr)); got != 0 {
+				t.Fatalf("delete vote net_votes=%d, want 0", got)
+			}
+		}
+		var got model.Post
+		if err := app.DB.First(&got, post.ID).Error; err != nil {
+			t.Fatalf("reload post: %v", err)
+		}
+		if got.Upvotes != 0 || got.Downvotes != 0 || got.NetVotes != 0 {
+			t.Fatalf("post counters up=%d down=%d net=%d, want all 0", got.Upvotes, got.Downvotes, got.NetVotes)
+		}
+	}
+
+	// 缺少 vote_type 仍为 400
+	{
+		rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{}, voter.token)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("missing vote_type status=%d body=%s", rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestVote_ToggleDoesNotExhaustDailyCap(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+	agents := seedVoteAgents(t, app, 3)
+	author, toggler, other := agents[0], agents[1], agents[2]
+	// 当日获赞额度只剩 1 分
+	if err := app.DB.Create(&model.PointsLog{
+		AgentID: author.id, PointsChange: pointsService.DailyCapContentUpvoted - 1, Reason: model.PointsReasonContentUpvoted,
+	}).Error; err != nil {
+		t.Fatalf("seed points log: %v", err)
+	}
+
+	post := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "toggle me"}
+	if err := app.DB.Create(post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	path := "/api/v1/posts/" + strconv.FormatInt(post.ID, 10) + "/vote"
+
+	// 反复赞同/撤销，每次撤销都把 1 分退回当日额度
+	for i := 0; i < 3; i++ {
+		for _, voteType := range []int{model.VoteTypeUpvote, model.VoteTypeNone} {
+			rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": voteType}, toggler.token)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("toggle %d status=%d body=%s", voteType, rr.Code, rr.Body.String())
+			}
+		}
+	}
+
+	rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": model.VoteTypeUpvote}, other.token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("other vote status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var v model.Vote
+	if err := app.DB.Where("agent_id = ? AND target_id = ? AND target_type = ?", other.id, post.ID, model.VoteTargetPost).First(&v).Error; err != nil {
+		t.Fatalf("load vote: %v", err)
+	}
+	if v.PointsAwarded != 1 {
+		t.Fatalf("other voter's upvote awarded %d, want 1", v.PointsAwarded)
+	}
+	var a model.Agent
+	if err := app.DB.First(&a, author.id).Error; err != nil {
+		t.Fatalf("reload author: %v", err)
+	}
+	if a.Points != 1 {
+		t.Fatalf("author points=%d, want 1", a.Points)
+	}
+}
+
+func TestVote_ClampedDownvoteRetractDoesNotMintPoints(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	var community model.Community
+	if err := app.DB.First(&community).Error; err != nil {
+		t.Fatalf("load community: %v", err)
+	}
+	agents := seedVoteAgents(t, app, 2)
+	author, voter := agents[0], agents[1]
+	post := &model.Post{AgentID: author.id, CommunityID: community.ID, Title: "zero balance"}
+	if err := app.DB.Create(post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	path := "/api/v1/posts/" + strconv.FormatInt(post.ID, 10) + "/vote"
+
+	// 作者积分为 0：反对票扣不动，撤销也不应返还
+	for _, voteType := range []int{model.VoteTypeDownvote, model.VoteTypeNone} {
+		rr := doJSON(t, app.Router, http.MethodPost, path, map[string]any{"vote_type": voteType}, voter.token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("vote %d status=%d body=%s", voteType, rr.Code, rr.Body.String())
+		}
+	}
+	var a model.Agent
+	if err := app.DB.First(&a, author.id).Error; err != nil {
+		t.Fatalf("reload author: %v", err)
+	}
+	if a.Points != 0 {
+		t.Fatalf("author points=%d, want 0", a.Points)
+	}
+	var logs int64
+	app.DB.Model(&model.PointsLog{}).Where("agent_id = ?", author.id).Count(&logs)
+	if logs != 0 {
+		t.Fatalf("author has %d points logs, want none", logs)
+	}
+}
+
+func TestVote_BackfillPointsAwarded(t *testing.T) {
+	app := testutil.NewMySQLTestApp(t)
+
+	agents := seedVoteAgents(t, app, 2)
+	// 模拟升级前的表结构与旧数据
+	if err := app.DB.Migrator().DropColumn(&model.Vote{}, "PointsAwarded"); err != nil {
+		t.Fatalf("drop column: %v", err)
+	}
+	for i, voteType := range []int{model.VoteTypeUpvote, model.VoteTypeDownvote} {
+		if err := app.DB.Exec(
+			"INSERT INTO votes (agent_id, target_id, target_type, vote_type, created_at) VALUES (?, ?, ?, ?, NOW())",
+			agents[i].id, 1, model.VoteTargetPost, voteType,
+		).Error; err != nil {
+			t.Fatalf("insert legacy vote: %v", err)
+		}
+	}
+
+	if err := model.AutoMigrate(app.DB); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	var votes []model.Vote
+	if err := app.DB.Order("id").Find(&votes).Error; err != nil {
+		t.Fatalf("load votes: %v", err)
+	}
+	if len(votes) != 2 || votes[0].PointsAwarded != 1 || votes[1].PointsAwarded != -1 {
+		t.Fatalf("backfilled votes = %+v, want points_awarded 1 and -1", votes)
+	}
+
+	// 列已存在时不再回填，未计分的新票保持 0
+	if err := app.DB.Model(&model.Vote{}).Where("id = ?", votes[0].ID).Update("points_awarded", 0).Error; err != nil {
+		t.Fatalf("reset vote: %v", err)
+	}
+	if err := model.AutoMigrate(app.DB); err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	var v model.Vote
+	if err := app.DB.First(&v, votes[0].ID).Error; err != nil {
+		t.Fatalf("reload vote: %v", err)
+	}
+	if v.PointsAwarded != 0 {
+		t.Fatalf("points_awarded=%d after second migrate, want 0", v.PointsAwarded)
+	}
+}